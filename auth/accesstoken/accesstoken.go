@@ -0,0 +1,46 @@
+package accesstoken
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+var ErrTokenNotFound = errors.New("access token not found")
+var ErrInvalidToken = errors.New("invalid access token")
+var ErrTokenExpired = errors.New("access token has expired")
+
+// AccessToken is a personal access token issued to a user.
+//
+// Token holds the hashed value; the plaintext token is only available once,
+// through NewAccessToken, when the token is created.
+type AccessToken struct {
+	ID          int64
+	TokenableID string
+	Name        string
+	Token       string
+	Abilities   []string
+	LastUsedAt  *time.Time
+	ExpiresAt   *time.Time
+	CreatedAt   time.Time
+}
+
+// Can reports whether the token has the given ability. The "*" ability grants everything.
+func (t *AccessToken) Can(ability string) bool {
+	return slices.Contains(t.Abilities, "*") || slices.Contains(t.Abilities, ability)
+}
+
+func (t *AccessToken) Cant(ability string) bool {
+	return !t.Can(ability)
+}
+
+func (t *AccessToken) Expired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+type NewAccessToken struct {
+	AccessToken *AccessToken
+
+	// The plaintext token in the form "<id>|<secret>". Show it to the user once; it cannot be recovered.
+	PlainTextToken string
+}
@@ -0,0 +1,100 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wolftotem4/golava-core/auth/accesstoken"
+)
+
+type MySQLTokenRepository struct {
+	DB    *sql.DB
+	Table string
+}
+
+func NewMySQLTokenRepository(db *sql.DB, table string) *MySQLTokenRepository {
+	return &MySQLTokenRepository{
+		DB:    db,
+		Table: table,
+	}
+}
+
+func (d *MySQLTokenRepository) Create(ctx context.Context, token *accesstoken.AccessToken) error {
+	abilities, err := accesstoken.EncodeAbilities(token.Abilities)
+	if err != nil {
+		return err
+	}
+
+	result, err := d.DB.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			"INSERT INTO `%s` (tokenable_id, name, token, abilities, last_used_at, expires_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			d.Table,
+		),
+		token.TokenableID, token.Name, token.Token, abilities, accesstoken.NullUnix(token.LastUsedAt), accesstoken.NullUnix(token.ExpiresAt), token.CreatedAt.Unix(),
+	)
+	if err != nil {
+		return err
+	}
+
+	token.ID, err = result.LastInsertId()
+	return err
+}
+
+func (d *MySQLTokenRepository) Find(ctx context.Context, id int64) (*accesstoken.AccessToken, error) {
+	row := d.DB.QueryRowContext(ctx, fmt.Sprintf(
+		"SELECT %s FROM `%s` WHERE id = ?", accesstoken.Columns, d.Table,
+	), id)
+
+	token, err := accesstoken.ScanAccessToken(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, accesstoken.ErrTokenNotFound
+	}
+	return token, err
+}
+
+func (d *MySQLTokenRepository) ListByTokenable(ctx context.Context, tokenableID string) ([]*accesstoken.AccessToken, error) {
+	rows, err := d.DB.QueryContext(ctx, fmt.Sprintf(
+		"SELECT %s FROM `%s` WHERE tokenable_id = ? ORDER BY id", accesstoken.Columns, d.Table,
+	), tokenableID)
+	if err != nil {
+		return nil, err
+	}
+
+	return accesstoken.ScanAccessTokens(rows)
+}
+
+func (d *MySQLTokenRepository) Touch(ctx context.Context, id int64, lastUsedAt time.Time) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		"UPDATE `%s` SET last_used_at = ? WHERE id = ?", d.Table,
+	), lastUsedAt.Unix(), id)
+	return err
+}
+
+func (d *MySQLTokenRepository) Delete(ctx context.Context, id int64) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		"DELETE FROM `%s` WHERE id = ?", d.Table,
+	), id)
+	return err
+}
+
+func (d *MySQLTokenRepository) DeleteByTokenable(ctx context.Context, tokenableID string) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		"DELETE FROM `%s` WHERE tokenable_id = ?", d.Table,
+	), tokenableID)
+	return err
+}
+
+func (d *MySQLTokenRepository) Prune(ctx context.Context, before time.Time) (int64, error) {
+	result, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		"DELETE FROM `%s` WHERE expires_at IS NOT NULL AND expires_at <= ?", d.Table,
+	), before.Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wolftotem4/golava-core/auth/accesstoken"
+)
+
+type PostgresTokenRepository struct {
+	DB    *sql.DB
+	Table string
+}
+
+func NewPostgresTokenRepository(db *sql.DB, table string) *PostgresTokenRepository {
+	return &PostgresTokenRepository{
+		DB:    db,
+		Table: table,
+	}
+}
+
+func (d *PostgresTokenRepository) Create(ctx context.Context, token *accesstoken.AccessToken) error {
+	abilities, err := accesstoken.EncodeAbilities(token.Abilities)
+	if err != nil {
+		return err
+	}
+
+	row := d.DB.QueryRowContext(
+		ctx,
+		fmt.Sprintf(
+			`INSERT INTO "%s" (tokenable_id, name, token, abilities, last_used_at, expires_at, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+			d.Table,
+		),
+		token.TokenableID, token.Name, token.Token, abilities, accesstoken.NullUnix(token.LastUsedAt), accesstoken.NullUnix(token.ExpiresAt), token.CreatedAt.Unix(),
+	)
+
+	return row.Scan(&token.ID)
+}
+
+func (d *PostgresTokenRepository) Find(ctx context.Context, id int64) (*accesstoken.AccessToken, error) {
+	row := d.DB.QueryRowContext(ctx, fmt.Sprintf(
+		`SELECT %s FROM "%s" WHERE id = $1`, accesstoken.Columns, d.Table,
+	), id)
+
+	token, err := accesstoken.ScanAccessToken(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, accesstoken.ErrTokenNotFound
+	}
+	return token, err
+}
+
+func (d *PostgresTokenRepository) ListByTokenable(ctx context.Context, tokenableID string) ([]*accesstoken.AccessToken, error) {
+	rows, err := d.DB.QueryContext(ctx, fmt.Sprintf(
+		`SELECT %s FROM "%s" WHERE tokenable_id = $1 ORDER BY id`, accesstoken.Columns, d.Table,
+	), tokenableID)
+	if err != nil {
+		return nil, err
+	}
+
+	return accesstoken.ScanAccessTokens(rows)
+}
+
+func (d *PostgresTokenRepository) Touch(ctx context.Context, id int64, lastUsedAt time.Time) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		`UPDATE "%s" SET last_used_at = $1 WHERE id = $2`, d.Table,
+	), lastUsedAt.Unix(), id)
+	return err
+}
+
+func (d *PostgresTokenRepository) Delete(ctx context.Context, id int64) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		`DELETE FROM "%s" WHERE id = $1`, d.Table,
+	), id)
+	return err
+}
+
+func (d *PostgresTokenRepository) DeleteByTokenable(ctx context.Context, tokenableID string) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		`DELETE FROM "%s" WHERE tokenable_id = $1`, d.Table,
+	), tokenableID)
+	return err
+}
+
+func (d *PostgresTokenRepository) Prune(ctx context.Context, before time.Time) (int64, error) {
+	result, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		`DELETE FROM "%s" WHERE expires_at IS NOT NULL AND expires_at <= $1`, d.Table,
+	), before.Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
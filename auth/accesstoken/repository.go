@@ -0,0 +1,18 @@
+package accesstoken
+
+import (
+	"context"
+	"time"
+)
+
+type TokenRepository interface {
+	// Create persists the token and assigns its ID.
+	Create(ctx context.Context, token *AccessToken) error
+	Find(ctx context.Context, id int64) (*AccessToken, error)
+	ListByTokenable(ctx context.Context, tokenableID string) ([]*AccessToken, error)
+	Touch(ctx context.Context, id int64, lastUsedAt time.Time) error
+	Delete(ctx context.Context, id int64) error
+	DeleteByTokenable(ctx context.Context, tokenableID string) error
+	// Prune deletes the tokens that expired before the given time.
+	Prune(ctx context.Context, before time.Time) (int64, error)
+}
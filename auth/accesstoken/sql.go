@@ -0,0 +1,93 @@
+package accesstoken
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// Columns lists the columns read by ScanAccessToken, in order.
+const Columns = "id, tokenable_id, name, token, abilities, last_used_at, expires_at, created_at"
+
+// Scanner is implemented by *sql.Row and *sql.Rows.
+type Scanner interface {
+	Scan(dest ...any) error
+}
+
+// ScanAccessToken reads a row selected with Columns.
+func ScanAccessToken(row Scanner) (*AccessToken, error) {
+	var (
+		token      AccessToken
+		abilities  string
+		lastUsedAt sql.NullInt64
+		expiresAt  sql.NullInt64
+		createdAt  int64
+	)
+
+	err := row.Scan(&token.ID, &token.TokenableID, &token.Name, &token.Token, &abilities, &lastUsedAt, &expiresAt, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+
+	token.Abilities, err = DecodeAbilities(abilities)
+	if err != nil {
+		return nil, err
+	}
+
+	token.LastUsedAt = fromNullUnix(lastUsedAt)
+	token.ExpiresAt = fromNullUnix(expiresAt)
+	token.CreatedAt = time.Unix(createdAt, 0)
+
+	return &token, nil
+}
+
+// ScanAccessTokens reads all rows selected with Columns.
+func ScanAccessTokens(rows *sql.Rows) ([]*AccessToken, error) {
+	defer rows.Close()
+
+	var tokens []*AccessToken
+	for rows.Next() {
+		token, err := ScanAccessToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, rows.Err()
+}
+
+func EncodeAbilities(abilities []string) (string, error) {
+	if abilities == nil {
+		abilities = []string{}
+	}
+
+	data, err := json.Marshal(abilities)
+	return string(data), err
+}
+
+func DecodeAbilities(value string) ([]string, error) {
+	if value == "" {
+		return []string{}, nil
+	}
+
+	var abilities []string
+	err := json.Unmarshal([]byte(value), &abilities)
+	return abilities, err
+}
+
+// NullUnix converts an optional time into a value suitable for a nullable integer column.
+func NullUnix(t *time.Time) sql.NullInt64 {
+	if t == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: t.Unix(), Valid: true}
+}
+
+func fromNullUnix(value sql.NullInt64) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	t := time.Unix(value.Int64, 0)
+	return &t
+}
@@ -0,0 +1,96 @@
+package sqlserver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wolftotem4/golava-core/auth/accesstoken"
+)
+
+type SQLServerTokenRepository struct {
+	DB    *sql.DB
+	Table string
+}
+
+func NewSQLServerTokenRepository(db *sql.DB, table string) *SQLServerTokenRepository {
+	return &SQLServerTokenRepository{
+		DB:    db,
+		Table: table,
+	}
+}
+
+func (d *SQLServerTokenRepository) Create(ctx context.Context, token *accesstoken.AccessToken) error {
+	abilities, err := accesstoken.EncodeAbilities(token.Abilities)
+	if err != nil {
+		return err
+	}
+
+	row := d.DB.QueryRowContext(
+		ctx,
+		fmt.Sprintf(
+			"INSERT INTO [%s] (tokenable_id, name, token, abilities, last_used_at, expires_at, created_at) OUTPUT INSERTED.id VALUES (@p1, @p2, @p3, @p4, @p5, @p6, @p7)",
+			d.Table,
+		),
+		token.TokenableID, token.Name, token.Token, abilities, accesstoken.NullUnix(token.LastUsedAt), accesstoken.NullUnix(token.ExpiresAt), token.CreatedAt.Unix(),
+	)
+
+	return row.Scan(&token.ID)
+}
+
+func (d *SQLServerTokenRepository) Find(ctx context.Context, id int64) (*accesstoken.AccessToken, error) {
+	row := d.DB.QueryRowContext(ctx, fmt.Sprintf(
+		"SELECT %s FROM [%s] WHERE id = @p1", accesstoken.Columns, d.Table,
+	), id)
+
+	token, err := accesstoken.ScanAccessToken(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, accesstoken.ErrTokenNotFound
+	}
+	return token, err
+}
+
+func (d *SQLServerTokenRepository) ListByTokenable(ctx context.Context, tokenableID string) ([]*accesstoken.AccessToken, error) {
+	rows, err := d.DB.QueryContext(ctx, fmt.Sprintf(
+		"SELECT %s FROM [%s] WHERE tokenable_id = @p1 ORDER BY id", accesstoken.Columns, d.Table,
+	), tokenableID)
+	if err != nil {
+		return nil, err
+	}
+
+	return accesstoken.ScanAccessTokens(rows)
+}
+
+func (d *SQLServerTokenRepository) Touch(ctx context.Context, id int64, lastUsedAt time.Time) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		"UPDATE [%s] SET last_used_at = @p1 WHERE id = @p2", d.Table,
+	), lastUsedAt.Unix(), id)
+	return err
+}
+
+func (d *SQLServerTokenRepository) Delete(ctx context.Context, id int64) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		"DELETE FROM [%s] WHERE id = @p1", d.Table,
+	), id)
+	return err
+}
+
+func (d *SQLServerTokenRepository) DeleteByTokenable(ctx context.Context, tokenableID string) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		"DELETE FROM [%s] WHERE tokenable_id = @p1", d.Table,
+	), tokenableID)
+	return err
+}
+
+func (d *SQLServerTokenRepository) Prune(ctx context.Context, before time.Time) (int64, error) {
+	result, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		"DELETE FROM [%s] WHERE expires_at IS NOT NULL AND expires_at <= @p1", d.Table,
+	), before.Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,133 @@
+package accesstoken
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wolftotem4/golava-core/auth"
+	"github.com/wolftotem4/golava-core/hashing"
+	"github.com/wolftotem4/golava-core/util"
+)
+
+type Tokens struct {
+	Repository TokenRepository
+
+	// Hash the token secrets with this hasher. SHA-256 is used when nil.
+	Hasher hashing.Hasher
+
+	// Default lifetime of new tokens. Tokens never expire when zero.
+	Expiration time.Duration
+}
+
+// Create issues a new token for the user. Tokens without abilities are granted "*".
+func (t *Tokens) Create(ctx context.Context, user auth.Authenticatable, name string, abilities []string, expiresAt *time.Time) (*NewAccessToken, error) {
+	secret := util.RandomString(40)
+
+	hash, err := t.hash(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(abilities) == 0 {
+		abilities = []string{"*"}
+	}
+
+	now := time.Now()
+	if expiresAt == nil && t.Expiration > 0 {
+		expires := now.Add(t.Expiration)
+		expiresAt = &expires
+	}
+
+	token := &AccessToken{
+		TokenableID: fmt.Sprintf("%v", user.GetAuthIdentifier()),
+		Name:        name,
+		Token:       hash,
+		Abilities:   abilities,
+		ExpiresAt:   expiresAt,
+		CreatedAt:   now,
+	}
+
+	err = t.Repository.Create(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NewAccessToken{
+		AccessToken:    token,
+		PlainTextToken: fmt.Sprintf("%d|%s", token.ID, secret),
+	}, nil
+}
+
+// Find returns the token matching the plaintext token, as long as it has not expired.
+func (t *Tokens) Find(ctx context.Context, plainTextToken string) (*AccessToken, error) {
+	id, secret, ok := strings.Cut(plainTextToken, "|")
+	if !ok || secret == "" {
+		return nil, ErrInvalidToken
+	}
+
+	tokenId, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	token, err := t.Repository.Find(ctx, tokenId)
+	if err != nil {
+		return nil, err
+	}
+
+	valid, err := t.check(secret, token.Token)
+	if err != nil {
+		return nil, err
+	} else if !valid {
+		return nil, ErrInvalidToken
+	}
+
+	if token.Expired(time.Now()) {
+		return nil, ErrTokenExpired
+	}
+
+	return token, nil
+}
+
+// Touch records that the token has just been used.
+func (t *Tokens) Touch(ctx context.Context, token *AccessToken) error {
+	now := time.Now()
+	token.LastUsedAt = &now
+	return t.Repository.Touch(ctx, token.ID, now)
+}
+
+func (t *Tokens) Revoke(ctx context.Context, token *AccessToken) error {
+	return t.Repository.Delete(ctx, token.ID)
+}
+
+func (t *Tokens) RevokeAll(ctx context.Context, user auth.Authenticatable) error {
+	return t.Repository.DeleteByTokenable(ctx, fmt.Sprintf("%v", user.GetAuthIdentifier()))
+}
+
+func (t *Tokens) List(ctx context.Context, user auth.Authenticatable) ([]*AccessToken, error) {
+	return t.Repository.ListByTokenable(ctx, fmt.Sprintf("%v", user.GetAuthIdentifier()))
+}
+
+func (t *Tokens) hash(secret string) (string, error) {
+	if t.Hasher != nil {
+		return t.Hasher.Make(secret)
+	}
+
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (t *Tokens) check(secret string, hashed string) (bool, error) {
+	if t.Hasher != nil {
+		return t.Hasher.Check(secret, hashed)
+	}
+
+	sum := sha256.Sum256([]byte(secret))
+	return subtle.ConstantTimeCompare([]byte(hex.EncodeToString(sum[:])), []byte(hashed)) == 1, nil
+}
@@ -0,0 +1,173 @@
+package accesstoken
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wolftotem4/golava-core/hashing"
+)
+
+type user struct {
+	id int
+}
+
+func (u *user) GetAuthIdentifierName() string { return "id" }
+func (u *user) GetAuthIdentifier() any        { return u.id }
+func (u *user) GetAuthPasswordName() string   { return "password" }
+func (u *user) GetAuthPassword() string       { return "" }
+func (u *user) GetRememberToken() string      { return "" }
+func (u *user) SetRememberToken(string)       {}
+func (u *user) GetRememberTokenName() string  { return "remember_token" }
+
+type memoryRepository struct {
+	tokens map[int64]*AccessToken
+	nextId int64
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{tokens: make(map[int64]*AccessToken)}
+}
+
+func (r *memoryRepository) Create(ctx context.Context, token *AccessToken) error {
+	r.nextId++
+	token.ID = r.nextId
+	copied := *token
+	r.tokens[token.ID] = &copied
+	return nil
+}
+
+func (r *memoryRepository) Find(ctx context.Context, id int64) (*AccessToken, error) {
+	token, ok := r.tokens[id]
+	if !ok {
+		return nil, ErrTokenNotFound
+	}
+	copied := *token
+	return &copied, nil
+}
+
+func (r *memoryRepository) ListByTokenable(ctx context.Context, tokenableID string) ([]*AccessToken, error) {
+	var tokens []*AccessToken
+	for _, token := range r.tokens {
+		if token.TokenableID == tokenableID {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
+
+func (r *memoryRepository) Touch(ctx context.Context, id int64, lastUsedAt time.Time) error {
+	r.tokens[id].LastUsedAt = &lastUsedAt
+	return nil
+}
+
+func (r *memoryRepository) Delete(ctx context.Context, id int64) error {
+	delete(r.tokens, id)
+	return nil
+}
+
+func (r *memoryRepository) DeleteByTokenable(ctx context.Context, tokenableID string) error {
+	for id, token := range r.tokens {
+		if token.TokenableID == tokenableID {
+			delete(r.tokens, id)
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepository) Prune(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestTokens_CreateAndFind(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryRepository()
+	tokens := &Tokens{Repository: repo}
+	owner := &user{id: 7}
+
+	newToken, err := tokens.Create(ctx, owner, "cli", []string{"orders:read"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(newToken.PlainTextToken, "1|") {
+		t.Errorf("unexpected plaintext token %s", newToken.PlainTextToken)
+	}
+
+	stored := repo.tokens[newToken.AccessToken.ID]
+	if strings.Contains(newToken.PlainTextToken, stored.Token) {
+		t.Error("plaintext token must not be stored")
+	}
+
+	if stored.TokenableID != "7" {
+		t.Errorf("expected tokenable id 7, got %s", stored.TokenableID)
+	}
+
+	token, err := tokens.Find(ctx, newToken.PlainTextToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !token.Can("orders:read") || token.Can("orders:write") {
+		t.Error("unexpected abilities")
+	}
+
+	_, err = tokens.Find(ctx, "1|wrong")
+	if !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken, got %v", err)
+	}
+
+	_, err = tokens.Find(ctx, "2|wrong")
+	if !errors.Is(err, ErrTokenNotFound) {
+		t.Errorf("expected ErrTokenNotFound, got %v", err)
+	}
+
+	_, err = tokens.Find(ctx, "malformed")
+	if !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken, got %v", err)
+	}
+}
+
+func TestTokens_Expiration(t *testing.T) {
+	ctx := context.Background()
+	tokens := &Tokens{Repository: newMemoryRepository()}
+	owner := &user{id: 1}
+
+	expired := time.Now().Add(-time.Minute)
+	newToken, err := tokens.Create(ctx, owner, "old", nil, &expired)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !newToken.AccessToken.Can("anything") {
+		t.Error("expected token without abilities to be granted *")
+	}
+
+	_, err = tokens.Find(ctx, newToken.PlainTextToken)
+	if !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("expected ErrTokenExpired, got %v", err)
+	}
+}
+
+func TestTokens_Hasher(t *testing.T) {
+	ctx := context.Background()
+	tokens := &Tokens{
+		Repository: newMemoryRepository(),
+		Hasher:     &hashing.Md5Hasher{},
+	}
+
+	newToken, err := tokens.Create(ctx, &user{id: 1}, "md5", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(newToken.AccessToken.Token, hashing.Md5Prefix) {
+		t.Errorf("expected token to be hashed with the given hasher, got %s", newToken.AccessToken.Token)
+	}
+
+	if _, err := tokens.Find(ctx, newToken.PlainTextToken); err != nil {
+		t.Fatal(err)
+	}
+}
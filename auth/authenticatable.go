@@ -9,3 +9,18 @@ type Authenticatable interface {
 	SetRememberToken(string)
 	GetRememberTokenName() string
 }
+
+// TwoFactorAuthenticatable users with two-factor authentication enabled must
+// complete a second step after their password is validated.
+type TwoFactorAuthenticatable interface {
+	Authenticatable
+	HasTwoFactorEnabled() bool
+}
+
+// MustVerifyEmail users must verify their email address before accessing
+// routes guarded by the EnsureEmailIsVerified middleware.
+type MustVerifyEmail interface {
+	Authenticatable
+	HasVerifiedEmail() bool
+	GetEmailForVerification() string
+}
@@ -0,0 +1,84 @@
+package callback
+
+import (
+	"context"
+
+	"github.com/wolftotem4/golava-core/auth"
+)
+
+type multi []Callbacks
+
+// Multi dispatches each event to all the listeners in order, stopping at the first error.
+// Nil listeners are skipped.
+func Multi(listeners ...Callbacks) Callbacks {
+	m := make(multi, 0, len(listeners))
+	for _, listener := range listeners {
+		if listener != nil {
+			m = append(m, listener)
+		}
+	}
+	return m
+}
+
+func (m multi) Attempting(ctx context.Context, name string, credentials map[string]any, remember bool) error {
+	for _, listener := range m {
+		if err := listener.Attempting(ctx, name, credentials, remember); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m multi) Validated(ctx context.Context, name string, user auth.Authenticatable) error {
+	for _, listener := range m {
+		if err := listener.Validated(ctx, name, user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m multi) Login(ctx context.Context, name string, user auth.Authenticatable, remember bool) error {
+	for _, listener := range m {
+		if err := listener.Login(ctx, name, user, remember); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m multi) Authenticated(ctx context.Context, name string, user auth.Authenticatable) error {
+	for _, listener := range m {
+		if err := listener.Authenticated(ctx, name, user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m multi) CurrentDeviceLogout(ctx context.Context, name string, user auth.Authenticatable) error {
+	for _, listener := range m {
+		if err := listener.CurrentDeviceLogout(ctx, name, user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m multi) OtherDeviceLogout(ctx context.Context, name string, user auth.Authenticatable) error {
+	for _, listener := range m {
+		if err := listener.OtherDeviceLogout(ctx, name, user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m multi) Failed(ctx context.Context, name string, user auth.Authenticatable) error {
+	for _, listener := range m {
+		if err := listener.Failed(ctx, name, user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
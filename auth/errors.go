@@ -5,3 +5,7 @@ import "errors"
 var ErrUserNotFound = errors.New("user not found")
 var ErrUnauthenticated = errors.New("unauthenticated")
 var ErrPasswordMismatch = errors.New("the given password does not match the current password")
+var ErrMissingAbility = errors.New("the access token does not have the required ability")
+var ErrNoPendingTwoFactor = errors.New("no pending two-factor authentication")
+var ErrEmailNotVerified = errors.New("your email address is not verified")
+var ErrGuardNotDefined = errors.New("auth guard is not defined")
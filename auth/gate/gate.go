@@ -0,0 +1,175 @@
+package gate
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/wolftotem4/golava-core/auth"
+)
+
+// Ability decides whether the user may perform an action. Guests are denied
+// before abilities are called, so user is never nil.
+type Ability func(ctx context.Context, user auth.Authenticatable, args ...any) (Response, error)
+
+// BeforeHook runs before every check. A decided response, e.g. Allow() for
+// super-admins, skips the ability. Return Abstain to continue.
+type BeforeHook func(ctx context.Context, user auth.Authenticatable, ability string, args ...any) (Response, error)
+
+// AfterHook runs after every check and may override the result by returning a
+// decided response. Return Abstain to keep it.
+type AfterHook func(ctx context.Context, user auth.Authenticatable, ability string, result Response, args ...any) (Response, error)
+
+type Gate struct {
+	abilities map[string]Ability
+	policies  map[reflect.Type]any
+	before    []BeforeHook
+	after     []AfterHook
+}
+
+func New() *Gate {
+	return &Gate{
+		abilities: make(map[string]Ability),
+		policies:  make(map[reflect.Type]any),
+	}
+}
+
+func (g *Gate) Define(ability string, fn Ability) {
+	g.abilities[ability] = fn
+}
+
+func (g *Gate) Has(ability string) bool {
+	_, ok := g.abilities[ability]
+	return ok
+}
+
+// Policy registers the policy of a resource type. Pass a value of the resource type,
+// e.g. g.Policy((*Post)(nil), &PostPolicy{}).
+//
+// Checks whose first argument is of that type are handled by the policy, unless
+// an ability of the same name has been defined.
+func (g *Gate) Policy(resource any, policy any) {
+	g.policies[reflect.TypeOf(resource)] = policy
+}
+
+func (g *Gate) GetPolicyFor(resource any) (any, bool) {
+	if resource == nil {
+		return nil, false
+	}
+
+	policy, ok := g.policies[reflect.TypeOf(resource)]
+	return policy, ok
+}
+
+func (g *Gate) Before(hook BeforeHook) {
+	g.before = append(g.before, hook)
+}
+
+func (g *Gate) After(hook AfterHook) {
+	g.after = append(g.after, hook)
+}
+
+// Inspect returns the full response of the check, including its message.
+func (g *Gate) Inspect(ctx context.Context, user auth.Authenticatable, ability string, args ...any) (Response, error) {
+	if user == nil {
+		return Deny(), nil
+	}
+
+	result, err := g.callBefore(ctx, user, ability, args)
+	if err != nil {
+		return Deny(), err
+	}
+
+	if !result.Decided() {
+		result, err = g.callAbility(ctx, user, ability, args)
+		if err != nil {
+			return Deny(), err
+		}
+	}
+
+	for _, hook := range g.after {
+		override, err := hook(ctx, user, ability, result, args...)
+		if err != nil {
+			return Deny(), err
+		}
+		if override.Decided() {
+			result = override
+		}
+	}
+
+	if !result.Decided() {
+		return Deny(), nil
+	}
+
+	return result, nil
+}
+
+func (g *Gate) Allows(ctx context.Context, user auth.Authenticatable, ability string, args ...any) (bool, error) {
+	result, err := g.Inspect(ctx, user, ability, args...)
+	return result.Allowed(), err
+}
+
+func (g *Gate) Denies(ctx context.Context, user auth.Authenticatable, ability string, args ...any) (bool, error) {
+	allowed, err := g.Allows(ctx, user, ability, args...)
+	return !allowed, err
+}
+
+// Authorize returns an *AuthorizationError when the check fails.
+func (g *Gate) Authorize(ctx context.Context, user auth.Authenticatable, ability string, args ...any) error {
+	result, err := g.Inspect(ctx, user, ability, args...)
+	if err != nil {
+		return err
+	}
+
+	return result.Err()
+}
+
+// ForUser binds the gate to a user, e.g. the one of the current request.
+func (g *Gate) ForUser(user auth.Authenticatable) *UserGate {
+	return &UserGate{gate: g, user: user}
+}
+
+func (g *Gate) callBefore(ctx context.Context, user auth.Authenticatable, ability string, args []any) (Response, error) {
+	for _, hook := range g.before {
+		result, err := hook(ctx, user, ability, args...)
+		if err != nil || result.Decided() {
+			return result, err
+		}
+	}
+
+	return Abstain(), nil
+}
+
+func (g *Gate) callAbility(ctx context.Context, user auth.Authenticatable, ability string, args []any) (Response, error) {
+	if fn, ok := g.abilities[ability]; ok {
+		return fn(ctx, user, args...)
+	}
+
+	if len(args) > 0 {
+		if policy, ok := g.GetPolicyFor(args[0]); ok {
+			return callPolicy(ctx, policy, ability, user, args[0])
+		}
+	}
+
+	return Abstain(), nil
+}
+
+type UserGate struct {
+	gate *Gate
+	user auth.Authenticatable
+}
+
+func (u *UserGate) Inspect(ctx context.Context, ability string, args ...any) (Response, error) {
+	return u.gate.Inspect(ctx, u.user, ability, args...)
+}
+
+func (u *UserGate) Allows(ctx context.Context, ability string, args ...any) (bool, error) {
+	return u.gate.Allows(ctx, u.user, ability, args...)
+}
+
+func (u *UserGate) Denies(ctx context.Context, ability string, args ...any) (bool, error) {
+	return u.gate.Denies(ctx, u.user, ability, args...)
+}
+
+func (u *UserGate) Authorize(ctx context.Context, ability string, args ...any) error {
+	return u.gate.Authorize(ctx, u.user, ability, args...)
+}
@@ -0,0 +1,137 @@
+package gate
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/wolftotem4/golava-core/auth"
+)
+
+type user struct {
+	id    int
+	admin bool
+}
+
+func (u *user) GetAuthIdentifierName() string { return "id" }
+func (u *user) GetAuthIdentifier() any        { return u.id }
+func (u *user) GetAuthPasswordName() string   { return "password" }
+func (u *user) GetAuthPassword() string       { return "" }
+func (u *user) GetRememberToken() string      { return "" }
+func (u *user) SetRememberToken(string)       {}
+func (u *user) GetRememberTokenName() string  { return "remember_token" }
+
+type post struct {
+	authorId int
+}
+
+type postPolicy struct{}
+
+func (p *postPolicy) View(ctx context.Context, u auth.Authenticatable, resource any) (Response, error) {
+	return Allow(), nil
+}
+
+func (p *postPolicy) Update(ctx context.Context, u auth.Authenticatable, resource any) (Response, error) {
+	return Result(resource.(*post).authorId == u.GetAuthIdentifier(), "You do not own this post."), nil
+}
+
+func (p *postPolicy) Delete(ctx context.Context, u auth.Authenticatable, resource any) (Response, error) {
+	return DenyWithStatus(http.StatusNotFound), nil
+}
+
+func TestGate_Define(t *testing.T) {
+	ctx := context.Background()
+	g := New()
+	g.Define("edit-settings", func(ctx context.Context, u auth.Authenticatable, args ...any) (Response, error) {
+		return Result(u.(*user).admin), nil
+	})
+
+	if allowed, _ := g.Allows(ctx, &user{id: 1, admin: true}, "edit-settings"); !allowed {
+		t.Error("expected admin to be allowed")
+	}
+
+	if allowed, _ := g.Allows(ctx, &user{id: 2}, "edit-settings"); allowed {
+		t.Error("expected user to be denied")
+	}
+
+	if allowed, _ := g.Allows(ctx, nil, "edit-settings"); allowed {
+		t.Error("expected guest to be denied")
+	}
+
+	if allowed, _ := g.Allows(ctx, &user{id: 1, admin: true}, "undefined"); allowed {
+		t.Error("expected undefined ability to be denied")
+	}
+}
+
+func TestGate_Policy(t *testing.T) {
+	ctx := context.Background()
+	g := New()
+	g.Policy((*post)(nil), &postPolicy{})
+
+	author := &user{id: 1}
+	other := &user{id: 2}
+	p := &post{authorId: 1}
+
+	if allowed, _ := g.Allows(ctx, other, "view", p); !allowed {
+		t.Error("expected view to be allowed")
+	}
+
+	if allowed, _ := g.Allows(ctx, author, "update", p); !allowed {
+		t.Error("expected author to update")
+	}
+
+	err := g.Authorize(ctx, other, "update", p)
+	var authErr *AuthorizationError
+	if !errors.As(err, &authErr) || authErr.Message != "You do not own this post." || authErr.Code != http.StatusForbidden {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if !errors.Is(err, ErrAccessDenied) {
+		t.Error("expected error to match ErrAccessDenied")
+	}
+
+	err = g.Authorize(ctx, author, "delete", p)
+	if !errors.As(err, &authErr) || authErr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 denial, got %v", err)
+	}
+
+	if allowed, _ := g.Allows(ctx, author, "create", (*post)(nil)); allowed {
+		t.Error("expected unimplemented ability to be denied")
+	}
+}
+
+func TestGate_Hooks(t *testing.T) {
+	ctx := context.Background()
+	g := New()
+	g.Policy((*post)(nil), &postPolicy{})
+
+	g.Before(func(ctx context.Context, u auth.Authenticatable, ability string, args ...any) (Response, error) {
+		if u.(*user).admin {
+			return Allow(), nil
+		}
+		return Abstain(), nil
+	})
+
+	g.After(func(ctx context.Context, u auth.Authenticatable, ability string, result Response, args ...any) (Response, error) {
+		if ability == "view" && u.GetAuthIdentifier() == 3 {
+			return Deny("banned"), nil
+		}
+		return Abstain(), nil
+	})
+
+	p := &post{authorId: 1}
+
+	if allowed, _ := g.Allows(ctx, &user{id: 9, admin: true}, "delete", p); !allowed {
+		t.Error("expected super-admin to bypass the policy")
+	}
+
+	result, _ := g.Inspect(ctx, &user{id: 3}, "view", p)
+	if result.Allowed() || result.Message != "banned" {
+		t.Errorf("expected after hook to override, got %+v", result)
+	}
+
+	if allowed, _ := g.ForUser(&user{id: 1}).Allows(ctx, "update", p); !allowed {
+		t.Error("expected user gate to allow author")
+	}
+}
@@ -0,0 +1,70 @@
+package gate
+
+import (
+	"context"
+
+	"github.com/wolftotem4/golava-core/auth"
+)
+
+// A policy groups the authorization logic of a resource type. Implement any of
+// the interfaces below; abilities a policy does not implement are denied.
+
+type ViewPolicy interface {
+	View(ctx context.Context, user auth.Authenticatable, resource any) (Response, error)
+}
+
+type CreatePolicy interface {
+	Create(ctx context.Context, user auth.Authenticatable) (Response, error)
+}
+
+type UpdatePolicy interface {
+	Update(ctx context.Context, user auth.Authenticatable, resource any) (Response, error)
+}
+
+type DeletePolicy interface {
+	Delete(ctx context.Context, user auth.Authenticatable, resource any) (Response, error)
+}
+
+// AbilityPolicy handles the abilities not covered by the interfaces above.
+type AbilityPolicy interface {
+	Authorize(ctx context.Context, ability string, user auth.Authenticatable, resource any) (Response, error)
+}
+
+// BeforePolicy runs before any ability of the policy. Return Abstain to continue.
+type BeforePolicy interface {
+	Before(ctx context.Context, ability string, user auth.Authenticatable) (Response, error)
+}
+
+func callPolicy(ctx context.Context, policy any, ability string, user auth.Authenticatable, resource any) (Response, error) {
+	if before, ok := policy.(BeforePolicy); ok {
+		response, err := before.Before(ctx, ability, user)
+		if err != nil || response.Decided() {
+			return response, err
+		}
+	}
+
+	switch ability {
+	case "view":
+		if p, ok := policy.(ViewPolicy); ok {
+			return p.View(ctx, user, resource)
+		}
+	case "create":
+		if p, ok := policy.(CreatePolicy); ok {
+			return p.Create(ctx, user)
+		}
+	case "update":
+		if p, ok := policy.(UpdatePolicy); ok {
+			return p.Update(ctx, user, resource)
+		}
+	case "delete":
+		if p, ok := policy.(DeletePolicy); ok {
+			return p.Delete(ctx, user, resource)
+		}
+	}
+
+	if p, ok := policy.(AbilityPolicy); ok {
+		return p.Authorize(ctx, ability, user, resource)
+	}
+
+	return Abstain(), nil
+}
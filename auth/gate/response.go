@@ -0,0 +1,94 @@
+package gate
+
+import (
+	"errors"
+	"net/http"
+)
+
+var ErrAccessDenied = errors.New("this action is unauthorized")
+
+// Response is the outcome of an authorization check.
+//
+// The zero value abstains: before hooks return it to let the check continue,
+// and an ability or policy returning it denies access.
+type Response struct {
+	decided bool
+	allowed bool
+	Message string
+	Code    int
+}
+
+func Allow(message ...string) Response {
+	return Response{decided: true, allowed: true, Message: first(message)}
+}
+
+func Deny(message ...string) Response {
+	return Response{decided: true, allowed: false, Message: first(message)}
+}
+
+// DenyWithStatus denies access with a custom HTTP status, e.g. http.StatusNotFound to hide the resource.
+func DenyWithStatus(code int, message ...string) Response {
+	return Response{decided: true, allowed: false, Message: first(message), Code: code}
+}
+
+// Result allows access when allowed is true, and denies it otherwise.
+func Result(allowed bool, message ...string) Response {
+	if allowed {
+		return Allow()
+	}
+	return Deny(message...)
+}
+
+func Abstain() Response {
+	return Response{}
+}
+
+func (r Response) Allowed() bool {
+	return r.decided && r.allowed
+}
+
+func (r Response) Denied() bool {
+	return !r.Allowed()
+}
+
+func (r Response) Decided() bool {
+	return r.decided
+}
+
+// Err returns nil when access is allowed, and an *AuthorizationError otherwise.
+func (r Response) Err() error {
+	if r.Allowed() {
+		return nil
+	}
+
+	code := r.Code
+	if code == 0 {
+		code = http.StatusForbidden
+	}
+
+	return &AuthorizationError{Message: r.Message, Code: code}
+}
+
+// AuthorizationError matches ErrAccessDenied with errors.Is.
+type AuthorizationError struct {
+	Message string
+	Code    int
+}
+
+func (e *AuthorizationError) Error() string {
+	if e.Message == "" {
+		return ErrAccessDenied.Error()
+	}
+	return e.Message
+}
+
+func (e *AuthorizationError) Is(target error) bool {
+	return target == ErrAccessDenied
+}
+
+func first(values []string) string {
+	if len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,175 @@
+package generic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/wolftotem4/golava-core/auth"
+	"github.com/wolftotem4/golava-core/auth/accesstoken"
+	"github.com/wolftotem4/golava-core/auth/callback"
+)
+
+// AccessTokenGuard authenticates requests by a personal access token sent as a bearer token.
+type AccessTokenGuard struct {
+	Name     string
+	Request  *http.Request
+	Tokens   *accesstoken.Tokens
+	Provider auth.UserProvider
+
+	// Convert the token owner's ID back to the type expected by Provider.RetrieveById.
+	IdMorph auth.RecallerIdMorph
+
+	// Listen to auth events.
+	//
+	// See SessionGuard.Callbacks.
+	Callbacks callback.Callbacks
+
+	user  auth.Authenticatable
+	token *accesstoken.AccessToken
+}
+
+func (ag *AccessTokenGuard) User() auth.Authenticatable {
+	return ag.user
+}
+
+func (ag *AccessTokenGuard) SetUser(user auth.Authenticatable) error {
+	return ag.setUser(context.TODO(), user)
+}
+
+func (ag *AccessTokenGuard) setUser(ctx context.Context, user auth.Authenticatable) error {
+	ag.user = user
+
+	if user == nil {
+		ag.token = nil
+		return nil
+	}
+
+	if ag.Callbacks != nil {
+		return ag.Callbacks.Authenticated(ctx, ag.Name, user)
+	}
+
+	return nil
+}
+
+// CurrentAccessToken returns the token the user authenticated with.
+func (ag *AccessTokenGuard) CurrentAccessToken() *accesstoken.AccessToken {
+	return ag.token
+}
+
+// WithAccessToken sets the token the current user is acting with.
+func (ag *AccessTokenGuard) WithAccessToken(token *accesstoken.AccessToken) {
+	ag.token = token
+}
+
+func (ag *AccessTokenGuard) TokenCan(ability string) bool {
+	return ag.token != nil && ag.token.Can(ability)
+}
+
+func (ag *AccessTokenGuard) ID() any {
+	if ag.user == nil {
+		return nil
+	}
+
+	return ag.user.GetAuthIdentifier()
+}
+
+func (ag *AccessTokenGuard) Check() bool {
+	return ag.user != nil
+}
+
+func (ag *AccessTokenGuard) HasUser() bool {
+	return ag.user != nil
+}
+
+func (ag *AccessTokenGuard) Guest() bool {
+	return !ag.Check()
+}
+
+// Validate the plaintext token found in credentials under "token".
+func (ag *AccessTokenGuard) Validate(ctx context.Context, credentials map[string]any) (bool, error) {
+	plainTextToken, _ := credentials["token"].(string)
+	if plainTextToken == "" {
+		return false, nil
+	}
+
+	user, _, err := ag.retrieve(ctx, plainTextToken)
+	if err != nil || user == nil {
+		return false, err
+	}
+
+	if ag.Callbacks != nil {
+		err := ag.Callbacks.Validated(ctx, ag.Name, user)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// RestoreAuth authenticates the user from the bearer token of the current request
+// and records the token as used.
+func (ag *AccessTokenGuard) RestoreAuth(ctx context.Context) error {
+	if ag.Request == nil {
+		return nil
+	}
+
+	plainTextToken := BearerToken(ag.Request)
+	if plainTextToken == "" {
+		return nil
+	}
+
+	user, token, err := ag.retrieve(ctx, plainTextToken)
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		if ag.Callbacks != nil {
+			return ag.Callbacks.Failed(ctx, ag.Name, nil)
+		}
+		return nil
+	}
+
+	err = ag.Tokens.Touch(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	ag.token = token
+	return ag.setUser(ctx, user)
+}
+
+func (ag *AccessTokenGuard) retrieve(ctx context.Context, plainTextToken string) (auth.Authenticatable, *accesstoken.AccessToken, error) {
+	token, err := ag.Tokens.Find(ctx, plainTextToken)
+	if isInvalidAccessToken(err) {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	var id any = token.TokenableID
+	if ag.IdMorph != nil {
+		id, err = ag.IdMorph(token.TokenableID)
+		if err != nil {
+			// ignore error
+			return nil, nil, nil
+		}
+	}
+
+	user, err := ag.Provider.RetrieveById(ctx, id)
+	if errors.Is(err, auth.ErrUserNotFound) {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	return user, token, nil
+}
+
+func isInvalidAccessToken(err error) bool {
+	return errors.Is(err, accesstoken.ErrTokenNotFound) ||
+		errors.Is(err, accesstoken.ErrInvalidToken) ||
+		errors.Is(err, accesstoken.ErrTokenExpired)
+}
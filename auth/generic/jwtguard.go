@@ -0,0 +1,250 @@
+package generic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/wolftotem4/golava-core/auth"
+	"github.com/wolftotem4/golava-core/auth/callback"
+	"github.com/wolftotem4/golava-core/auth/jwt"
+)
+
+// JWTGuard authenticates requests by a signed JWT sent as a bearer token.
+// It keeps no server-side state apart from the optional revocation list.
+type JWTGuard struct {
+	Name     string
+	Request  *http.Request
+	JWT      *jwt.JWT
+	Provider auth.UserProvider
+
+	// Convert the "sub" claim back to the type expected by Provider.RetrieveById.
+	IdMorph auth.RecallerIdMorph
+
+	// Listen to auth events.
+	//
+	// See SessionGuard.Callbacks.
+	Callbacks callback.Callbacks
+
+	user   auth.Authenticatable
+	claims *jwt.Claims
+}
+
+func (jg *JWTGuard) User() auth.Authenticatable {
+	return jg.user
+}
+
+func (jg *JWTGuard) SetUser(user auth.Authenticatable) error {
+	return jg.setUser(context.TODO(), user)
+}
+
+func (jg *JWTGuard) setUser(ctx context.Context, user auth.Authenticatable) error {
+	jg.user = user
+
+	if user == nil {
+		jg.claims = nil
+		return nil
+	}
+
+	if jg.Callbacks != nil {
+		return jg.Callbacks.Authenticated(ctx, jg.Name, user)
+	}
+
+	return nil
+}
+
+// Claims returns the claims of the access token the user authenticated with.
+func (jg *JWTGuard) Claims() *jwt.Claims {
+	return jg.claims
+}
+
+func (jg *JWTGuard) ID() any {
+	if jg.user == nil {
+		return nil
+	}
+
+	return jg.user.GetAuthIdentifier()
+}
+
+func (jg *JWTGuard) Check() bool {
+	return jg.user != nil
+}
+
+func (jg *JWTGuard) HasUser() bool {
+	return jg.user != nil
+}
+
+func (jg *JWTGuard) Guest() bool {
+	return !jg.Check()
+}
+
+func (jg *JWTGuard) Validate(ctx context.Context, credentials map[string]any) (bool, error) {
+	_, valid, err := jg.validate(ctx, credentials)
+	return valid, err
+}
+
+func (jg *JWTGuard) validate(ctx context.Context, credentials map[string]any) (auth.Authenticatable, bool, error) {
+	user, err := jg.Provider.RetrieveByCredentials(ctx, credentials)
+	if errors.Is(err, auth.ErrUserNotFound) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	valid, err := jg.Provider.ValidateCredentials(ctx, user, credentials)
+	if err != nil || !valid {
+		return user, false, err
+	}
+
+	if jg.Callbacks != nil {
+		err := jg.Callbacks.Validated(ctx, jg.Name, user)
+		if err != nil {
+			return user, false, err
+		}
+	}
+
+	return user, true, nil
+}
+
+// Attempt validates the credentials and issues a token pair on success.
+// A nil pair is returned when the credentials are invalid.
+func (jg *JWTGuard) Attempt(ctx context.Context, credentials map[string]any) (*jwt.TokenPair, error) {
+	if jg.Callbacks != nil {
+		err := jg.Callbacks.Attempting(ctx, jg.Name, credentials, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	user, valid, err := jg.validate(ctx, credentials)
+	if err != nil {
+		return nil, err
+	} else if !valid {
+		if jg.Callbacks != nil {
+			return nil, jg.Callbacks.Failed(ctx, jg.Name, user)
+		}
+		return nil, nil
+	}
+
+	_, err = jg.Provider.RehashPasswordIfRequired(ctx, user, credentials, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return jg.Login(ctx, user)
+}
+
+// Login issues a token pair for the user and authenticates the current request as them.
+func (jg *JWTGuard) Login(ctx context.Context, user auth.Authenticatable) (*jwt.TokenPair, error) {
+	pair, err := jg.JWT.IssuePair(fmt.Sprintf("%v", user.GetAuthIdentifier()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if jg.Callbacks != nil {
+		err := jg.Callbacks.Login(ctx, jg.Name, user, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return pair, jg.setUser(ctx, user)
+}
+
+// Refresh exchanges a refresh token for a new token pair, as long as its user still exists.
+func (jg *JWTGuard) Refresh(ctx context.Context, refreshToken string) (*jwt.TokenPair, error) {
+	claims, err := jg.JWT.ParseType(ctx, refreshToken, jwt.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := jg.retrieveBySubject(ctx, claims.Subject)
+	if err != nil {
+		return nil, err
+	} else if user == nil {
+		return nil, auth.ErrUserNotFound
+	}
+
+	pair, _, err := jg.JWT.Refresh(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return pair, jg.setUser(ctx, user)
+}
+
+// Logout revokes the current access token and its refresh token.
+func (jg *JWTGuard) Logout(ctx context.Context) error {
+	user := jg.user
+
+	if jg.claims != nil {
+		err := jg.JWT.Revoke(ctx, jg.claims)
+		if err != nil {
+			return err
+		}
+	}
+
+	if user != nil && jg.Callbacks != nil {
+		err := jg.Callbacks.CurrentDeviceLogout(ctx, jg.Name, user)
+		if err != nil {
+			return err
+		}
+	}
+
+	return jg.setUser(ctx, nil)
+}
+
+// RestoreAuth authenticates the user from the bearer token of the current request.
+func (jg *JWTGuard) RestoreAuth(ctx context.Context) error {
+	if jg.Request == nil {
+		return nil
+	}
+
+	token := BearerToken(jg.Request)
+	if token == "" {
+		return nil
+	}
+
+	claims, err := jg.JWT.ParseType(ctx, token, jwt.AccessToken)
+	if err != nil && !jwt.IsValidationError(err) {
+		return err
+	} else if err != nil {
+		// invalid tokens leave the request unauthenticated
+		if jg.Callbacks != nil {
+			return jg.Callbacks.Failed(ctx, jg.Name, nil)
+		}
+		return nil
+	}
+
+	user, err := jg.retrieveBySubject(ctx, claims.Subject)
+	if err != nil || user == nil {
+		return err
+	}
+
+	err = jg.setUser(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	jg.claims = claims
+	return nil
+}
+
+func (jg *JWTGuard) retrieveBySubject(ctx context.Context, subject string) (auth.Authenticatable, error) {
+	var id any = subject
+	if jg.IdMorph != nil {
+		var err error
+		id, err = jg.IdMorph(subject)
+		if err != nil {
+			// ignore error
+			return nil, nil
+		}
+	}
+
+	user, err := jg.Provider.RetrieveById(ctx, id)
+	if errors.Is(err, auth.ErrUserNotFound) {
+		return nil, nil
+	}
+	return user, err
+}
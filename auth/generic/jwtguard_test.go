@@ -0,0 +1,65 @@
+package generic
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/wolftotem4/golava-core/auth/jwt"
+)
+
+type unavailableRevocationList struct {
+	jwt.RevocationList
+}
+
+var errUnavailable = errors.New("revocation list is unavailable")
+
+func (l *unavailableRevocationList) IsRevoked(ctx context.Context, id string) (bool, error) {
+	return false, errUnavailable
+}
+
+func TestJWTGuard_RestoreAuthErrors(t *testing.T) {
+	keys, err := jwt.NewHMACKeySet([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuer := &jwt.JWT{Keys: keys, Now: func() time.Time { return time.Now().Add(-2 * time.Hour) }}
+	expired, _, err := issuer.Issue("1", jwt.AccessToken, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid, _, err := (&jwt.JWT{Keys: keys}).Issue("1", jwt.AccessToken, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		revocation jwt.RevocationList
+		want       error
+	}{
+		{"expired token", expired, nil, nil},
+		{"malformed token", "not-a-token", nil, nil},
+		{"revocation list unavailable", valid, &unavailableRevocationList{}, errUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Header.Set("Authorization", "Bearer "+tt.token)
+
+			guard := &JWTGuard{Request: r, JWT: &jwt.JWT{Keys: keys, Revocation: tt.revocation}}
+			if err := guard.RestoreAuth(context.Background()); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+			if guard.Check() {
+				t.Error("expected the request to be unauthenticated")
+			}
+		})
+	}
+}
@@ -25,6 +25,9 @@ type SessionGuard struct {
 	Provider         auth.UserProvider
 	RecallerIdMorph  auth.RecallerIdMorph
 
+	// How long a pending two-factor login can be completed. Defaults to 10 minutes.
+	TwoFactorTimeout time.Duration
+
 	// Listen to auth events.
 	//
 	// Recommend attaching this to an event emitter.
@@ -63,6 +66,10 @@ func (sg *SessionGuard) GetName() string {
 	return fmt.Sprintf("login_%s", sg.Name)
 }
 
+func (sg *SessionGuard) GetTwoFactorName() string {
+	return fmt.Sprintf("login_2fa_%s", sg.Name)
+}
+
 func (sg *SessionGuard) GetRecallerName() string {
 	return fmt.Sprintf("remember_%s", sg.Name)
 }
@@ -178,6 +185,11 @@ func (sg *SessionGuard) Attempt(ctx context.Context, credentials map[string]any,
 			return false, err
 		}
 
+		// the user is not logged in until the two-factor challenge is completed
+		if requiresTwoFactor(user) {
+			return true, sg.startTwoFactor(ctx, user, remember, newhash)
+		}
+
 		err = sg.login(ctx, user, remember, newhash)
 		if err != nil {
 			return false, err
@@ -263,6 +275,116 @@ func (sg *SessionGuard) login(ctx context.Context, user auth.Authenticatable, re
 	return sg.setUser(ctx, user, true, newhash)
 }
 
+func requiresTwoFactor(user auth.Authenticatable) bool {
+	user2fa, ok := user.(auth.TwoFactorAuthenticatable)
+	return ok && user2fa.HasTwoFactorEnabled()
+}
+
+type pendingTwoFactor struct {
+	id        any
+	remember  bool
+	newhash   string
+	expiresAt int64
+}
+
+// Hold the user, whose password is validated, until CompleteTwoFactor is called.
+func (sg *SessionGuard) startTwoFactor(ctx context.Context, user auth.Authenticatable, remember bool, newhash string) error {
+	timeout := sg.TwoFactorTimeout
+	if timeout <= 0 {
+		timeout = 10 * time.Minute
+	}
+
+	sg.Session.Store.Remove(sg.GetName())
+	sg.Session.Store.Put(sg.GetTwoFactorName(), map[string]any{
+		"id":         user.GetAuthIdentifier(),
+		"remember":   remember,
+		"newhash":    newhash,
+		"expires_at": time.Now().Add(timeout).Unix(),
+	})
+	return sg.Session.Store.Migrate(ctx, true)
+}
+
+func (sg *SessionGuard) pendingTwoFactor() (pendingTwoFactor, bool) {
+	value, ok := sg.Session.Store.Get(sg.GetTwoFactorName())
+	if !ok {
+		return pendingTwoFactor{}, false
+	}
+
+	data, ok := value.(map[string]any)
+	if !ok {
+		return pendingTwoFactor{}, false
+	}
+
+	pending := pendingTwoFactor{id: data["id"]}
+	pending.remember, _ = data["remember"].(bool)
+	pending.newhash, _ = data["newhash"].(string)
+	pending.expiresAt, _ = data["expires_at"].(int64)
+	if pending.id == nil || pending.expiresAt <= time.Now().Unix() {
+		return pendingTwoFactor{}, false
+	}
+
+	return pending, true
+}
+
+// Whether a user has passed the password step and has yet to complete the two-factor challenge.
+func (sg *SessionGuard) HasPendingTwoFactor() bool {
+	_, ok := sg.pendingTwoFactor()
+	return ok
+}
+
+// Retrieve the user awaiting the two-factor challenge, to verify their code.
+func (sg *SessionGuard) PendingTwoFactorUser(ctx context.Context) (auth.Authenticatable, error) {
+	pending, ok := sg.pendingTwoFactor()
+	if !ok {
+		return nil, auth.ErrNoPendingTwoFactor
+	}
+
+	return sg.Provider.RetrieveById(ctx, pending.id)
+}
+
+// Log the pending user in once their second factor has been verified.
+func (sg *SessionGuard) CompleteTwoFactor(ctx context.Context) error {
+	pending, ok := sg.pendingTwoFactor()
+	if !ok {
+		return auth.ErrNoPendingTwoFactor
+	}
+
+	user, err := sg.Provider.RetrieveById(ctx, pending.id)
+	if err != nil {
+		return err
+	}
+
+	sg.CancelTwoFactor()
+	return sg.login(ctx, user, pending.remember, pending.newhash)
+}
+
+// Verify the second factor of the pending user, e.g. their TOTP code, and log them in when it is valid.
+// An invalid second factor triggers the Failed event, as invalid credentials do.
+func (sg *SessionGuard) AttemptTwoFactor(ctx context.Context, verify func(ctx context.Context, user auth.Authenticatable) (bool, error)) (bool, error) {
+	user, err := sg.PendingTwoFactorUser(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	valid, err := verify(ctx, user)
+	if err != nil {
+		return false, err
+	} else if valid {
+		return true, sg.CompleteTwoFactor(ctx)
+	}
+
+	if sg.Callbacks != nil {
+		err := sg.Callbacks.Failed(ctx, sg.Name, user)
+		return false, err
+	}
+
+	return false, nil
+}
+
+func (sg *SessionGuard) CancelTwoFactor() {
+	sg.Session.Store.Remove(sg.GetTwoFactorName())
+}
+
 func (sg *SessionGuard) ensureRememberTokenIsSet(ctx context.Context, user auth.Authenticatable) error {
 	if user.GetRememberToken() == "" {
 		return sg.cycleRememberToken(ctx, user)
@@ -362,6 +484,14 @@ func (sg *SessionGuard) LogoutOtherDevices(ctx context.Context, password string)
 		sg.createRecaller(sg.user, newhash)
 	}
 
+	// end the other sessions right away when the handler can find them
+	if repository, ok := sg.Session.Store.Handler.(session.SessionRepository); ok {
+		_, err := repository.DestroyByUser(ctx, sg.user.GetAuthIdentifier(), sg.Session.Store.ID)
+		if err != nil {
+			return err
+		}
+	}
+
 	if sg.Callbacks != nil {
 		return sg.Callbacks.OtherDeviceLogout(ctx, sg.Name, sg.user)
 	}
@@ -495,6 +625,7 @@ func (sg *SessionGuard) checkRecaller() (check bool, err error) {
 
 func (sg *SessionGuard) clearUserDataFromStorage() {
 	sg.Session.Store.Remove(sg.GetName())
+	sg.Session.Store.Remove(sg.GetTwoFactorName())
 	sg.Cookie.Forget(sg.GetRecallerName())
 }
 
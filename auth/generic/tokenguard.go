@@ -0,0 +1,166 @@
+package generic
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/wolftotem4/golava-core/auth"
+	"github.com/wolftotem4/golava-core/auth/callback"
+)
+
+// TokenGuard authenticates requests by an API token sent as a bearer token,
+// or as a query string / form field named InputKey.
+type TokenGuard struct {
+	Name     string
+	Request  *http.Request
+	Provider auth.ApiTokenUserProvider
+
+	// The query string or form field holding the token. Defaults to "api_token".
+	InputKey string
+
+	// Hash the token with SHA-256 before looking it up, so only digests need to be stored.
+	Hash bool
+
+	// Listen to auth events.
+	//
+	// See SessionGuard.Callbacks.
+	Callbacks callback.Callbacks
+
+	user auth.Authenticatable
+}
+
+func HashApiToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (tg *TokenGuard) User() auth.Authenticatable {
+	return tg.user
+}
+
+func (tg *TokenGuard) SetUser(user auth.Authenticatable) error {
+	return tg.setUser(context.TODO(), user)
+}
+
+func (tg *TokenGuard) setUser(ctx context.Context, user auth.Authenticatable) error {
+	tg.user = user
+
+	if user != nil && tg.Callbacks != nil {
+		return tg.Callbacks.Authenticated(ctx, tg.Name, user)
+	}
+
+	return nil
+}
+
+func (tg *TokenGuard) ID() any {
+	if tg.user == nil {
+		return nil
+	}
+
+	return tg.user.GetAuthIdentifier()
+}
+
+func (tg *TokenGuard) Check() bool {
+	return tg.user != nil
+}
+
+func (tg *TokenGuard) HasUser() bool {
+	return tg.user != nil
+}
+
+func (tg *TokenGuard) Guest() bool {
+	return !tg.Check()
+}
+
+// Validate the token found in credentials under InputKey.
+func (tg *TokenGuard) Validate(ctx context.Context, credentials map[string]any) (bool, error) {
+	token, _ := credentials[tg.inputKey()].(string)
+	if token == "" {
+		return false, nil
+	}
+
+	user, err := tg.retrieveByToken(ctx, token)
+	if err != nil || user == nil {
+		return false, err
+	}
+
+	if tg.Callbacks != nil {
+		err := tg.Callbacks.Validated(ctx, tg.Name, user)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// RestoreAuth authenticates the user from the token carried by the current request.
+func (tg *TokenGuard) RestoreAuth(ctx context.Context) error {
+	token := tg.GetTokenForRequest()
+	if token == "" {
+		return nil
+	}
+
+	user, err := tg.retrieveByToken(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		if tg.Callbacks != nil {
+			return tg.Callbacks.Failed(ctx, tg.Name, nil)
+		}
+		return nil
+	}
+
+	return tg.setUser(ctx, user)
+}
+
+func (tg *TokenGuard) retrieveByToken(ctx context.Context, token string) (auth.Authenticatable, error) {
+	if tg.Hash {
+		token = HashApiToken(token)
+	}
+
+	user, err := tg.Provider.RetrieveByApiToken(ctx, token)
+	if errors.Is(err, auth.ErrUserNotFound) {
+		return nil, nil
+	}
+	return user, err
+}
+
+// GetTokenForRequest looks for the token in the Authorization header first,
+// then in the query string, then in the request body.
+func (tg *TokenGuard) GetTokenForRequest() string {
+	if tg.Request == nil {
+		return ""
+	}
+
+	if token := BearerToken(tg.Request); token != "" {
+		return token
+	}
+
+	if token := tg.Request.URL.Query().Get(tg.inputKey()); token != "" {
+		return token
+	}
+
+	return tg.Request.PostFormValue(tg.inputKey())
+}
+
+func (tg *TokenGuard) inputKey() string {
+	if tg.InputKey == "" {
+		return "api_token"
+	}
+	return tg.InputKey
+}
+
+func BearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
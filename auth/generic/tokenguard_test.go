@@ -0,0 +1,128 @@
+package generic
+
+import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/wolftotem4/golava-core/auth"
+	"github.com/wolftotem4/golava-core/auth/callback"
+)
+
+type apiTokenProvider struct {
+	auth.UserProvider
+	tokens map[string]*User
+}
+
+func (p *apiTokenProvider) RetrieveByApiToken(ctx context.Context, token string) (auth.Authenticatable, error) {
+	user, ok := p.tokens[token]
+	if !ok {
+		return nil, auth.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func TestTokenGuard_RestoreAuth(t *testing.T) {
+	user := &User{ID: 1, Username: "john"}
+	provider := &apiTokenProvider{tokens: map[string]*User{"secret": user}}
+
+	tests := []struct {
+		name    string
+		prepare func() *TokenGuard
+		want    bool
+	}{
+		{"bearer", func() *TokenGuard {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Header.Set("Authorization", "Bearer secret")
+			return &TokenGuard{Request: r, Provider: provider}
+		}, true},
+		{"query", func() *TokenGuard {
+			r := httptest.NewRequest("GET", "/?api_token=secret", nil)
+			return &TokenGuard{Request: r, Provider: provider}
+		}, true},
+		{"form", func() *TokenGuard {
+			form := url.Values{"token": {"secret"}}
+			r := httptest.NewRequest("POST", "/", strings.NewReader(form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			return &TokenGuard{Request: r, Provider: provider, InputKey: "token"}
+		}, true},
+		{"invalid token", func() *TokenGuard {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Header.Set("Authorization", "Bearer wrong")
+			return &TokenGuard{Request: r, Provider: provider}
+		}, false},
+		{"missing token", func() *TokenGuard {
+			r := httptest.NewRequest("GET", "/", nil)
+			return &TokenGuard{Request: r, Provider: provider}
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guard := tt.prepare()
+			if err := guard.RestoreAuth(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			if guard.Check() != tt.want {
+				t.Errorf("expected Check() to be %v", tt.want)
+			}
+
+			if tt.want && guard.ID() != 1 {
+				t.Errorf("expected ID 1, got %v", guard.ID())
+			}
+		})
+	}
+}
+
+func TestTokenGuard_Hash(t *testing.T) {
+	user := &User{ID: 1, Username: "john"}
+	provider := &apiTokenProvider{tokens: map[string]*User{HashApiToken("secret"): user}}
+
+	counter := &eventCounter{}
+	callbacks := callback.Listen(counter)
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	guard := &TokenGuard{Request: r, Provider: provider, Hash: true, Callbacks: callbacks}
+	if err := guard.RestoreAuth(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !guard.Check() || counter.authenticated != 1 {
+		t.Error("expected hashed token to authenticate")
+	}
+
+	r.Header.Set("Authorization", "Bearer wrong")
+	guard = &TokenGuard{Request: r, Provider: provider, Hash: true, Callbacks: callbacks}
+	if err := guard.RestoreAuth(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if guard.Check() || counter.failed != 1 {
+		t.Error("expected invalid token to fail")
+	}
+
+	valid, err := guard.Validate(context.Background(), map[string]any{"api_token": "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !valid {
+		t.Error("expected credentials to be valid")
+	}
+}
+
+type eventCounter struct {
+	authenticated int
+	failed        int
+}
+
+func (e *eventCounter) Authenticated(ctx context.Context, name string, user auth.Authenticatable) error {
+	e.authenticated++
+	return nil
+}
+
+func (e *eventCounter) Failed(ctx context.Context, name string, user auth.Authenticatable) error {
+	e.failed++
+	return nil
+}
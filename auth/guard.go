@@ -11,3 +11,16 @@ type Guard interface {
 	HasUser() bool
 	SetUser(user Authenticatable) error
 }
+
+// RestorableGuard is implemented by guards that authenticate from the current request,
+// such as the session, the remember-me cookie or a bearer token.
+type RestorableGuard interface {
+	Guard
+	RestoreAuth(ctx context.Context) error
+}
+
+// TokenAbilityGuard is implemented by guards that authenticate with scoped access tokens.
+type TokenAbilityGuard interface {
+	Guard
+	TokenCan(ability string) bool
+}
@@ -0,0 +1,107 @@
+package jwt
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+const (
+	AccessToken  = "access"
+	RefreshToken = "refresh"
+)
+
+// Claims are the registered claims of a token. Extra holds any other claim.
+type Claims struct {
+	Issuer    string   `json:"iss,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	Audience  Audience `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	ID        string   `json:"jti,omitempty"`
+
+	// Either AccessToken or RefreshToken.
+	TokenType string `json:"token_type,omitempty"`
+
+	// The ID of the refresh token issued alongside an access token, so both can be revoked together.
+	RefreshID string `json:"rid,omitempty"`
+
+	Extra map[string]any `json:"-"`
+}
+
+type registeredClaims Claims
+
+var registeredNames = []string{"iss", "sub", "aud", "exp", "nbf", "iat", "jti", "token_type", "rid"}
+
+func (c Claims) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(registeredClaims(c))
+	if err != nil || len(c.Extra) == 0 {
+		return data, err
+	}
+
+	merged := make(map[string]any, len(c.Extra))
+	for name, value := range c.Extra {
+		if !slices.Contains(registeredNames, name) {
+			merged[name] = value
+		}
+	}
+
+	err = json.Unmarshal(data, &merged)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(merged)
+}
+
+func (c *Claims) UnmarshalJSON(data []byte) error {
+	err := json.Unmarshal(data, (*registeredClaims)(c))
+	if err != nil {
+		return err
+	}
+
+	var all map[string]any
+	err = json.Unmarshal(data, &all)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range registeredNames {
+		delete(all, name)
+	}
+
+	if len(all) > 0 {
+		c.Extra = all
+	} else {
+		c.Extra = nil
+	}
+
+	return nil
+}
+
+// Audience is encoded as a string when it holds a single value, as an array otherwise.
+type Audience []string
+
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+
+	var multiple []string
+	err := json.Unmarshal(data, &multiple)
+	*a = multiple
+	return err
+}
+
+func (a Audience) Contains(audience string) bool {
+	return slices.Contains(a, audience)
+}
@@ -0,0 +1,40 @@
+package jwt
+
+import "errors"
+
+var ErrMalformedToken = errors.New("jwt: malformed token")
+var ErrUnsupportedAlgorithm = errors.New("jwt: unsupported algorithm")
+var ErrUnknownKey = errors.New("jwt: unknown key id")
+var ErrInvalidSignature = errors.New("jwt: invalid signature")
+var ErrTokenExpired = errors.New("jwt: token has expired")
+var ErrTokenNotValidYet = errors.New("jwt: token is not valid yet")
+var ErrInvalidIssuer = errors.New("jwt: invalid issuer")
+var ErrInvalidAudience = errors.New("jwt: invalid audience")
+var ErrInvalidTokenType = errors.New("jwt: invalid token type")
+var ErrTokenRevoked = errors.New("jwt: token has been revoked")
+var ErrEmptySecret = errors.New("jwt: HMAC secret is empty")
+
+// validationErrors are the errors caused by the token itself.
+var validationErrors = []error{
+	ErrMalformedToken,
+	ErrUnsupportedAlgorithm,
+	ErrUnknownKey,
+	ErrInvalidSignature,
+	ErrTokenExpired,
+	ErrTokenNotValidYet,
+	ErrInvalidIssuer,
+	ErrInvalidAudience,
+	ErrInvalidTokenType,
+	ErrTokenRevoked,
+}
+
+// IsValidationError reports whether the token was rejected for itself, rather
+// than because e.g. the revocation list could not be reached.
+func IsValidationError(err error) bool {
+	for _, target := range validationErrors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,233 @@
+package jwt
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type JWT struct {
+	Keys     *KeySet
+	Issuer   string
+	Audience []string
+
+	// Lifetime of access tokens. Defaults to one hour.
+	TTL time.Duration
+
+	// Lifetime of refresh tokens. Defaults to two weeks.
+	RefreshTTL time.Duration
+
+	// Delay before issued tokens become valid.
+	NotBefore time.Duration
+
+	// Clock skew tolerated when checking exp and nbf.
+	Leeway time.Duration
+
+	// Revoked tokens are rejected when set. Logout and refresh rotation depend on it.
+	Revocation RevocationList
+
+	Now func() time.Time
+}
+
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// Issue signs a single token of the given type for the subject.
+func (j *JWT) Issue(subject string, tokenType string, extra map[string]any) (string, *Claims, error) {
+	now := j.now()
+
+	ttl := j.ttl()
+	if tokenType == RefreshToken {
+		ttl = j.refreshTTL()
+	}
+
+	claims := &Claims{
+		Issuer:    j.Issuer,
+		Subject:   subject,
+		Audience:  j.Audience,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+		ID:        uuid.New().String(),
+		TokenType: tokenType,
+		Extra:     extra,
+	}
+
+	if j.NotBefore > 0 {
+		claims.NotBefore = now.Add(j.NotBefore).Unix()
+	}
+
+	token, err := Sign(claims, j.Keys.SigningKey())
+	return token, claims, err
+}
+
+// IssuePair signs an access token and the refresh token that can renew it.
+func (j *JWT) IssuePair(subject string, extra map[string]any) (*TokenPair, error) {
+	refreshToken, refreshClaims, err := j.Issue(subject, RefreshToken, extra)
+	if err != nil {
+		return nil, err
+	}
+
+	now := j.now()
+	claims := &Claims{
+		Issuer:    j.Issuer,
+		Subject:   subject,
+		Audience:  j.Audience,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(j.ttl()).Unix(),
+		ID:        uuid.New().String(),
+		TokenType: AccessToken,
+		RefreshID: refreshClaims.ID,
+		Extra:     extra,
+	}
+
+	if j.NotBefore > 0 {
+		claims.NotBefore = now.Add(j.NotBefore).Unix()
+	}
+
+	accessToken, err := Sign(claims, j.Keys.SigningKey())
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(j.ttl().Seconds()),
+	}, nil
+}
+
+// Parse verifies the signature and the claims of the token.
+func (j *JWT) Parse(ctx context.Context, token string) (*Claims, error) {
+	claims, err := Verify(token, j.Keys)
+	if err != nil {
+		return nil, err
+	}
+
+	err = j.validate(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if j.Revocation != nil && claims.ID != "" {
+		revoked, err := j.Revocation.IsRevoked(ctx, claims.ID)
+		if err != nil {
+			return nil, err
+		} else if revoked {
+			return nil, ErrTokenRevoked
+		}
+	}
+
+	return claims, nil
+}
+
+// ParseType parses the token and checks it is of the given type.
+func (j *JWT) ParseType(ctx context.Context, token string, tokenType string) (*Claims, error) {
+	claims, err := j.Parse(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.TokenType != tokenType {
+		return nil, ErrInvalidTokenType
+	}
+
+	return claims, nil
+}
+
+// Refresh exchanges a refresh token for a new token pair. The refresh token
+// is consumed, so it can be used only once, even by concurrent requests.
+func (j *JWT) Refresh(ctx context.Context, refreshToken string) (*TokenPair, *Claims, error) {
+	claims, err := j.ParseType(ctx, refreshToken, RefreshToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if j.Revocation != nil {
+		consumed, err := j.Revocation.Consume(ctx, claims.ID, time.Unix(claims.ExpiresAt, 0))
+		if err != nil {
+			return nil, nil, err
+		} else if !consumed {
+			return nil, nil, ErrTokenRevoked
+		}
+	}
+
+	pair, err := j.IssuePair(claims.Subject, claims.Extra)
+	return pair, claims, err
+}
+
+// Revoke the token, and the refresh token issued with it if it is an access token.
+func (j *JWT) Revoke(ctx context.Context, claims *Claims) error {
+	if j.Revocation == nil {
+		return nil
+	}
+
+	err := j.Revocation.Revoke(ctx, claims.ID, time.Unix(claims.ExpiresAt, 0))
+	if err != nil {
+		return err
+	}
+
+	if claims.RefreshID != "" {
+		expiresAt := time.Unix(claims.IssuedAt, 0).Add(j.refreshTTL())
+		return j.Revocation.Revoke(ctx, claims.RefreshID, expiresAt)
+	}
+
+	return nil
+}
+
+func (j *JWT) validate(claims *Claims) error {
+	now := j.now()
+
+	if claims.ExpiresAt != 0 && !now.Before(time.Unix(claims.ExpiresAt, 0).Add(j.Leeway)) {
+		return ErrTokenExpired
+	}
+
+	if claims.NotBefore != 0 && now.Add(j.Leeway).Before(time.Unix(claims.NotBefore, 0)) {
+		return ErrTokenNotValidYet
+	}
+
+	if j.Issuer != "" && claims.Issuer != j.Issuer {
+		return ErrInvalidIssuer
+	}
+
+	if len(j.Audience) > 0 {
+		matched := false
+		for _, audience := range j.Audience {
+			if claims.Audience.Contains(audience) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return ErrInvalidAudience
+		}
+	}
+
+	return nil
+}
+
+func (j *JWT) now() time.Time {
+	if j.Now != nil {
+		return j.Now()
+	}
+	return time.Now()
+}
+
+func (j *JWT) ttl() time.Duration {
+	if j.TTL > 0 {
+		return j.TTL
+	}
+	return time.Hour
+}
+
+func (j *JWT) refreshTTL() time.Duration {
+	if j.RefreshTTL > 0 {
+		return j.RefreshTTL
+	}
+	return 14 * 24 * time.Hour
+}
@@ -0,0 +1,280 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := []*Key{
+		HMACKey("hmac", []byte("12345678901234567890123456789012")),
+		RSAKey("rsa", rsaKey),
+		EdDSAKey("ed", edKey),
+	}
+
+	for _, key := range keys {
+		t.Run(key.Algorithm, func(t *testing.T) {
+			claims := &Claims{Subject: "1", Extra: map[string]any{"role": "admin"}}
+
+			token, err := Sign(claims, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			parsed, err := Verify(token, mustKeySet(t, key))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if parsed.Subject != "1" || parsed.Extra["role"] != "admin" {
+				t.Errorf("unexpected claims %+v", parsed)
+			}
+
+			tampered := token[:len(token)-2] + "xx"
+			if _, err := Verify(tampered, mustKeySet(t, key)); err == nil {
+				t.Error("expected tampered token to be rejected")
+			}
+		})
+	}
+}
+
+func TestVerify_AlgorithmIsPinnedByKey(t *testing.T) {
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	public := edKey.Public().(ed25519.PublicKey)
+
+	// sign with HS256 using the public key bytes under the EdDSA key id
+	forged, err := Sign(&Claims{Subject: "1"}, HMACKey("ed", public))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Verify(forged, mustKeySet(t, EdDSAPublicKey("ed", public)))
+	if !errors.Is(err, ErrUnsupportedAlgorithm) {
+		t.Errorf("expected ErrUnsupportedAlgorithm, got %v", err)
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	oldKey := HMACKey("2024", []byte("old-secret"))
+	newKey := HMACKey("2025", []byte("new-secret"))
+
+	keys := mustKeySet(t, oldKey)
+	j := &JWT{Keys: keys}
+
+	oldToken, _, err := j.Issue("1", AccessToken, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := keys.Rotate(newKey); err != nil {
+		t.Fatal(err)
+	}
+
+	newToken, _, err := j.Issue("1", AccessToken, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, token := range []string{oldToken, newToken} {
+		if _, err := j.Parse(context.Background(), token); err != nil {
+			t.Errorf("expected token to be valid after rotation: %v", err)
+		}
+	}
+
+	keys.Remove("2024")
+
+	if _, err := j.Parse(context.Background(), oldToken); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("expected ErrUnknownKey, got %v", err)
+	}
+}
+
+func TestParse_Claims(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	keys := mustKeySet(t, HMACKey("default", []byte("secret")))
+
+	issuer := &JWT{
+		Keys:      keys,
+		Issuer:    "https://example.com",
+		Audience:  []string{"api"},
+		TTL:       time.Minute,
+		NotBefore: 10 * time.Second,
+		Now:       func() time.Time { return now },
+	}
+
+	token, _, err := issuer.Issue("1", AccessToken, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		parser JWT
+		want   error
+	}{
+		{"valid", JWT{Keys: keys, Issuer: "https://example.com", Audience: []string{"api"}, Now: func() time.Time { return now.Add(30 * time.Second) }}, nil},
+		{"not yet valid", JWT{Keys: keys, Now: func() time.Time { return now }}, ErrTokenNotValidYet},
+		{"leeway", JWT{Keys: keys, Leeway: 15 * time.Second, Now: func() time.Time { return now }}, nil},
+		{"expired", JWT{Keys: keys, Now: func() time.Time { return now.Add(time.Hour) }}, ErrTokenExpired},
+		{"issuer", JWT{Keys: keys, Issuer: "https://example.org", Now: func() time.Time { return now.Add(30 * time.Second) }}, ErrInvalidIssuer},
+		{"audience", JWT{Keys: keys, Audience: []string{"admin"}, Now: func() time.Time { return now.Add(30 * time.Second) }}, ErrInvalidAudience},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.parser.Parse(context.Background(), token)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestRefreshAndRevoke(t *testing.T) {
+	ctx := context.Background()
+	j := &JWT{
+		Keys:       mustKeySet(t, HMACKey("default", []byte("secret"))),
+		Revocation: NewMemoryRevocationList(),
+	}
+
+	pair, err := j.IssuePair("1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := j.ParseType(ctx, pair.RefreshToken, AccessToken); !errors.Is(err, ErrInvalidTokenType) {
+		t.Errorf("expected refresh token to be rejected as access token, got %v", err)
+	}
+
+	renewed, claims, err := j.Refresh(ctx, pair.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if claims.Subject != "1" {
+		t.Errorf("expected subject 1, got %s", claims.Subject)
+	}
+
+	if _, _, err := j.Refresh(ctx, pair.RefreshToken); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("expected refresh token to be single use, got %v", err)
+	}
+
+	access, err := j.ParseType(ctx, renewed.AccessToken, AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := j.Revoke(ctx, access); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := j.Parse(ctx, renewed.AccessToken); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("expected revoked access token, got %v", err)
+	}
+
+	if _, _, err := j.Refresh(ctx, renewed.RefreshToken); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("expected refresh token to be revoked with its access token, got %v", err)
+	}
+}
+
+func mustKeySet(t *testing.T, signingKey *Key, verificationKeys ...*Key) *KeySet {
+	keys, err := NewKeySet(signingKey, verificationKeys...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+func TestEmptyHMACSecret(t *testing.T) {
+	if _, err := NewHMACKeySet(nil); !errors.Is(err, ErrEmptySecret) {
+		t.Errorf("expected ErrEmptySecret, got %v", err)
+	}
+
+	if _, err := NewKeySet(nil, HMACKey("empty", []byte{})); !errors.Is(err, ErrEmptySecret) {
+		t.Errorf("expected ErrEmptySecret for a verification key, got %v", err)
+	}
+
+	keys := mustKeySet(t, HMACKey("default", []byte("secret")))
+	if err := keys.Rotate(HMACKey("empty", nil)); !errors.Is(err, ErrEmptySecret) {
+		t.Errorf("expected ErrEmptySecret when rotating, got %v", err)
+	}
+	if err := keys.Add(HMACKey("empty", nil)); !errors.Is(err, ErrEmptySecret) {
+		t.Errorf("expected ErrEmptySecret when adding, got %v", err)
+	}
+
+	// a token signed with an empty secret is never accepted, whatever the key set
+	forged := signingInput(t, "empty") + "." + encodeSegment(hmacSHA256(nil, signingInput(t, "empty")))
+	keys.keys["empty"] = HMACKey("empty", nil)
+	if _, err := Verify(forged, keys); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func signingInput(t *testing.T, keyID string) string {
+	headerJson, err := json.Marshal(header{Algorithm: HS256, Type: "JWT", KeyID: keyID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claimsJson, err := json.Marshal(&Claims{Subject: "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encodeSegment(headerJson) + "." + encodeSegment(claimsJson)
+}
+
+func hmacSHA256(secret []byte, data string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func TestRefreshIsSingleUseUnderConcurrency(t *testing.T) {
+	ctx := context.Background()
+	j := &JWT{
+		Keys:       mustKeySet(t, HMACKey("default", []byte("secret"))),
+		Revocation: NewMemoryRevocationList(),
+	}
+
+	pair, err := j.IssuePair("1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	var refreshed atomic.Int32
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := j.Refresh(ctx, pair.RefreshToken); err == nil {
+				refreshed.Add(1)
+			} else if !errors.Is(err, ErrTokenRevoked) {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if refreshed.Load() != 1 {
+		t.Errorf("expected the refresh token to be used once, got %d", refreshed.Load())
+	}
+}
@@ -0,0 +1,213 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	EdDSA = "EdDSA"
+)
+
+// Key is a signing or verification key identified by the "kid" header.
+//
+// Keys built from public keys only can verify tokens but cannot sign them.
+type Key struct {
+	ID         string
+	Algorithm  string
+	Secret     []byte
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
+func HMACKey(id string, secret []byte) *Key {
+	return &Key{ID: id, Algorithm: HS256, Secret: secret}
+}
+
+func RSAKey(id string, privateKey *rsa.PrivateKey) *Key {
+	return &Key{ID: id, Algorithm: RS256, PrivateKey: privateKey, PublicKey: &privateKey.PublicKey}
+}
+
+func RSAPublicKey(id string, publicKey *rsa.PublicKey) *Key {
+	return &Key{ID: id, Algorithm: RS256, PublicKey: publicKey}
+}
+
+func EdDSAKey(id string, privateKey ed25519.PrivateKey) *Key {
+	return &Key{ID: id, Algorithm: EdDSA, PrivateKey: privateKey, PublicKey: privateKey.Public()}
+}
+
+func EdDSAPublicKey(id string, publicKey ed25519.PublicKey) *Key {
+	return &Key{ID: id, Algorithm: EdDSA, PublicKey: publicKey}
+}
+
+func (k *Key) CanSign() bool {
+	if k == nil {
+		return false
+	}
+	if k.Algorithm == HS256 {
+		return len(k.Secret) > 0
+	}
+	return k.PrivateKey != nil
+}
+
+func (k *Key) validate() error {
+	if k.Algorithm == HS256 && len(k.Secret) == 0 {
+		return fmt.Errorf("%w: key %q", ErrEmptySecret, k.ID)
+	}
+	return nil
+}
+
+func (k *Key) sign(data []byte) ([]byte, error) {
+	switch k.Algorithm {
+	case HS256:
+		mac := hmac.New(sha256.New, k.Secret)
+		mac.Write(data)
+		return mac.Sum(nil), nil
+	case RS256:
+		privateKey, ok := k.PrivateKey.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("jwt: RS256 key requires an *rsa.PrivateKey")
+		}
+		digest := sha256.Sum256(data)
+		return rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, digest[:])
+	case EdDSA:
+		privateKey, ok := k.PrivateKey.(ed25519.PrivateKey)
+		if !ok {
+			return nil, errors.New("jwt: EdDSA key requires an ed25519.PrivateKey")
+		}
+		return ed25519.Sign(privateKey, data), nil
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+}
+
+func (k *Key) verify(data []byte, signature []byte) error {
+	switch k.Algorithm {
+	case HS256:
+		// anyone can sign with an empty secret
+		if len(k.Secret) == 0 {
+			return ErrInvalidSignature
+		}
+		mac := hmac.New(sha256.New, k.Secret)
+		mac.Write(data)
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return ErrInvalidSignature
+		}
+		return nil
+	case RS256:
+		publicKey, ok := k.PublicKey.(*rsa.PublicKey)
+		if !ok {
+			return ErrInvalidSignature
+		}
+		digest := sha256.Sum256(data)
+		if rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature) != nil {
+			return ErrInvalidSignature
+		}
+		return nil
+	case EdDSA:
+		publicKey, ok := k.PublicKey.(ed25519.PublicKey)
+		if !ok || !ed25519.Verify(publicKey, data, signature) {
+			return ErrInvalidSignature
+		}
+		return nil
+	default:
+		return ErrUnsupportedAlgorithm
+	}
+}
+
+// KeySet holds the keys used to verify tokens, and the one used to sign new ones.
+//
+// Rotate keys by adding the new key with Rotate; tokens signed with previous keys
+// remain valid until those keys are removed.
+type KeySet struct {
+	mu         sync.RWMutex
+	signingKey string
+	keys       map[string]*Key
+}
+
+// NewKeySet creates a key set. A nil signing key makes a verification-only key set.
+// HS256 keys without a secret are rejected with ErrEmptySecret.
+func NewKeySet(signingKey *Key, verificationKeys ...*Key) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]*Key)}
+	for _, key := range verificationKeys {
+		if err := key.validate(); err != nil {
+			return nil, err
+		}
+		ks.keys[key.ID] = key
+	}
+	if signingKey != nil {
+		if err := signingKey.validate(); err != nil {
+			return nil, err
+		}
+		ks.keys[signingKey.ID] = signingKey
+		ks.signingKey = signingKey.ID
+	}
+	return ks, nil
+}
+
+// NewHMACKeySet creates a key set signing with HS256, typically keyed by App.AppKey.
+// An empty secret, e.g. an AppKey that is not set, is rejected with ErrEmptySecret.
+func NewHMACKeySet(secret []byte) (*KeySet, error) {
+	return NewKeySet(HMACKey("default", secret))
+}
+
+// Rotate makes the key the signing key, keeping the previous keys for verification.
+func (ks *KeySet) Rotate(key *Key) error {
+	if err := key.validate(); err != nil {
+		return err
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	ks.keys[key.ID] = key
+	ks.signingKey = key.ID
+	return nil
+}
+
+// Add registers a key used only for verification.
+func (ks *KeySet) Add(key *Key) error {
+	if err := key.validate(); err != nil {
+		return err
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	ks.keys[key.ID] = key
+	return nil
+}
+
+// Remove retires a key; tokens signed with it are no longer accepted.
+func (ks *KeySet) Remove(id string) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if id != ks.signingKey {
+		delete(ks.keys, id)
+	}
+}
+
+func (ks *KeySet) Get(id string) (*Key, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	key, ok := ks.keys[id]
+	return key, ok
+}
+
+func (ks *KeySet) SigningKey() *Key {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	return ks.keys[ks.signingKey]
+}
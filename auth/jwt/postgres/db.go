@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PostgresRevocationList is a jwt.RevocationList stored in a table such as:
+//
+//	CREATE TABLE jwt_revocations (id VARCHAR(64) PRIMARY KEY, expires_at BIGINT NOT NULL)
+type PostgresRevocationList struct {
+	DB    *sql.DB
+	Table string
+}
+
+func NewPostgresRevocationList(db *sql.DB, table string) *PostgresRevocationList {
+	return &PostgresRevocationList{
+		DB:    db,
+		Table: table,
+	}
+}
+
+func (d *PostgresRevocationList) Revoke(ctx context.Context, id string, expiresAt time.Time) error {
+	_, err := d.Consume(ctx, id, expiresAt)
+	return err
+}
+
+func (d *PostgresRevocationList) IsRevoked(ctx context.Context, id string) (bool, error) {
+	var found int
+	err := d.DB.QueryRowContext(ctx, fmt.Sprintf(
+		`SELECT 1 FROM "%s" WHERE id = $1`, d.Table,
+	), id).Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Consume inserts the token ID unless it is already present, the affected rows telling whether it was.
+func (d *PostgresRevocationList) Consume(ctx context.Context, id string, expiresAt time.Time) (bool, error) {
+	result, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		`INSERT INTO "%s" (id, expires_at) VALUES ($1, $2) ON CONFLICT (id) DO NOTHING`, d.Table,
+	), id, expiresAt.Unix())
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+// Prune deletes the IDs of the tokens that have expired anyway.
+func (d *PostgresRevocationList) Prune(ctx context.Context, before time.Time) (int64, error) {
+	result, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		`DELETE FROM "%s" WHERE expires_at <= $1`, d.Table,
+	), before.Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
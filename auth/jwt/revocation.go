@@ -0,0 +1,66 @@
+package jwt
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RevocationList records revoked token IDs until the tokens would have expired anyway.
+type RevocationList interface {
+	Revoke(ctx context.Context, id string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, id string) (bool, error)
+
+	// Consume revokes the token unless it is already revoked, and reports whether it did,
+	// in a single step so that concurrent calls cannot both succeed.
+	Consume(ctx context.Context, id string, expiresAt time.Time) (bool, error)
+}
+
+// MemoryRevocationList is a RevocationList for a single process.
+type MemoryRevocationList struct {
+	mu      sync.Mutex
+	revoked map[string]time.Time
+}
+
+func NewMemoryRevocationList() *MemoryRevocationList {
+	return &MemoryRevocationList{revoked: make(map[string]time.Time)}
+}
+
+func (l *MemoryRevocationList) Revoke(ctx context.Context, id string, expiresAt time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.prune()
+	l.revoked[id] = expiresAt
+	return nil
+}
+
+func (l *MemoryRevocationList) Consume(ctx context.Context, id string, expiresAt time.Time) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.prune()
+	if _, ok := l.revoked[id]; ok {
+		return false, nil
+	}
+
+	l.revoked[id] = expiresAt
+	return true, nil
+}
+
+func (l *MemoryRevocationList) prune() {
+	now := time.Now()
+	for revokedId, expires := range l.revoked {
+		if !expires.After(now) {
+			delete(l.revoked, revokedId)
+		}
+	}
+}
+
+func (l *MemoryRevocationList) IsRevoked(ctx context.Context, id string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, ok := l.revoked[id]
+	return ok, nil
+}
@@ -0,0 +1,65 @@
+package sqlserver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SQLServerRevocationList is a jwt.RevocationList stored in a table such as:
+//
+//	CREATE TABLE jwt_revocations (id NVARCHAR(64) PRIMARY KEY, expires_at BIGINT NOT NULL)
+type SQLServerRevocationList struct {
+	DB    *sql.DB
+	Table string
+}
+
+func NewSQLServerRevocationList(db *sql.DB, table string) *SQLServerRevocationList {
+	return &SQLServerRevocationList{
+		DB:    db,
+		Table: table,
+	}
+}
+
+func (d *SQLServerRevocationList) Revoke(ctx context.Context, id string, expiresAt time.Time) error {
+	_, err := d.Consume(ctx, id, expiresAt)
+	return err
+}
+
+func (d *SQLServerRevocationList) IsRevoked(ctx context.Context, id string) (bool, error) {
+	var found int
+	err := d.DB.QueryRowContext(ctx, fmt.Sprintf(
+		"SELECT 1 FROM [%s] WHERE id = @p1", d.Table,
+	), id).Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Consume inserts the token ID unless it is already present, the affected rows telling whether it was.
+func (d *SQLServerRevocationList) Consume(ctx context.Context, id string, expiresAt time.Time) (bool, error) {
+	result, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		"INSERT INTO [%s] (id, expires_at) SELECT @p1, @p2 WHERE NOT EXISTS (SELECT 1 FROM [%s] WITH (UPDLOCK, HOLDLOCK) WHERE id = @p1)", d.Table, d.Table,
+	), id, expiresAt.Unix())
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+// Prune deletes the IDs of the tokens that have expired anyway.
+func (d *SQLServerRevocationList) Prune(ctx context.Context, before time.Time) (int64, error) {
+	result, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		"DELETE FROM [%s] WHERE expires_at <= @p1", d.Table,
+	), before.Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,98 @@
+package jwt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+type header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ,omitempty"`
+	KeyID     string `json:"kid,omitempty"`
+}
+
+// Sign encodes the claims into a compact JWS signed with the key.
+func Sign(claims *Claims, key *Key) (string, error) {
+	if !key.CanSign() {
+		return "", errors.New("jwt: key cannot be used for signing")
+	}
+
+	headerJson, err := json.Marshal(header{Algorithm: key.Algorithm, Type: "JWT", KeyID: key.ID})
+	if err != nil {
+		return "", err
+	}
+
+	claimsJson, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := encodeSegment(headerJson) + "." + encodeSegment(claimsJson)
+	signature, err := key.sign([]byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + encodeSegment(signature), nil
+}
+
+// Verify checks the signature of the token against the key set and returns its claims.
+// The claims themselves (expiry, audience, ...) are not validated.
+func Verify(token string, keys *KeySet) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	headerJson, err := decodeSegment(parts[0])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	var h header
+	if err := json.Unmarshal(headerJson, &h); err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	key, ok := keys.Get(h.KeyID)
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	// the algorithm is pinned by the key, never chosen by the token
+	if h.Algorithm != key.Algorithm {
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	signature, err := decodeSegment(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	err = key.verify([]byte(parts[0]+"."+parts[1]), signature)
+	if err != nil {
+		return nil, err
+	}
+
+	claimsJson, err := decodeSegment(parts[1])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(claimsJson, &claims); err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	return &claims, nil
+}
+
+func encodeSegment(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeSegment(segment string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(segment)
+}
@@ -0,0 +1,108 @@
+// Package manager resolves the named guards of an application for each gin request.
+package manager
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wolftotem4/golava-core/auth"
+)
+
+// GuardFactory creates the guard for the current request.
+type GuardFactory func(c *gin.Context) (auth.Guard, error)
+
+// Manager holds the named guard factories of the application, such as "web", "admin" and "api".
+type Manager struct {
+	DefaultGuard string
+	factories    map[string]GuardFactory
+}
+
+func NewManager(defaultGuard string) *Manager {
+	return &Manager{
+		DefaultGuard: defaultGuard,
+		factories:    make(map[string]GuardFactory),
+	}
+}
+
+// Extend registers the factory of a named guard.
+func (m *Manager) Extend(name string, factory GuardFactory) {
+	if m.factories == nil {
+		m.factories = make(map[string]GuardFactory)
+	}
+	m.factories[name] = factory
+}
+
+func (m *Manager) Has(name string) bool {
+	_, ok := m.factories[name]
+	return ok
+}
+
+// ForRequest returns the guards of a single request. Guards are only created when first used.
+func (m *Manager) ForRequest(c *gin.Context) *Guards {
+	return &Guards{
+		manager:      m,
+		context:      c,
+		defaultGuard: m.DefaultGuard,
+		guards:       make(map[string]auth.Guard),
+	}
+}
+
+// Guards resolves the named guards of a single request, creating each one at most once.
+type Guards struct {
+	manager      *Manager
+	context      *gin.Context
+	defaultGuard string
+	guards       map[string]auth.Guard
+}
+
+// Guard returns the named guard, or the default guard when name is empty.
+//
+// Guards implementing auth.RestorableGuard restore their user when they are created.
+func (g *Guards) Guard(name string) (auth.Guard, error) {
+	if name == "" {
+		name = g.defaultGuard
+	}
+
+	if guard, ok := g.guards[name]; ok {
+		return guard, nil
+	}
+
+	factory, ok := g.manager.factories[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", auth.ErrGuardNotDefined, name)
+	}
+
+	guard, err := factory(g.context)
+	if err != nil {
+		return nil, err
+	}
+
+	if restorable, ok := guard.(auth.RestorableGuard); ok {
+		err := restorable.RestoreAuth(g.context)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	g.guards[name] = guard
+	return guard, nil
+}
+
+// Default returns the default guard of the request.
+func (g *Guards) Default() (auth.Guard, error) {
+	return g.Guard("")
+}
+
+func (g *Guards) DefaultName() string {
+	return g.defaultGuard
+}
+
+// ShouldUse changes the default guard of the request.
+func (g *Guards) ShouldUse(name string) {
+	g.defaultGuard = name
+}
+
+func (g *Guards) Dispose() {
+	g.context = nil
+	g.guards = nil
+}
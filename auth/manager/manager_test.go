@@ -0,0 +1,83 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wolftotem4/golava-core/auth"
+)
+
+type fakeGuard struct {
+	user     auth.Authenticatable
+	restored int
+}
+
+func (g *fakeGuard) Check() bool                          { return g.user != nil }
+func (g *fakeGuard) Guest() bool                          { return g.user == nil }
+func (g *fakeGuard) User() auth.Authenticatable           { return g.user }
+func (g *fakeGuard) ID() any                              { return nil }
+func (g *fakeGuard) HasUser() bool                        { return g.user != nil }
+func (g *fakeGuard) SetUser(u auth.Authenticatable) error { g.user = u; return nil }
+func (g *fakeGuard) Validate(ctx context.Context, credentials map[string]any) (bool, error) {
+	return false, nil
+}
+func (g *fakeGuard) RestoreAuth(ctx context.Context) error {
+	g.restored++
+	return nil
+}
+
+func TestManager(t *testing.T) {
+	created := map[string]int{}
+
+	manager := NewManager("web")
+	for _, name := range []string{"web", "admin"} {
+		manager.Extend(name, func(c *gin.Context) (auth.Guard, error) {
+			created[name]++
+			return &fakeGuard{}, nil
+		})
+	}
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	guards := manager.ForRequest(c)
+
+	if len(created) != 0 {
+		t.Fatal("guards must be resolved lazily")
+	}
+
+	admin, err := guards.Guard("admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	again, _ := guards.Guard("admin")
+	if admin != again || created["admin"] != 1 {
+		t.Error("expected guard to be created once per request")
+	}
+
+	if admin.(*fakeGuard).restored != 1 {
+		t.Error("expected guard to be restored when resolved")
+	}
+
+	web, _ := guards.Default()
+	if web == admin || created["web"] != 1 {
+		t.Error("expected default guard to be web")
+	}
+
+	guards.ShouldUse("admin")
+	if guard, _ := guards.Default(); guard != admin {
+		t.Error("expected default guard to be admin after ShouldUse")
+	}
+
+	if _, err := guards.Guard("api"); !errors.Is(err, auth.ErrGuardNotDefined) {
+		t.Errorf("expected ErrGuardNotDefined, got %v", err)
+	}
+
+	// a new request gets new guards
+	manager.ForRequest(c).Guard("admin")
+	if created["admin"] != 2 {
+		t.Error("expected guards not to be shared across requests")
+	}
+}
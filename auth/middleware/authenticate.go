@@ -17,3 +17,43 @@ func Authenticate(c *gin.Context) {
 
 	c.Next()
 }
+
+// AuthenticateWith passes when any of the named guards is authenticated, and
+// makes the first authenticated one the request's guard. The auth manager's
+// default guard is checked when no name is given.
+func AuthenticateWith(guards ...string) gin.HandlerFunc {
+	if len(guards) == 0 {
+		guards = []string{""}
+	}
+
+	return func(c *gin.Context) {
+		i := instance.MustGetInstance(c)
+		if i.Guards == nil {
+			c.Error(errNoAuthManager)
+			c.Abort()
+			return
+		}
+
+		for _, name := range guards {
+			guard, err := i.Guards.Guard(name)
+			if err != nil {
+				c.Error(err)
+				c.Abort()
+				return
+			}
+
+			if guard.Check() {
+				if name != "" {
+					i.Guards.ShouldUse(name)
+				}
+				i.Auth = guard
+
+				c.Next()
+				return
+			}
+		}
+
+		c.Error(auth.ErrUnauthenticated)
+		c.Abort()
+	}
+}
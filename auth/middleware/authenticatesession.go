@@ -36,7 +36,7 @@ func authenticateSession(ctx context.Context, i *instance.Instance) error {
 		return nil
 	}
 
-	guard, ok := i.Auth.(*generic.SessionGuard)
+	guard, ok := sessionGuard(i.Auth)
 	if !ok {
 		return errors.New("auth guard is not a session guard")
 	}
@@ -70,6 +70,18 @@ func authenticateSession(ctx context.Context, i *instance.Instance) error {
 	return nil
 }
 
+// sessionGuard unwraps decorated guards, such as throttle.ThrottledGuard.
+func sessionGuard(guard auth.Guard) (*generic.SessionGuard, bool) {
+	switch g := guard.(type) {
+	case *generic.SessionGuard:
+		return g, true
+	case interface{ Unwrap() auth.Guard }:
+		return sessionGuard(g.Unwrap())
+	default:
+		return nil, false
+	}
+}
+
 func storePasswordHashInSession(store *session.Store, name string, password string) {
 	store.Put(name, password)
 }
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wolftotem4/golava-core/instance"
+)
+
+var errNoGate = errors.New("authorization gate is not configured")
+
+// Can passes when the current user is allowed the ability by the application's gate.
+// The resolver, which may be nil, loads the arguments of the check, such as the
+// resource identified by a route parameter.
+func Can(ability string, resolver func(c *gin.Context) ([]any, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		i := instance.MustGetInstance(c)
+
+		gate := i.App.Base().Gate
+		if gate == nil {
+			c.Error(errNoGate)
+			c.Abort()
+			return
+		}
+
+		var args []any
+		if resolver != nil {
+			var err error
+			args, err = resolver(c)
+			if err != nil {
+				c.Error(err)
+				c.Abort()
+				return
+			}
+		}
+
+		err := gate.Authorize(c, i.Auth.User(), ability, args...)
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
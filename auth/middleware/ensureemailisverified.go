@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wolftotem4/golava-core/auth"
+	"github.com/wolftotem4/golava-core/http/utils"
+	"github.com/wolftotem4/golava-core/instance"
+)
+
+// EnsureEmailIsVerified blocks users who must verify their email address and
+// have not. Browsers are redirected to the notice page when one is given.
+func EnsureEmailIsVerified(redirectTo string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		instance := instance.MustGetInstance(c)
+
+		user, ok := instance.Auth.User().(auth.MustVerifyEmail)
+		if ok && !user.HasVerifiedEmail() {
+			if redirectTo != "" && !utils.ExpectJson(c.GetHeader("Accept")) {
+				instance.Redirector.Redirect(http.StatusSeeOther, redirectTo)
+				c.Abort()
+				return
+			}
+
+			c.Error(auth.ErrEmailNotVerified)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wolftotem4/golava-core/auth"
+	"github.com/wolftotem4/golava-core/instance"
+)
+
+// RequireAbility passes when the current access token has all of the given abilities.
+func RequireAbility(abilities ...string) gin.HandlerFunc {
+	return requireAbilities(func(guard auth.TokenAbilityGuard) bool {
+		return !slices.ContainsFunc(abilities, func(ability string) bool {
+			return !guard.TokenCan(ability)
+		})
+	})
+}
+
+// RequireAnyAbility passes when the current access token has at least one of the given abilities.
+func RequireAnyAbility(abilities ...string) gin.HandlerFunc {
+	return requireAbilities(func(guard auth.TokenAbilityGuard) bool {
+		return slices.ContainsFunc(abilities, guard.TokenCan)
+	})
+}
+
+func requireAbilities(check func(guard auth.TokenAbilityGuard) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		instance := instance.MustGetInstance(c)
+
+		if !instance.Auth.Check() {
+			c.Error(auth.ErrUnauthenticated)
+			c.Abort()
+			return
+		}
+
+		guard, ok := instance.Auth.(auth.TokenAbilityGuard)
+		if !ok || !check(guard) {
+			c.Error(auth.ErrMissingAbility)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wolftotem4/golava-core/auth"
+	"github.com/wolftotem4/golava-core/auth/rbac"
+	"github.com/wolftotem4/golava-core/instance"
+)
+
+var errNoRoleProvider = errors.New("role provider is not configured")
+
+// RequireRole passes when the current user has at least one of the given roles.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return requireRbac(rbac.ErrMissingRole, func(ctx context.Context, set *rbac.Set) (bool, error) {
+		return set.HasAnyRole(ctx, roles...)
+	})
+}
+
+// RequirePermission passes when the current user has all of the given permissions.
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return requireRbac(rbac.ErrMissingPermission, func(ctx context.Context, set *rbac.Set) (bool, error) {
+		return set.HasAllPermissions(ctx, permissions...)
+	})
+}
+
+func requireRbac(denied error, check func(ctx context.Context, set *rbac.Set) (bool, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		i := instance.MustGetInstance(c)
+
+		provider := i.App.Base().RoleProvider
+		if provider == nil {
+			c.Error(errNoRoleProvider)
+			c.Abort()
+			return
+		}
+
+		user := i.Auth.User()
+		if user == nil {
+			c.Error(auth.ErrUnauthenticated)
+			c.Abort()
+			return
+		}
+
+		ok, err := check(c, rbac.ForRequest(c, provider, user))
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		} else if !ok {
+			c.Error(denied)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wolftotem4/golava-core/auth"
+	"github.com/wolftotem4/golava-core/instance"
+)
+
+// RedirectIfTwoFactorPending redirects users who have passed the password step,
+// but not the two-factor challenge, to the challenge page.
+func RedirectIfTwoFactorPending(challengePath string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		instance := instance.MustGetInstance(c)
+
+		guard, ok := instance.Auth.(auth.TwoFactorGuard)
+		if ok && !guard.Check() && guard.HasPendingTwoFactor() {
+			instance.Redirector.Redirect(http.StatusSeeOther, challengePath)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireTwoFactorPending guards the challenge page, redirecting visitors
+// without a pending two-factor login. The challenge handler verifies the code with
+// TwoFactorGuard.AttemptTwoFactor, which a ThrottledGuard puts behind its limiter.
+func RequireTwoFactorPending(redirectTo string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		instance := instance.MustGetInstance(c)
+
+		guard, ok := instance.Auth.(auth.TwoFactorGuard)
+		if !ok || !guard.HasPendingTwoFactor() {
+			instance.Redirector.Redirect(http.StatusSeeOther, redirectTo)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
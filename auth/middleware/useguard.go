@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wolftotem4/golava-core/auth"
+	"github.com/wolftotem4/golava-core/auth/manager"
+	"github.com/wolftotem4/golava-core/instance"
+)
+
+var errNoAuthManager = errors.New("auth manager is not configured")
+
+// UseGuard replaces the request's guard for the routes it is attached to, so
+// a route group can authenticate with e.g. a TokenGuard while the rest of the
+// application uses a SessionGuard.
+func UseGuard(factory manager.GuardFactory) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		i := instance.MustGetInstance(c)
+
+		guard, err := factory(c)
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		if restorable, ok := guard.(auth.RestorableGuard); ok {
+			err := restorable.RestoreAuth(c)
+			if err != nil {
+				c.Error(err)
+				c.Abort()
+				return
+			}
+		}
+
+		i.Auth = guard
+
+		c.Next()
+	}
+}
+
+// ShouldUse makes the named guard of the auth manager the request's guard.
+func ShouldUse(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		i := instance.MustGetInstance(c)
+		if i.Guards == nil {
+			c.Error(errNoAuthManager)
+			c.Abort()
+			return
+		}
+
+		guard, err := i.Guards.Guard(name)
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		i.Guards.ShouldUse(name)
+		i.Auth = guard
+
+		c.Next()
+	}
+}
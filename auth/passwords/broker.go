@@ -0,0 +1,221 @@
+package passwords
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/wolftotem4/golava-core/auth"
+	"github.com/wolftotem4/golava-core/auth/callback"
+	"github.com/wolftotem4/golava-core/hashing"
+	"github.com/wolftotem4/golava-core/session"
+	"github.com/wolftotem4/golava-core/util"
+)
+
+var (
+	ErrInvalidUser  = errors.New("we can't find a user with that email address")
+	ErrInvalidToken = errors.New("this password reset token is invalid")
+	ErrThrottled    = errors.New("please wait before retrying")
+)
+
+// CanResetPassword is a user who can receive a password reset link.
+type CanResetPassword interface {
+	auth.Authenticatable
+	GetEmailForPasswordReset() string
+}
+
+type UserProvider interface {
+	auth.UserProvider
+
+	// UpdatePassword stores the new password hash of the user.
+	UpdatePassword(ctx context.Context, user auth.Authenticatable, hash string) error
+}
+
+// Notifier sends the reset link, e.g. by email.
+type Notifier interface {
+	SendPasswordResetNotification(ctx context.Context, user CanResetPassword, token string) error
+}
+
+// Broker runs the forgot-password flow.
+type Broker struct {
+	Tokens   TokenRepository
+	Users    UserProvider
+	Hasher   *hashing.HasherManager
+	Notifier Notifier
+
+	// Tokens are stored as their HMAC-SHA256 under this key, typically App.AppKey.
+	Key []byte
+
+	// Lifetime of the tokens. Defaults to one hour.
+	Expire time.Duration
+
+	// Minimum delay between two tokens for the same user. Defaults to one minute.
+	Throttle time.Duration
+
+	// Name of the guard whose callbacks are fired.
+	Name string
+
+	// OtherDeviceLogout is fired after a reset, since the sessions and "remember me"
+	// cookies of other devices are no longer valid.
+	Callbacks callback.Callbacks
+
+	// Sessions, when set, has every session of the user destroyed after a reset.
+	Sessions session.SessionRepository
+}
+
+// SendResetLink creates a token for the user matching the credentials and notifies them.
+func (b *Broker) SendResetLink(ctx context.Context, credentials map[string]any) error {
+	user, err := b.getUser(ctx, credentials)
+	if err != nil {
+		return err
+	}
+
+	token, err := b.CreateToken(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	return b.Notifier.SendPasswordResetNotification(ctx, user, token)
+}
+
+// CreateToken returns a new plain text token for the user, replacing the previous one.
+func (b *Broker) CreateToken(ctx context.Context, user CanResetPassword) (string, error) {
+	email := user.GetEmailForPasswordReset()
+
+	record, err := b.Tokens.Find(ctx, email)
+	if err == nil && time.Since(record.CreatedAt) < b.throttle() {
+		return "", ErrThrottled
+	} else if err != nil && !errors.Is(err, ErrTokenNotFound) {
+		return "", err
+	}
+
+	token := util.RandomString(64)
+	err = b.Tokens.Create(ctx, &TokenRecord{
+		Email:     email,
+		Token:     b.hash(token),
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// TokenExists reports whether the token is the user's current, unexpired token.
+func (b *Broker) TokenExists(ctx context.Context, user CanResetPassword, token string) (bool, error) {
+	record, err := b.Tokens.Find(ctx, user.GetEmailForPasswordReset())
+	if errors.Is(err, ErrTokenNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	if time.Since(record.CreatedAt) >= b.expire() {
+		return false, nil
+	}
+
+	return hmac.Equal([]byte(record.Token), []byte(b.hash(token))), nil
+}
+
+// Reset sets the password of the user matching the credentials, if the token
+// is valid. The token is consumed before the password changes, so that two
+// concurrent resets with the same token cannot both succeed, and the user's
+// remember token is cycled.
+func (b *Broker) Reset(ctx context.Context, credentials map[string]any, token string, password string) (CanResetPassword, error) {
+	user, err := b.getUser(ctx, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	valid, err := b.TokenExists(ctx, user, token)
+	if err != nil {
+		return nil, err
+	} else if !valid {
+		return nil, ErrInvalidToken
+	}
+
+	hash, err := b.Hasher.Make(password)
+	if err != nil {
+		return nil, err
+	}
+
+	consumed, err := b.Tokens.Consume(ctx, user.GetEmailForPasswordReset(), b.hash(token))
+	if err != nil {
+		return nil, err
+	} else if !consumed {
+		return nil, ErrInvalidToken
+	}
+
+	err = b.Users.UpdatePassword(ctx, user, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	rememberToken := util.RandomToken(45)
+	user.SetRememberToken(rememberToken)
+	err = b.Users.UpdateRememberToken(ctx, user, rememberToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if b.Sessions != nil {
+		_, err = b.Sessions.DestroyByUser(ctx, user.GetAuthIdentifier(), "")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if b.Callbacks != nil {
+		err = b.Callbacks.OtherDeviceLogout(ctx, b.Name, user)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return user, nil
+}
+
+// DeleteExpired removes the expired tokens.
+func (b *Broker) DeleteExpired(ctx context.Context) (int64, error) {
+	return b.Tokens.DeleteExpired(ctx, time.Now().Add(-b.expire()))
+}
+
+func (b *Broker) getUser(ctx context.Context, credentials map[string]any) (CanResetPassword, error) {
+	user, err := b.Users.RetrieveByCredentials(ctx, credentials)
+	if errors.Is(err, auth.ErrUserNotFound) {
+		return nil, ErrInvalidUser
+	} else if err != nil {
+		return nil, err
+	}
+
+	resettable, ok := user.(CanResetPassword)
+	if !ok {
+		return nil, ErrInvalidUser
+	}
+
+	return resettable, nil
+}
+
+func (b *Broker) hash(token string) string {
+	mac := hmac.New(sha256.New, b.Key)
+	mac.Write([]byte(token))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (b *Broker) expire() time.Duration {
+	if b.Expire > 0 {
+		return b.Expire
+	}
+	return time.Hour
+}
+
+func (b *Broker) throttle() time.Duration {
+	if b.Throttle > 0 {
+		return b.Throttle
+	}
+	return time.Minute
+}
@@ -0,0 +1,297 @@
+package passwords
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wolftotem4/golava-core/auth"
+	"github.com/wolftotem4/golava-core/hashing"
+	"github.com/wolftotem4/golava-core/session"
+)
+
+type user struct {
+	id            int
+	email         string
+	password      string
+	rememberToken string
+}
+
+func (u *user) GetAuthIdentifierName() string    { return "id" }
+func (u *user) GetAuthIdentifier() any           { return u.id }
+func (u *user) GetAuthPasswordName() string      { return "password" }
+func (u *user) GetAuthPassword() string          { return u.password }
+func (u *user) GetRememberToken() string         { return u.rememberToken }
+func (u *user) SetRememberToken(token string)    { u.rememberToken = token }
+func (u *user) GetRememberTokenName() string     { return "remember_token" }
+func (u *user) GetEmailForPasswordReset() string { return u.email }
+
+type userProvider struct {
+	auth.UserProvider
+	user *user
+}
+
+func (p *userProvider) RetrieveByCredentials(ctx context.Context, credentials map[string]any) (auth.Authenticatable, error) {
+	if credentials["email"] == p.user.email {
+		return p.user, nil
+	}
+	return nil, auth.ErrUserNotFound
+}
+
+func (p *userProvider) UpdatePassword(ctx context.Context, u auth.Authenticatable, hash string) error {
+	u.(*user).password = hash
+	return nil
+}
+
+func (p *userProvider) UpdateRememberToken(ctx context.Context, u auth.Authenticatable, token string) error {
+	return nil
+}
+
+type tokenRepository struct {
+	mu      sync.Mutex
+	records map[string]*TokenRecord
+}
+
+func (r *tokenRepository) Create(ctx context.Context, record *TokenRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records[record.Email] = record
+	return nil
+}
+
+func (r *tokenRepository) Find(ctx context.Context, email string) (*TokenRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, ok := r.records[email]
+	if !ok {
+		return nil, ErrTokenNotFound
+	}
+	copied := *record
+	return &copied, nil
+}
+
+func (r *tokenRepository) Delete(ctx context.Context, email string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.records, email)
+	return nil
+}
+
+func (r *tokenRepository) Consume(ctx context.Context, email string, token string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, ok := r.records[email]
+	if !ok || record.Token != token {
+		return false, nil
+	}
+	delete(r.records, email)
+	return true, nil
+}
+
+func (r *tokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+type sessionRepository struct {
+	session.SessionRepository
+	destroyed []any
+}
+
+func (r *sessionRepository) DestroyByUser(ctx context.Context, userID any, exceptID string) (int64, error) {
+	r.destroyed = append(r.destroyed, userID)
+	return 1, nil
+}
+
+type notifier struct {
+	token string
+}
+
+func (n *notifier) SendPasswordResetNotification(ctx context.Context, user CanResetPassword, token string) error {
+	n.token = token
+	return nil
+}
+
+func newBroker() (*Broker, *userProvider, *tokenRepository, *notifier) {
+	users := &userProvider{user: &user{id: 1, email: "alice@example.com", rememberToken: "old"}}
+	tokens := &tokenRepository{records: make(map[string]*TokenRecord)}
+	notifier := &notifier{}
+
+	return &Broker{
+		Tokens:   tokens,
+		Users:    users,
+		Notifier: notifier,
+		Key:      []byte("key"),
+		Hasher: &hashing.HasherManager{
+			DefaultHasher: "bcrypt",
+			Hashers:       map[string]hashing.Hasher{"bcrypt": &hashing.BcryptHasher{Cost: 4}},
+			MapHashPrefix: map[string]string{"$2a$": "bcrypt"},
+		},
+	}, users, tokens, notifier
+}
+
+func TestReset(t *testing.T) {
+	ctx := context.Background()
+	broker, users, tokens, notifier := newBroker()
+	credentials := map[string]any{"email": "alice@example.com"}
+
+	err := broker.SendResetLink(ctx, credentials)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tokens.records["alice@example.com"].Token == notifier.token {
+		t.Fatalf("expected the token to be stored hashed")
+	}
+
+	err = broker.SendResetLink(ctx, credentials)
+	if !errors.Is(err, ErrThrottled) {
+		t.Fatalf("expected ErrThrottled, got %v", err)
+	}
+
+	_, err = broker.Reset(ctx, credentials, "wrong", "new password")
+	if !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected ErrInvalidToken, got %v", err)
+	}
+
+	_, err = broker.Reset(ctx, credentials, notifier.token, "new password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, _ := broker.Hasher.Check("new password", users.user.password); !ok {
+		t.Fatalf("expected the password to be updated")
+	}
+	if users.user.rememberToken == "old" {
+		t.Fatalf("expected the remember token to be cycled")
+	}
+
+	_, err = broker.Reset(ctx, credentials, notifier.token, "again")
+	if !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected the token to be single-use, got %v", err)
+	}
+}
+
+func TestExpiredToken(t *testing.T) {
+	ctx := context.Background()
+	broker, users, tokens, _ := newBroker()
+
+	token, err := broker.CreateToken(ctx, users.user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokens.records["alice@example.com"].CreatedAt = time.Now().Add(-2 * time.Hour)
+
+	ok, err := broker.TokenExists(ctx, users.user, token)
+	if err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatalf("expected the token to be expired")
+	}
+}
+
+func TestInvalidUser(t *testing.T) {
+	broker, _, _, _ := newBroker()
+
+	err := broker.SendResetLink(context.Background(), map[string]any{"email": "bob@example.com"})
+	if !errors.Is(err, ErrInvalidUser) {
+		t.Fatalf("expected ErrInvalidUser, got %v", err)
+	}
+}
+
+func TestResetIsSingleUseUnderConcurrency(t *testing.T) {
+	ctx := context.Background()
+	broker, users, _, _ := newBroker()
+	credentials := map[string]any{"email": "alice@example.com"}
+
+	token, err := broker.CreateToken(ctx, users.user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		successes int
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := broker.Reset(ctx, credentials, token, "new password")
+			if err == nil {
+				mu.Lock()
+				successes++
+				mu.Unlock()
+			} else if !errors.Is(err, ErrInvalidToken) {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if successes != 1 {
+		t.Fatalf("expected exactly one reset to succeed, got %d", successes)
+	}
+}
+
+func TestResetKeepsPasswordWhenTokenIsReplaced(t *testing.T) {
+	ctx := context.Background()
+	broker, users, tokens, _ := newBroker()
+	credentials := map[string]any{"email": "alice@example.com"}
+
+	token, err := broker.CreateToken(ctx, users.user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a newer token replaces the one being used, after it has been checked
+	replaced := &replacingRepository{tokenRepository: tokens}
+	broker.Tokens = replaced
+
+	_, err = broker.Reset(ctx, credentials, token, "new password")
+	if !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected ErrInvalidToken, got %v", err)
+	}
+	if users.user.password != "" {
+		t.Fatalf("expected the password to be left unchanged")
+	}
+}
+
+func TestResetDestroysSessions(t *testing.T) {
+	ctx := context.Background()
+	broker, users, _, _ := newBroker()
+	sessions := &sessionRepository{}
+	broker.Sessions = sessions
+
+	token, err := broker.CreateToken(ctx, users.user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = broker.Reset(ctx, map[string]any{"email": "alice@example.com"}, token, "new password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sessions.destroyed) != 1 || sessions.destroyed[0] != 1 {
+		t.Fatalf("expected the sessions of the user to be destroyed, got %v", sessions.destroyed)
+	}
+}
+
+// replacingRepository stores a new token right before the current one is consumed.
+type replacingRepository struct {
+	*tokenRepository
+}
+
+func (r *replacingRepository) Consume(ctx context.Context, email string, token string) (bool, error) {
+	r.Create(ctx, &TokenRecord{Email: email, Token: "newer", CreatedAt: time.Now()})
+	return r.tokenRepository.Consume(ctx, email, token)
+}
@@ -0,0 +1,90 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wolftotem4/golava-core/auth/passwords"
+)
+
+type MySQLTokenRepository struct {
+	DB    *sql.DB
+	Table string
+}
+
+func NewMySQLTokenRepository(db *sql.DB, table string) *MySQLTokenRepository {
+	return &MySQLTokenRepository{
+		DB:    db,
+		Table: table,
+	}
+}
+
+func (d *MySQLTokenRepository) Create(ctx context.Context, record *passwords.TokenRecord) error {
+	createdAt := record.CreatedAt.Unix()
+	_, err := d.DB.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			"INSERT INTO `%s` (email, token, created_at) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE token = ?, created_at = ?",
+			d.Table,
+		),
+		record.Email, record.Token, createdAt, record.Token, createdAt,
+	)
+	return err
+}
+
+func (d *MySQLTokenRepository) Find(ctx context.Context, email string) (*passwords.TokenRecord, error) {
+	row := d.DB.QueryRowContext(ctx, fmt.Sprintf(
+		"SELECT email, token, created_at FROM `%s` WHERE email = ?", d.Table,
+	), email)
+
+	var (
+		record    passwords.TokenRecord
+		createdAt int64
+	)
+	err := row.Scan(&record.Email, &record.Token, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, passwords.ErrTokenNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	record.CreatedAt = time.Unix(createdAt, 0)
+	return &record, nil
+}
+
+func (d *MySQLTokenRepository) Delete(ctx context.Context, email string) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		"DELETE FROM `%s` WHERE email = ?", d.Table,
+	), email)
+	return err
+}
+
+func (d *MySQLTokenRepository) Consume(ctx context.Context, email string, token string) (bool, error) {
+	result, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		"DELETE FROM `%s` WHERE email = ? AND token = ?", d.Table,
+	), email, token)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (d *MySQLTokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		"DELETE FROM `%s` WHERE created_at < ?", d.Table,
+	), before.Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wolftotem4/golava-core/auth/passwords"
+)
+
+type PostgresTokenRepository struct {
+	DB    *sql.DB
+	Table string
+}
+
+func NewPostgresTokenRepository(db *sql.DB, table string) *PostgresTokenRepository {
+	return &PostgresTokenRepository{
+		DB:    db,
+		Table: table,
+	}
+}
+
+func (d *PostgresTokenRepository) Create(ctx context.Context, record *passwords.TokenRecord) error {
+	_, err := d.DB.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			`INSERT INTO "%s" (email, token, created_at) VALUES ($1, $2, $3) ON CONFLICT (email) DO UPDATE SET token = $2, created_at = $3`,
+			d.Table,
+		),
+		record.Email, record.Token, record.CreatedAt.Unix(),
+	)
+	return err
+}
+
+func (d *PostgresTokenRepository) Find(ctx context.Context, email string) (*passwords.TokenRecord, error) {
+	row := d.DB.QueryRowContext(ctx, fmt.Sprintf(
+		`SELECT email, token, created_at FROM "%s" WHERE email = $1`, d.Table,
+	), email)
+
+	var (
+		record    passwords.TokenRecord
+		createdAt int64
+	)
+	err := row.Scan(&record.Email, &record.Token, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, passwords.ErrTokenNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	record.CreatedAt = time.Unix(createdAt, 0)
+	return &record, nil
+}
+
+func (d *PostgresTokenRepository) Delete(ctx context.Context, email string) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		`DELETE FROM "%s" WHERE email = $1`, d.Table,
+	), email)
+	return err
+}
+
+func (d *PostgresTokenRepository) Consume(ctx context.Context, email string, token string) (bool, error) {
+	result, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		`DELETE FROM "%s" WHERE email = $1 AND token = $2`, d.Table,
+	), email, token)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (d *PostgresTokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		`DELETE FROM "%s" WHERE created_at < $1`, d.Table,
+	), before.Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,33 @@
+package passwords
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrTokenNotFound = errors.New("password reset token not found")
+
+// TokenRecord is a reset token as stored, hashed, for an email address.
+type TokenRecord struct {
+	Email     string
+	Token     string
+	CreatedAt time.Time
+}
+
+type TokenRepository interface {
+	// Create stores the token, replacing any previous token of the email address.
+	Create(ctx context.Context, record *TokenRecord) error
+
+	// Find returns ErrTokenNotFound when the email address has no token.
+	Find(ctx context.Context, email string) (*TokenRecord, error)
+
+	Delete(ctx context.Context, email string) error
+
+	// Consume deletes the token of the email address only if it is still the
+	// given one, and reports whether it did, so that a token is used once.
+	Consume(ctx context.Context, email string, token string) (bool, error)
+
+	// DeleteExpired removes the tokens created before the given time.
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,95 @@
+package sqlserver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wolftotem4/golava-core/auth/passwords"
+)
+
+type SQLServerTokenRepository struct {
+	DB    *sql.DB
+	Table string
+}
+
+func NewSQLServerTokenRepository(db *sql.DB, table string) *SQLServerTokenRepository {
+	return &SQLServerTokenRepository{
+		DB:    db,
+		Table: table,
+	}
+}
+
+func (d *SQLServerTokenRepository) Create(ctx context.Context, record *passwords.TokenRecord) error {
+	_, err := d.DB.ExecContext(
+		ctx,
+		fmt.Sprintf(`
+BEGIN tran
+	UPDATE [%s] WITH (serializable) SET token = @p2, created_at = @p3 WHERE email = @p1;
+	IF @@rowcount = 0
+	BEGIN
+		INSERT INTO [%[1]s] (email, token, created_at) VALUES (@p1, @p2, @p3);
+	END
+COMMIT tran`,
+			d.Table,
+		),
+		record.Email, record.Token, record.CreatedAt.Unix(),
+	)
+	return err
+}
+
+func (d *SQLServerTokenRepository) Find(ctx context.Context, email string) (*passwords.TokenRecord, error) {
+	row := d.DB.QueryRowContext(ctx, fmt.Sprintf(
+		"SELECT email, token, created_at FROM [%s] WHERE email = @p1", d.Table,
+	), email)
+
+	var (
+		record    passwords.TokenRecord
+		createdAt int64
+	)
+	err := row.Scan(&record.Email, &record.Token, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, passwords.ErrTokenNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	record.CreatedAt = time.Unix(createdAt, 0)
+	return &record, nil
+}
+
+func (d *SQLServerTokenRepository) Delete(ctx context.Context, email string) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		"DELETE FROM [%s] WHERE email = @p1", d.Table,
+	), email)
+	return err
+}
+
+func (d *SQLServerTokenRepository) Consume(ctx context.Context, email string, token string) (bool, error) {
+	result, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		"DELETE FROM [%s] WHERE email = @p1 AND token = @p2", d.Table,
+	), email, token)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (d *SQLServerTokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		"DELETE FROM [%s] WHERE created_at < @p1", d.Table,
+	), before.Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,65 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wolftotem4/golava-core/auth/rbac"
+)
+
+// Schema returns the statements creating the tables, with the unique keys the upserts of the provider rely on.
+func Schema(tables rbac.Tables) []string {
+	return []string{
+		fmt.Sprintf(
+			"CREATE TABLE IF NOT EXISTS `%s` (\n"+
+				"  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,\n"+
+				"  name VARCHAR(255) NOT NULL,\n"+
+				"  UNIQUE KEY name_unique (name)\n"+
+				")",
+			tables.Roles,
+		),
+		fmt.Sprintf(
+			"CREATE TABLE IF NOT EXISTS `%s` (\n"+
+				"  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,\n"+
+				"  name VARCHAR(255) NOT NULL,\n"+
+				"  UNIQUE KEY name_unique (name)\n"+
+				")",
+			tables.Permissions,
+		),
+		fmt.Sprintf(
+			"CREATE TABLE IF NOT EXISTS `%s` (\n"+
+				"  role_id BIGINT UNSIGNED NOT NULL,\n"+
+				"  user_id VARCHAR(255) NOT NULL,\n"+
+				"  PRIMARY KEY (role_id, user_id),\n"+
+				"  INDEX user_id_index (user_id),\n"+
+				"  FOREIGN KEY (role_id) REFERENCES `%s` (id) ON DELETE CASCADE\n"+
+				")",
+			tables.RoleUser, tables.Roles,
+		),
+		fmt.Sprintf(
+			"CREATE TABLE IF NOT EXISTS `%s` (\n"+
+				"  permission_id BIGINT UNSIGNED NOT NULL,\n"+
+				"  role_id BIGINT UNSIGNED NOT NULL,\n"+
+				"  PRIMARY KEY (permission_id, role_id),\n"+
+				"  INDEX role_id_index (role_id),\n"+
+				"  FOREIGN KEY (permission_id) REFERENCES `%s` (id) ON DELETE CASCADE,\n"+
+				"  FOREIGN KEY (role_id) REFERENCES `%s` (id) ON DELETE CASCADE\n"+
+				")",
+			tables.PermissionRole, tables.Permissions, tables.Roles,
+		),
+	}
+}
+
+func (d *MySQLRoleProvider) Schema() []string {
+	return Schema(d.Tables)
+}
+
+// CreateTables creates the tables of the roles, the permissions and their pivots, unless they exist.
+func (d *MySQLRoleProvider) CreateTables(ctx context.Context) error {
+	for _, statement := range d.Schema() {
+		if _, err := d.DB.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
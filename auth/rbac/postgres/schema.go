@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wolftotem4/golava-core/auth/rbac"
+)
+
+// Schema returns the statements creating the tables, with the unique keys the upserts of the provider rely on.
+func Schema(tables rbac.Tables) []string {
+	return []string{
+		fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS "%s" (`+"\n"+
+				"  id BIGSERIAL PRIMARY KEY,\n"+
+				"  name VARCHAR(255) NOT NULL UNIQUE\n"+
+				")",
+			tables.Roles,
+		),
+		fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS "%s" (`+"\n"+
+				"  id BIGSERIAL PRIMARY KEY,\n"+
+				"  name VARCHAR(255) NOT NULL UNIQUE\n"+
+				")",
+			tables.Permissions,
+		),
+		fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS "%[1]s" (`+"\n"+
+				"  role_id BIGINT NOT NULL REFERENCES \"%[2]s\" (id) ON DELETE CASCADE,\n"+
+				"  user_id VARCHAR(255) NOT NULL,\n"+
+				"  PRIMARY KEY (role_id, user_id)\n"+
+				")",
+			tables.RoleUser, tables.Roles,
+		),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS "%[1]s_user_id_index" ON "%[1]s" (user_id)`, tables.RoleUser),
+		fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS "%[1]s" (`+"\n"+
+				"  permission_id BIGINT NOT NULL REFERENCES \"%[2]s\" (id) ON DELETE CASCADE,\n"+
+				"  role_id BIGINT NOT NULL REFERENCES \"%[3]s\" (id) ON DELETE CASCADE,\n"+
+				"  PRIMARY KEY (permission_id, role_id)\n"+
+				")",
+			tables.PermissionRole, tables.Permissions, tables.Roles,
+		),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS "%[1]s_role_id_index" ON "%[1]s" (role_id)`, tables.PermissionRole),
+	}
+}
+
+func (d *PostgresRoleProvider) Schema() []string {
+	return Schema(d.Tables)
+}
+
+// CreateTables creates the tables of the roles, the permissions and their pivots, unless they exist.
+func (d *PostgresRoleProvider) CreateTables(ctx context.Context) error {
+	for _, statement := range d.Schema() {
+		if _, err := d.DB.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
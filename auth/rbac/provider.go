@@ -0,0 +1,43 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrRoleNotFound = errors.New("role not found")
+var ErrPermissionNotFound = errors.New("permission not found")
+var ErrMissingRole = errors.New("the user does not have the required role")
+var ErrMissingPermission = errors.New("the user does not have the required permission")
+
+// RoleProvider stores the roles of users and the permissions granted to roles.
+type RoleProvider interface {
+	RolesFor(ctx context.Context, userID any) ([]string, error)
+
+	// PermissionsFor returns the permissions granted through all the roles of the user.
+	PermissionsFor(ctx context.Context, userID any) ([]string, error)
+
+	CreateRole(ctx context.Context, role string) error
+	CreatePermission(ctx context.Context, permission string) error
+
+	AssignRole(ctx context.Context, userID any, role string) error
+	RemoveRole(ctx context.Context, userID any, role string) error
+
+	GrantPermission(ctx context.Context, role string, permission string) error
+	RevokePermission(ctx context.Context, role string, permission string) error
+}
+
+// Tables names the tables used by the database/sql providers.
+type Tables struct {
+	Roles          string
+	Permissions    string
+	RoleUser       string
+	PermissionRole string
+}
+
+var DefaultTables = Tables{
+	Roles:          "roles",
+	Permissions:    "permissions",
+	RoleUser:       "role_user",
+	PermissionRole: "permission_role",
+}
@@ -0,0 +1,152 @@
+// Package rbactest checks that rbac.RoleProvider implementations behave alike.
+//
+//	func TestProvider(t *testing.T) {
+//		rbactest.RunProviderSuite(t, func(t *testing.T) rbac.RoleProvider {
+//			return NewMyProvider(...)
+//		})
+//	}
+package rbactest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/wolftotem4/golava-core/auth/rbac"
+)
+
+// ProviderFactory returns a provider holding no roles nor permissions.
+type ProviderFactory func(t *testing.T) rbac.RoleProvider
+
+type suite struct {
+	factory ProviderFactory
+}
+
+// RunProviderSuite runs the conformance tests against fresh providers of the factory.
+func RunProviderSuite(t *testing.T, factory ProviderFactory) {
+	s := &suite{factory: factory}
+
+	t.Run("Roles", s.testRoles)
+	t.Run("Permissions", s.testPermissions)
+	t.Run("NotFound", s.testNotFound)
+}
+
+func (s *suite) testRoles(t *testing.T) {
+	ctx := context.Background()
+	provider := s.factory(t)
+
+	expectRoles(t, provider, 1)
+
+	for _, role := range []string{"editor", "admin", "admin"} {
+		must(t, provider.CreateRole(ctx, role))
+	}
+
+	// assigning twice is a no-op
+	must(t, provider.AssignRole(ctx, 1, "editor"))
+	must(t, provider.AssignRole(ctx, 1, "admin"))
+	must(t, provider.AssignRole(ctx, 1, "admin"))
+	must(t, provider.AssignRole(ctx, 2, "editor"))
+
+	expectRoles(t, provider, 1, "admin", "editor")
+	expectRoles(t, provider, 2, "editor")
+
+	must(t, provider.RemoveRole(ctx, 1, "admin"))
+	expectRoles(t, provider, 1, "editor")
+	expectRoles(t, provider, 2, "editor")
+
+	// removing a role the user does not have is a no-op
+	must(t, provider.RemoveRole(ctx, 2, "admin"))
+	expectRoles(t, provider, 2, "editor")
+}
+
+func (s *suite) testPermissions(t *testing.T) {
+	ctx := context.Background()
+	provider := s.factory(t)
+
+	for _, role := range []string{"editor", "admin"} {
+		must(t, provider.CreateRole(ctx, role))
+	}
+	for _, permission := range []string{"posts.edit", "posts.delete", "posts.edit"} {
+		must(t, provider.CreatePermission(ctx, permission))
+	}
+
+	must(t, provider.GrantPermission(ctx, "editor", "posts.edit"))
+	must(t, provider.GrantPermission(ctx, "admin", "posts.edit"))
+	must(t, provider.GrantPermission(ctx, "admin", "posts.delete"))
+	must(t, provider.GrantPermission(ctx, "admin", "posts.delete"))
+
+	must(t, provider.AssignRole(ctx, 1, "editor"))
+	must(t, provider.AssignRole(ctx, 1, "admin"))
+	must(t, provider.AssignRole(ctx, 2, "editor"))
+
+	// permissions granted by several roles are listed once
+	expectPermissions(t, provider, 1, "posts.delete", "posts.edit")
+	expectPermissions(t, provider, 2, "posts.edit")
+	expectPermissions(t, provider, 3)
+
+	must(t, provider.RevokePermission(ctx, "admin", "posts.delete"))
+	expectPermissions(t, provider, 1, "posts.edit")
+
+	must(t, provider.RevokePermission(ctx, "editor", "posts.edit"))
+	expectPermissions(t, provider, 1, "posts.edit")
+	expectPermissions(t, provider, 2)
+}
+
+func (s *suite) testNotFound(t *testing.T) {
+	ctx := context.Background()
+	provider := s.factory(t)
+
+	must(t, provider.CreateRole(ctx, "admin"))
+
+	if err := provider.AssignRole(ctx, 1, "missing"); !errors.Is(err, rbac.ErrRoleNotFound) {
+		t.Errorf("AssignRole of a missing role: expected ErrRoleNotFound, got %v", err)
+	}
+	if err := provider.GrantPermission(ctx, "missing", "posts.edit"); !errors.Is(err, rbac.ErrRoleNotFound) {
+		t.Errorf("GrantPermission to a missing role: expected ErrRoleNotFound, got %v", err)
+	}
+	if err := provider.GrantPermission(ctx, "admin", "missing"); !errors.Is(err, rbac.ErrPermissionNotFound) {
+		t.Errorf("GrantPermission of a missing permission: expected ErrPermissionNotFound, got %v", err)
+	}
+
+	expectRoles(t, provider, 1)
+}
+
+func must(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func expectRoles(t *testing.T, provider rbac.RoleProvider, userID any, expected ...string) {
+	t.Helper()
+
+	roles, err := provider.RolesFor(context.Background(), userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectNames(t, "roles", userID, roles, expected)
+}
+
+func expectPermissions(t *testing.T, provider rbac.RoleProvider, userID any, expected ...string) {
+	t.Helper()
+
+	permissions, err := provider.PermissionsFor(context.Background(), userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectNames(t, "permissions", userID, permissions, expected)
+}
+
+func expectNames(t *testing.T, kind string, userID any, actual []string, expected []string) {
+	t.Helper()
+
+	if expected == nil {
+		expected = []string{}
+	}
+	if actual == nil || !reflect.DeepEqual(actual, expected) {
+		t.Errorf("%s of user %v: expected %q, got %q", kind, userID, expected, actual)
+	}
+}
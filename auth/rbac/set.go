@@ -0,0 +1,115 @@
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wolftotem4/golava-core/auth"
+)
+
+const contextKey = "rbac"
+
+// Set is the roles and permissions of a user. Each is loaded from the provider
+// at most once, when first needed.
+type Set struct {
+	provider RoleProvider
+	userID   any
+
+	mu          sync.Mutex
+	roles       []string
+	permissions []string
+	rolesOk     bool
+	permsOk     bool
+}
+
+func NewSet(provider RoleProvider, userID any) *Set {
+	return &Set{provider: provider, userID: userID}
+}
+
+// ForRequest returns the set of the user, cached for the rest of the request.
+func ForRequest(c *gin.Context, provider RoleProvider, user auth.Authenticatable) *Set {
+	var cache map[string]*Set
+	if value, ok := c.Get(contextKey); ok {
+		cache, _ = value.(map[string]*Set)
+	}
+
+	if cache == nil {
+		cache = make(map[string]*Set)
+		c.Set(contextKey, cache)
+	}
+
+	key := fmt.Sprintf("%v", user.GetAuthIdentifier())
+	set, ok := cache[key]
+	if !ok {
+		set = NewSet(provider, user.GetAuthIdentifier())
+		cache[key] = set
+	}
+
+	return set
+}
+
+func (s *Set) Roles(ctx context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.rolesOk {
+		roles, err := s.provider.RolesFor(ctx, s.userID)
+		if err != nil {
+			return nil, err
+		}
+		s.roles, s.rolesOk = roles, true
+	}
+
+	return s.roles, nil
+}
+
+func (s *Set) Permissions(ctx context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.permsOk {
+		permissions, err := s.provider.PermissionsFor(ctx, s.userID)
+		if err != nil {
+			return nil, err
+		}
+		s.permissions, s.permsOk = permissions, true
+	}
+
+	return s.permissions, nil
+}
+
+// HasAnyRole reports whether the user has at least one of the roles.
+func (s *Set) HasAnyRole(ctx context.Context, roles ...string) (bool, error) {
+	owned, err := s.Roles(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return slices.ContainsFunc(roles, func(role string) bool {
+		return slices.Contains(owned, role)
+	}), nil
+}
+
+// HasAllPermissions reports whether the user has every one of the permissions.
+func (s *Set) HasAllPermissions(ctx context.Context, permissions ...string) (bool, error) {
+	owned, err := s.Permissions(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return !slices.ContainsFunc(permissions, func(permission string) bool {
+		return !slices.Contains(owned, permission)
+	}), nil
+}
+
+// Forget discards the loaded roles and permissions, e.g. after assigning a role.
+func (s *Set) Forget() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.roles, s.permissions = nil, nil
+	s.rolesOk, s.permsOk = false, false
+}
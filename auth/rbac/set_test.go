@@ -0,0 +1,82 @@
+package rbac
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type user struct {
+	id int
+}
+
+func (u *user) GetAuthIdentifierName() string { return "id" }
+func (u *user) GetAuthIdentifier() any        { return u.id }
+func (u *user) GetAuthPasswordName() string   { return "password" }
+func (u *user) GetAuthPassword() string       { return "" }
+func (u *user) GetRememberToken() string      { return "" }
+func (u *user) SetRememberToken(string)       {}
+func (u *user) GetRememberTokenName() string  { return "remember_token" }
+
+type countingProvider struct {
+	RoleProvider
+	roleQueries       int
+	permissionQueries int
+}
+
+func (p *countingProvider) RolesFor(ctx context.Context, userID any) ([]string, error) {
+	p.roleQueries++
+	if userID == 1 {
+		return []string{"editor"}, nil
+	}
+	return []string{}, nil
+}
+
+func (p *countingProvider) PermissionsFor(ctx context.Context, userID any) ([]string, error) {
+	p.permissionQueries++
+	if userID == 1 {
+		return []string{"posts.create", "posts.update"}, nil
+	}
+	return []string{}, nil
+}
+
+func TestForRequest_Caching(t *testing.T) {
+	ctx := context.Background()
+	provider := &countingProvider{}
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	editor := &user{id: 1}
+
+	for range 3 {
+		set := ForRequest(c, provider, editor)
+
+		if ok, _ := set.HasAnyRole(ctx, "admin", "editor"); !ok {
+			t.Error("expected editor role")
+		}
+
+		if ok, _ := set.HasAllPermissions(ctx, "posts.create", "posts.update"); !ok {
+			t.Error("expected permissions")
+		}
+
+		if ok, _ := set.HasAllPermissions(ctx, "posts.create", "posts.delete"); ok {
+			t.Error("expected missing permission")
+		}
+	}
+
+	if provider.roleQueries != 1 || provider.permissionQueries != 1 {
+		t.Errorf("expected a single query each, got %d roles and %d permissions queries", provider.roleQueries, provider.permissionQueries)
+	}
+
+	if ok, _ := ForRequest(c, provider, &user{id: 2}).HasAnyRole(ctx, "editor"); ok {
+		t.Error("expected cache to be keyed by user")
+	}
+
+	set := ForRequest(c, provider, editor)
+	set.Forget()
+	set.Roles(ctx)
+	if provider.roleQueries != 3 {
+		t.Errorf("expected Forget to reload roles, got %d queries", provider.roleQueries)
+	}
+}
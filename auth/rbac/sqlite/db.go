@@ -0,0 +1,131 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/wolftotem4/golava-core/auth/rbac"
+)
+
+type SqliteRoleProvider struct {
+	DB     *sql.DB
+	Tables rbac.Tables
+}
+
+func NewSqliteRoleProvider(db *sql.DB, tables rbac.Tables) *SqliteRoleProvider {
+	return &SqliteRoleProvider{
+		DB:     db,
+		Tables: tables,
+	}
+}
+
+func (d *SqliteRoleProvider) RolesFor(ctx context.Context, userID any) ([]string, error) {
+	rows, err := d.DB.QueryContext(ctx, fmt.Sprintf(
+		`SELECT r.name FROM "%s" r INNER JOIN "%s" ru ON ru.role_id = r.id WHERE ru.user_id = $1 ORDER BY r.name`,
+		d.Tables.Roles, d.Tables.RoleUser,
+	), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanNames(rows)
+}
+
+func (d *SqliteRoleProvider) PermissionsFor(ctx context.Context, userID any) ([]string, error) {
+	rows, err := d.DB.QueryContext(ctx, fmt.Sprintf(
+		`SELECT DISTINCT p.name FROM "%s" p INNER JOIN "%s" pr ON pr.permission_id = p.id INNER JOIN "%s" ru ON ru.role_id = pr.role_id WHERE ru.user_id = $1 ORDER BY p.name`,
+		d.Tables.Permissions, d.Tables.PermissionRole, d.Tables.RoleUser,
+	), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanNames(rows)
+}
+
+func (d *SqliteRoleProvider) CreateRole(ctx context.Context, role string) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		`INSERT INTO "%s" (name) VALUES ($1) ON CONFLICT (name) DO NOTHING`, d.Tables.Roles,
+	), role)
+	return err
+}
+
+func (d *SqliteRoleProvider) CreatePermission(ctx context.Context, permission string) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		`INSERT INTO "%s" (name) VALUES ($1) ON CONFLICT (name) DO NOTHING`, d.Tables.Permissions,
+	), permission)
+	return err
+}
+
+func (d *SqliteRoleProvider) AssignRole(ctx context.Context, userID any, role string) error {
+	roleId, err := d.findId(ctx, d.Tables.Roles, role, rbac.ErrRoleNotFound)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.DB.ExecContext(ctx, fmt.Sprintf(
+		`INSERT INTO "%s" (role_id, user_id) VALUES ($1, $2) ON CONFLICT (role_id, user_id) DO NOTHING`, d.Tables.RoleUser,
+	), roleId, userID)
+	return err
+}
+
+func (d *SqliteRoleProvider) RemoveRole(ctx context.Context, userID any, role string) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		`DELETE FROM "%s" WHERE user_id = $1 AND role_id IN (SELECT id FROM "%s" WHERE name = $2)`,
+		d.Tables.RoleUser, d.Tables.Roles,
+	), userID, role)
+	return err
+}
+
+func (d *SqliteRoleProvider) GrantPermission(ctx context.Context, role string, permission string) error {
+	roleId, err := d.findId(ctx, d.Tables.Roles, role, rbac.ErrRoleNotFound)
+	if err != nil {
+		return err
+	}
+
+	permissionId, err := d.findId(ctx, d.Tables.Permissions, permission, rbac.ErrPermissionNotFound)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.DB.ExecContext(ctx, fmt.Sprintf(
+		`INSERT INTO "%s" (permission_id, role_id) VALUES ($1, $2) ON CONFLICT (permission_id, role_id) DO NOTHING`, d.Tables.PermissionRole,
+	), permissionId, roleId)
+	return err
+}
+
+func (d *SqliteRoleProvider) RevokePermission(ctx context.Context, role string, permission string) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		`DELETE FROM "%s" WHERE role_id IN (SELECT id FROM "%s" WHERE name = $1) AND permission_id IN (SELECT id FROM "%s" WHERE name = $2)`,
+		d.Tables.PermissionRole, d.Tables.Roles, d.Tables.Permissions,
+	), role, permission)
+	return err
+}
+
+func (d *SqliteRoleProvider) findId(ctx context.Context, table string, name string, notFound error) (int64, error) {
+	var id int64
+	err := d.DB.QueryRowContext(ctx, fmt.Sprintf(
+		`SELECT id FROM "%s" WHERE name = $1`, table,
+	), name).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, notFound
+	}
+	return id, err
+}
+
+func scanNames(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+
+	return names, rows.Err()
+}
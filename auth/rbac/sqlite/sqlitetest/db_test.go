@@ -0,0 +1,34 @@
+package sqlitetest
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/wolftotem4/golava-core/auth/rbac"
+	"github.com/wolftotem4/golava-core/auth/rbac/rbactest"
+	"github.com/wolftotem4/golava-core/auth/rbac/sqlite"
+)
+
+func TestSqliteRoleProviderSuite(t *testing.T) {
+	rbactest.RunProviderSuite(t, func(t *testing.T) rbac.RoleProvider {
+		db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "rbac.db")+"?_foreign_keys=1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+
+		provider := sqlite.NewSqliteRoleProvider(db, rbac.DefaultTables)
+		if err := provider.CreateTables(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		// creating the tables again is a no-op
+		if err := provider.CreateTables(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		return provider
+	})
+}
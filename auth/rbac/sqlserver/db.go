@@ -0,0 +1,131 @@
+package sqlserver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/wolftotem4/golava-core/auth/rbac"
+)
+
+type SQLServerRoleProvider struct {
+	DB     *sql.DB
+	Tables rbac.Tables
+}
+
+func NewSQLServerRoleProvider(db *sql.DB, tables rbac.Tables) *SQLServerRoleProvider {
+	return &SQLServerRoleProvider{
+		DB:     db,
+		Tables: tables,
+	}
+}
+
+func (d *SQLServerRoleProvider) RolesFor(ctx context.Context, userID any) ([]string, error) {
+	rows, err := d.DB.QueryContext(ctx, fmt.Sprintf(
+		"SELECT r.name FROM [%s] r INNER JOIN [%s] ru ON ru.role_id = r.id WHERE ru.user_id = @p1 ORDER BY r.name",
+		d.Tables.Roles, d.Tables.RoleUser,
+	), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanNames(rows)
+}
+
+func (d *SQLServerRoleProvider) PermissionsFor(ctx context.Context, userID any) ([]string, error) {
+	rows, err := d.DB.QueryContext(ctx, fmt.Sprintf(
+		"SELECT DISTINCT p.name FROM [%s] p INNER JOIN [%s] pr ON pr.permission_id = p.id INNER JOIN [%s] ru ON ru.role_id = pr.role_id WHERE ru.user_id = @p1 ORDER BY p.name",
+		d.Tables.Permissions, d.Tables.PermissionRole, d.Tables.RoleUser,
+	), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanNames(rows)
+}
+
+func (d *SQLServerRoleProvider) CreateRole(ctx context.Context, role string) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		"IF NOT EXISTS (SELECT 1 FROM [%[1]s] WHERE name = @p1) INSERT INTO [%[1]s] (name) VALUES (@p1)", d.Tables.Roles,
+	), role)
+	return err
+}
+
+func (d *SQLServerRoleProvider) CreatePermission(ctx context.Context, permission string) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		"IF NOT EXISTS (SELECT 1 FROM [%[1]s] WHERE name = @p1) INSERT INTO [%[1]s] (name) VALUES (@p1)", d.Tables.Permissions,
+	), permission)
+	return err
+}
+
+func (d *SQLServerRoleProvider) AssignRole(ctx context.Context, userID any, role string) error {
+	roleId, err := d.findId(ctx, d.Tables.Roles, role, rbac.ErrRoleNotFound)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.DB.ExecContext(ctx, fmt.Sprintf(
+		"IF NOT EXISTS (SELECT 1 FROM [%[1]s] WHERE role_id = @p1 AND user_id = @p2) INSERT INTO [%[1]s] (role_id, user_id) VALUES (@p1, @p2)", d.Tables.RoleUser,
+	), roleId, userID)
+	return err
+}
+
+func (d *SQLServerRoleProvider) RemoveRole(ctx context.Context, userID any, role string) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		"DELETE ru FROM [%s] ru INNER JOIN [%s] r ON r.id = ru.role_id WHERE ru.user_id = @p1 AND r.name = @p2",
+		d.Tables.RoleUser, d.Tables.Roles,
+	), userID, role)
+	return err
+}
+
+func (d *SQLServerRoleProvider) GrantPermission(ctx context.Context, role string, permission string) error {
+	roleId, err := d.findId(ctx, d.Tables.Roles, role, rbac.ErrRoleNotFound)
+	if err != nil {
+		return err
+	}
+
+	permissionId, err := d.findId(ctx, d.Tables.Permissions, permission, rbac.ErrPermissionNotFound)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.DB.ExecContext(ctx, fmt.Sprintf(
+		"IF NOT EXISTS (SELECT 1 FROM [%[1]s] WHERE permission_id = @p1 AND role_id = @p2) INSERT INTO [%[1]s] (permission_id, role_id) VALUES (@p1, @p2)", d.Tables.PermissionRole,
+	), permissionId, roleId)
+	return err
+}
+
+func (d *SQLServerRoleProvider) RevokePermission(ctx context.Context, role string, permission string) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		"DELETE pr FROM [%s] pr INNER JOIN [%s] r ON r.id = pr.role_id INNER JOIN [%s] p ON p.id = pr.permission_id WHERE r.name = @p1 AND p.name = @p2",
+		d.Tables.PermissionRole, d.Tables.Roles, d.Tables.Permissions,
+	), role, permission)
+	return err
+}
+
+func (d *SQLServerRoleProvider) findId(ctx context.Context, table string, name string, notFound error) (int64, error) {
+	var id int64
+	err := d.DB.QueryRowContext(ctx, fmt.Sprintf(
+		"SELECT id FROM [%s] WHERE name = @p1", table,
+	), name).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, notFound
+	}
+	return id, err
+}
+
+func scanNames(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+
+	return names, rows.Err()
+}
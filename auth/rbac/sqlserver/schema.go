@@ -0,0 +1,72 @@
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wolftotem4/golava-core/auth/rbac"
+)
+
+// Schema returns the statements creating the tables, with the unique keys the upserts of the provider rely on.
+func Schema(tables rbac.Tables) []string {
+	return []string{
+		fmt.Sprintf(
+			"IF OBJECT_ID(N'[%[1]s]', N'U') IS NULL\n"+
+				"CREATE TABLE [%[1]s] (\n"+
+				"  id BIGINT IDENTITY(1,1) NOT NULL PRIMARY KEY,\n"+
+				"  name NVARCHAR(255) NOT NULL UNIQUE\n"+
+				")",
+			tables.Roles,
+		),
+		fmt.Sprintf(
+			"IF OBJECT_ID(N'[%[1]s]', N'U') IS NULL\n"+
+				"CREATE TABLE [%[1]s] (\n"+
+				"  id BIGINT IDENTITY(1,1) NOT NULL PRIMARY KEY,\n"+
+				"  name NVARCHAR(255) NOT NULL UNIQUE\n"+
+				")",
+			tables.Permissions,
+		),
+		fmt.Sprintf(
+			"IF OBJECT_ID(N'[%[1]s]', N'U') IS NULL\n"+
+				"CREATE TABLE [%[1]s] (\n"+
+				"  role_id BIGINT NOT NULL REFERENCES [%[2]s] (id) ON DELETE CASCADE,\n"+
+				"  user_id NVARCHAR(255) NOT NULL,\n"+
+				"  PRIMARY KEY (role_id, user_id)\n"+
+				")",
+			tables.RoleUser, tables.Roles,
+		),
+		createIndex(tables.RoleUser, "user_id"),
+		fmt.Sprintf(
+			"IF OBJECT_ID(N'[%[1]s]', N'U') IS NULL\n"+
+				"CREATE TABLE [%[1]s] (\n"+
+				"  permission_id BIGINT NOT NULL REFERENCES [%[2]s] (id) ON DELETE CASCADE,\n"+
+				"  role_id BIGINT NOT NULL REFERENCES [%[3]s] (id) ON DELETE CASCADE,\n"+
+				"  PRIMARY KEY (permission_id, role_id)\n"+
+				")",
+			tables.PermissionRole, tables.Permissions, tables.Roles,
+		),
+		createIndex(tables.PermissionRole, "role_id"),
+	}
+}
+
+func createIndex(table string, column string) string {
+	return fmt.Sprintf(
+		"IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'%[1]s_%[2]s_index' AND object_id = OBJECT_ID(N'[%[1]s]'))\n"+
+			"CREATE INDEX [%[1]s_%[2]s_index] ON [%[1]s] (%[2]s)",
+		table, column,
+	)
+}
+
+func (d *SQLServerRoleProvider) Schema() []string {
+	return Schema(d.Tables)
+}
+
+// CreateTables creates the tables of the roles, the permissions and their pivots, unless they exist.
+func (d *SQLServerRoleProvider) CreateTables(ctx context.Context) error {
+	for _, statement := range d.Schema() {
+		if _, err := d.DB.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package social
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrProviderNotFound = errors.New("social provider not found")
+	ErrInvalidState     = errors.New("invalid or expired social login state")
+	ErrMissingCode      = errors.New("missing authorization code")
+	ErrInvalidIDToken   = errors.New("invalid ID token")
+	ErrNonceMismatch    = errors.New("ID token nonce mismatch")
+	ErrAccessDenied     = errors.New("social login was denied")
+)
+
+// AuthorizationError is returned when the provider redirects back with an
+// error, e.g. when the user denies access. It matches ErrAccessDenied.
+type AuthorizationError struct {
+	Code        string
+	Description string
+}
+
+func (e *AuthorizationError) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("%s: %s (%s)", ErrAccessDenied, e.Code, e.Description)
+	}
+	return fmt.Sprintf("%s: %s", ErrAccessDenied, e.Code)
+}
+
+func (e *AuthorizationError) Is(target error) bool {
+	return target == ErrAccessDenied
+}
+
+// ResponseError is returned when a provider endpoint answers with an unexpected status.
+type ResponseError struct {
+	Endpoint   string
+	StatusCode int
+	Body       string
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("social: %s responded with status %d: %s", e.Endpoint, e.StatusCode, e.Body)
+}
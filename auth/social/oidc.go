@@ -0,0 +1,390 @@
+package social
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wolftotem4/golava-core/auth/jwt"
+)
+
+// Metadata is the provider configuration published at /.well-known/openid-configuration.
+type Metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// OIDCProvider is an OpenID Connect provider configured through discovery.
+type OIDCProvider struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+
+	// Defaults to "openid", "email" and "profile".
+	Scopes []string
+
+	// Extra parameters of the authorization request, e.g. "prompt".
+	AuthParams url.Values
+
+	// Clock skew tolerated when checking the ID token.
+	Leeway time.Duration
+
+	// Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// Minimum time between two fetches of the signing keys prompted by an ID token
+	// signed with an unknown key. Defaults to one minute.
+	KeysRefreshInterval time.Duration
+
+	mu            sync.Mutex
+	metadata      *Metadata
+	keys          *jwt.KeySet
+	keysFetchedAt time.Time
+}
+
+func NewOIDCProvider(issuer string, clientID string, clientSecret string, redirectURL string) *OIDCProvider {
+	return &OIDCProvider{
+		Issuer:       issuer,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+	}
+}
+
+func Google(clientID string, clientSecret string, redirectURL string) *OIDCProvider {
+	return NewOIDCProvider("https://accounts.google.com", clientID, clientSecret, redirectURL)
+}
+
+func GitLab(clientID string, clientSecret string, redirectURL string) *OIDCProvider {
+	return NewOIDCProvider("https://gitlab.com", clientID, clientSecret, redirectURL)
+}
+
+// Discover fetches the provider metadata once and caches it.
+func (p *OIDCProvider) Discover(ctx context.Context) (*Metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	var metadata Metadata
+	err := p.getJSON(ctx, strings.TrimSuffix(p.Issuer, "/")+"/.well-known/openid-configuration", "", &metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	if metadata.Issuer != p.Issuer {
+		return nil, fmt.Errorf("social: discovered issuer %q does not match %q", metadata.Issuer, p.Issuer)
+	}
+
+	p.metadata = &metadata
+	return p.metadata, nil
+}
+
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, state string, codeChallenge string, nonce string) (string, error) {
+	metadata, err := p.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	for key, values := range p.AuthParams {
+		query[key] = values
+	}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.ClientID)
+	query.Set("redirect_uri", p.RedirectURL)
+	query.Set("scope", strings.Join(p.scopes(), " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(metadata.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return metadata.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+func (p *OIDCProvider) Exchange(ctx context.Context, code string, codeVerifier string) (*Token, error) {
+	metadata, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.ClientID), url.QueryEscape(p.ClientSecret))
+
+	var response struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		TokenType    string `json:"token_type"`
+		IDToken      string `json:"id_token"`
+		ExpiresIn    int64  `json:"expires_in"`
+	}
+	err = p.do(req, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	token := &Token{
+		AccessToken:  response.AccessToken,
+		RefreshToken: response.RefreshToken,
+		TokenType:    response.TokenType,
+		IDToken:      response.IDToken,
+	}
+	if response.ExpiresIn > 0 {
+		token.ExpiresAt = time.Now().Add(time.Duration(response.ExpiresIn) * time.Second)
+	}
+
+	return token, nil
+}
+
+// User verifies the ID token and completes its claims with the userinfo endpoint.
+func (p *OIDCProvider) User(ctx context.Context, token *Token, nonce string) (*User, error) {
+	metadata, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.IDToken == "" {
+		return nil, fmt.Errorf("%w: missing from the token response", ErrInvalidIDToken)
+	}
+
+	claims, err := p.verifyIDToken(ctx, metadata, token.IDToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// a missing nonce on either side would let a replayed ID token through
+	tokenNonce, _ := claims.Extra["nonce"].(string)
+	if nonce == "" || tokenNonce == "" || subtle.ConstantTimeCompare([]byte(tokenNonce), []byte(nonce)) != 1 {
+		return nil, ErrNonceMismatch
+	}
+
+	raw := map[string]any{"sub": claims.Subject}
+	for key, value := range claims.Extra {
+		raw[key] = value
+	}
+
+	if metadata.UserinfoEndpoint != "" && token.AccessToken != "" {
+		var userinfo map[string]any
+		err := p.getJSON(ctx, metadata.UserinfoEndpoint, token.AccessToken, &userinfo)
+		if err != nil {
+			return nil, err
+		}
+
+		// the userinfo response must be about the same user
+		if sub, _ := userinfo["sub"].(string); sub == claims.Subject {
+			for key, value := range userinfo {
+				raw[key] = value
+			}
+		}
+	}
+
+	user := &User{ID: claims.Subject, Raw: raw, Token: token}
+	user.Email, _ = raw["email"].(string)
+	user.EmailVerified, _ = raw["email_verified"].(bool)
+	user.Name, _ = raw["name"].(string)
+	user.Nickname, _ = raw["preferred_username"].(string)
+	user.Avatar, _ = raw["picture"].(string)
+
+	return user, nil
+}
+
+func (p *OIDCProvider) verifyIDToken(ctx context.Context, metadata *Metadata, idToken string) (*jwt.Claims, error) {
+	claims, err := p.parseIDToken(ctx, metadata, idToken, false)
+	if errors.Is(err, jwt.ErrUnknownKey) {
+		// the provider may have rotated its keys
+		claims, err = p.parseIDToken(ctx, metadata, idToken, true)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIDToken, err)
+	}
+
+	if claims.Subject == "" || claims.ExpiresAt == 0 {
+		return nil, ErrInvalidIDToken
+	}
+
+	return claims, nil
+}
+
+func (p *OIDCProvider) parseIDToken(ctx context.Context, metadata *Metadata, idToken string, refresh bool) (*jwt.Claims, error) {
+	keys, err := p.keySet(ctx, metadata, refresh)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := &jwt.JWT{
+		Keys:     keys,
+		Issuer:   metadata.Issuer,
+		Audience: []string{p.ClientID},
+		Leeway:   p.Leeway,
+	}
+	return parser.Parse(ctx, idToken)
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+}
+
+// keySet returns the provider's signing keys, fetching them again on refresh.
+// Refreshes are prompted by unverified ID tokens, so they are rate limited and
+// made without holding the lock.
+func (p *OIDCProvider) keySet(ctx context.Context, metadata *Metadata, refresh bool) (*jwt.KeySet, error) {
+	p.mu.Lock()
+	keys := p.keys
+	if keys != nil && (!refresh || time.Since(p.keysFetchedAt) < p.keysRefreshInterval()) {
+		p.mu.Unlock()
+		return keys, nil
+	}
+	// Claimed before fetching, so that concurrent callbacks keep using the current keys.
+	p.keysFetchedAt = time.Now()
+	p.mu.Unlock()
+
+	keys, err := p.fetchKeySet(ctx, metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.mu.Unlock()
+
+	return keys, nil
+}
+
+// fetchKeySet fetches the provider's signing keys. Only RSA and Ed25519 keys are supported.
+func (p *OIDCProvider) fetchKeySet(ctx context.Context, metadata *Metadata) (*jwt.KeySet, error) {
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	err := p.getJSON(ctx, metadata.JWKSURI, "", &jwks)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := jwt.NewKeySet(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		switch {
+		case jwk.Kty == "RSA":
+			n, errN := base64.RawURLEncoding.DecodeString(jwk.N)
+			e, errE := base64.RawURLEncoding.DecodeString(jwk.E)
+			if errN != nil || errE != nil || len(e) > 4 {
+				continue
+			}
+			err = keys.Add(jwt.RSAPublicKey(jwk.Kid, &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			}))
+
+		case jwk.Kty == "OKP" && jwk.Crv == "Ed25519":
+			x, errX := base64.RawURLEncoding.DecodeString(jwk.X)
+			if errX != nil || len(x) != ed25519.PublicKeySize {
+				continue
+			}
+			err = keys.Add(jwt.EdDSAPublicKey(jwk.Kid, ed25519.PublicKey(x)))
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return keys, nil
+}
+
+func (p *OIDCProvider) keysRefreshInterval() time.Duration {
+	if p.KeysRefreshInterval > 0 {
+		return p.KeysRefreshInterval
+	}
+	return time.Minute
+}
+
+func (p *OIDCProvider) getJSON(ctx context.Context, endpoint string, accessToken string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	return p.do(req, v)
+}
+
+func (p *OIDCProvider) do(req *http.Request, v any) error {
+	client := p.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return &ResponseError{Endpoint: req.URL.Redacted(), StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	return json.Unmarshal(body, v)
+}
+
+func (p *OIDCProvider) scopes() []string {
+	if len(p.Scopes) > 0 {
+		return p.Scopes
+	}
+	return []string{"openid", "email", "profile"}
+}
@@ -0,0 +1,43 @@
+package social
+
+import (
+	"context"
+	"time"
+)
+
+// User is the identity returned by an external provider.
+type User struct {
+	// The provider's identifier of the user, e.g. the "sub" claim.
+	ID            string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Nickname      string
+	Avatar        string
+
+	// All the claims received from the provider.
+	Raw map[string]any
+
+	Token *Token
+}
+
+type Token struct {
+	AccessToken  string
+	RefreshToken string
+	TokenType    string
+	IDToken      string
+	ExpiresAt    time.Time
+}
+
+type Provider interface {
+	// AuthCodeURL returns the URL of the provider's authorization page.
+	// The code challenge is the S256 PKCE challenge.
+	AuthCodeURL(ctx context.Context, state string, codeChallenge string, nonce string) (string, error)
+
+	// Exchange trades the authorization code for tokens.
+	Exchange(ctx context.Context, code string, codeVerifier string) (*Token, error)
+
+	// User returns the identity of the token's owner. Providers issuing ID
+	// tokens reject a token whose nonce is missing or differs from the nonce.
+	User(ctx context.Context, token *Token, nonce string) (*User, error)
+}
@@ -0,0 +1,150 @@
+package social
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/wolftotem4/golava-core/auth"
+	"github.com/wolftotem4/golava-core/auth/generic"
+	"github.com/wolftotem4/golava-core/session"
+	"github.com/wolftotem4/golava-core/util"
+)
+
+// Resolver maps an external identity to a local user, e.g. by finding or creating
+// the user linked to the provider's user ID.
+type Resolver func(ctx context.Context, provider string, user *User) (auth.Authenticatable, error)
+
+// Social logs users in through external OAuth2/OpenID Connect providers.
+type Social struct {
+	Resolver Resolver
+
+	// How long the user has to complete the login at the provider. Defaults to 10 minutes.
+	Timeout time.Duration
+
+	mu        sync.RWMutex
+	providers map[string]Provider
+}
+
+func New(resolver Resolver) *Social {
+	return &Social{
+		Resolver:  resolver,
+		providers: make(map[string]Provider),
+	}
+}
+
+func (s *Social) Register(name string, provider Provider) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.providers[name] = provider
+}
+
+func (s *Social) Provider(name string) (Provider, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	provider, ok := s.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrProviderNotFound, name)
+	}
+	return provider, nil
+}
+
+// Redirect returns the URL to redirect the user to, after keeping the state,
+// the PKCE verifier and the nonce in the session.
+func (s *Social) Redirect(ctx context.Context, store *session.Store, name string) (string, error) {
+	provider, err := s.Provider(name)
+	if err != nil {
+		return "", err
+	}
+
+	state := util.RandomString(40)
+	verifier := util.RandomString(64)
+	nonce := util.RandomString(32)
+
+	store.Put(sessionKey(name), map[string]any{
+		"state":         state,
+		"code_verifier": verifier,
+		"nonce":         nonce,
+		"expires_at":    time.Now().Add(s.timeout()).Unix(),
+	})
+
+	return provider.AuthCodeURL(ctx, state, codeChallenge(verifier), nonce)
+}
+
+// Callback validates the state of the provider's redirect, exchanges the code
+// and returns the external identity.
+func (s *Social) Callback(ctx context.Context, store *session.Store, name string, query url.Values) (*User, error) {
+	provider, err := s.Provider(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// the state is single-use, whatever the outcome
+	value, ok := store.Get(sessionKey(name))
+	store.Forget(sessionKey(name))
+
+	data, _ := value.(map[string]any)
+	state, _ := data["state"].(string)
+	verifier, _ := data["code_verifier"].(string)
+	nonce, _ := data["nonce"].(string)
+	expiresAt, _ := data["expires_at"].(int64)
+
+	if !ok || state == "" || expiresAt <= time.Now().Unix() ||
+		subtle.ConstantTimeCompare([]byte(state), []byte(query.Get("state"))) != 1 {
+		return nil, ErrInvalidState
+	}
+
+	if code := query.Get("error"); code != "" {
+		return nil, &AuthorizationError{Code: code, Description: query.Get("error_description")}
+	}
+
+	code := query.Get("code")
+	if code == "" {
+		return nil, ErrMissingCode
+	}
+
+	token, err := provider.Exchange(ctx, code, verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.User(ctx, token, nonce)
+}
+
+// Login completes the callback, resolves the local user and logs them in through the guard.
+func (s *Social) Login(ctx context.Context, guard *generic.SessionGuard, name string, query url.Values, remember bool) (auth.Authenticatable, *User, error) {
+	external, err := s.Callback(ctx, guard.Session.Store, name, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := s.Resolver(ctx, name, external)
+	if err != nil {
+		return nil, external, err
+	}
+
+	return user, external, guard.Login(ctx, user, remember)
+}
+
+func (s *Social) timeout() time.Duration {
+	if s.Timeout > 0 {
+		return s.Timeout
+	}
+	return 10 * time.Minute
+}
+
+func sessionKey(provider string) string {
+	return "social." + provider
+}
+
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,287 @@
+package social
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wolftotem4/golava-core/auth/jwt"
+	"github.com/wolftotem4/golava-core/session"
+)
+
+// identityProvider is a stand-in OpenID Connect provider.
+type identityProvider struct {
+	*httptest.Server
+
+	key *rsa.PrivateKey
+
+	// authorization requests by code
+	requests map[string]url.Values
+
+	jwksRequests atomic.Int32
+
+	// issue ID tokens without a nonce
+	omitNonce bool
+}
+
+func newIdentityProvider(t *testing.T) *identityProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idp := &identityProvider{key: key, requests: make(map[string]url.Values)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Metadata{
+			Issuer:                idp.URL,
+			AuthorizationEndpoint: idp.URL + "/authorize",
+			TokenEndpoint:         idp.URL + "/token",
+			UserinfoEndpoint:      idp.URL + "/userinfo",
+			JWKSURI:               idp.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		idp.jwksRequests.Add(1)
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "key-1",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, secret, _ := r.BasicAuth()
+		request, ok := idp.requests[r.PostFormValue("code")]
+		if !ok || clientID != "client" || secret != "secret" {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+
+		// PKCE
+		sum := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+		if base64.RawURLEncoding.EncodeToString(sum[:]) != request.Get("code_challenge") {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+
+		extra := map[string]any{"nonce": request.Get("nonce"), "email": "alice@example.com", "email_verified": true}
+		if idp.omitNonce {
+			delete(extra, "nonce")
+		}
+
+		idToken, _ := jwt.Sign(&jwt.Claims{
+			Issuer:    idp.URL,
+			Subject:   "external-42",
+			Audience:  jwt.Audience{"client"},
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+			IssuedAt:  time.Now().Unix(),
+			Extra:     extra,
+		}, jwt.RSAKey("key-1", key))
+
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "access-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     idToken,
+		})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"sub": "external-42", "name": "Alice"})
+	})
+
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Close)
+	return idp
+}
+
+// authorize simulates the user approving the login and returns the callback query.
+func (idp *identityProvider) authorize(t *testing.T, authURL string) url.Values {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := u.Query()
+	if query.Get("code_challenge_method") != "S256" || query.Get("client_id") != "client" {
+		t.Fatalf("unexpected authorization request %s", authURL)
+	}
+
+	idp.requests["code-1"] = query
+	return url.Values{"code": {"code-1"}, "state": {query.Get("state")}}
+}
+
+func TestCallback(t *testing.T) {
+	ctx := context.Background()
+	idp := newIdentityProvider(t)
+	store := session.NewStore("session", nil)
+
+	s := New(nil)
+	s.Register("idp", NewOIDCProvider(idp.URL, "client", "secret", "https://app.example/callback"))
+
+	authURL, err := s.Redirect(ctx, store, "idp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := s.Callback(ctx, store, "idp", idp.authorize(t, authURL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user.ID != "external-42" || user.Email != "alice@example.com" || !user.EmailVerified || user.Name != "Alice" {
+		t.Fatalf("unexpected user %+v", user)
+	}
+}
+
+func TestCallbackRejectsInvalidState(t *testing.T) {
+	ctx := context.Background()
+	idp := newIdentityProvider(t)
+	store := session.NewStore("session", nil)
+
+	s := New(nil)
+	s.Register("idp", NewOIDCProvider(idp.URL, "client", "secret", "https://app.example/callback"))
+
+	authURL, err := s.Redirect(ctx, store, "idp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := idp.authorize(t, authURL)
+	query.Set("state", "forged")
+
+	_, err = s.Callback(ctx, store, "idp", query)
+	if !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("expected ErrInvalidState, got %v", err)
+	}
+
+	// the state is consumed by the failed attempt
+	query = idp.authorize(t, authURL)
+	_, err = s.Callback(ctx, store, "idp", query)
+	if !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("expected ErrInvalidState, got %v", err)
+	}
+}
+
+func TestCallbackAccessDenied(t *testing.T) {
+	ctx := context.Background()
+	idp := newIdentityProvider(t)
+	store := session.NewStore("session", nil)
+
+	s := New(nil)
+	s.Register("idp", NewOIDCProvider(idp.URL, "client", "secret", "https://app.example/callback"))
+
+	authURL, err := s.Redirect(ctx, store, "idp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, _ := url.Parse(authURL)
+	query := url.Values{"error": {"access_denied"}, "state": {u.Query().Get("state")}}
+
+	_, err = s.Callback(ctx, store, "idp", query)
+	if !errors.Is(err, ErrAccessDenied) {
+		t.Fatalf("expected ErrAccessDenied, got %v", err)
+	}
+}
+
+func TestCallbackRejectsMissingNonce(t *testing.T) {
+	ctx := context.Background()
+	idp := newIdentityProvider(t)
+	store := session.NewStore("session", nil)
+
+	s := New(nil)
+	s.Register("idp", NewOIDCProvider(idp.URL, "client", "secret", "https://app.example/callback"))
+
+	// the ID token carries no nonce
+	idp.omitNonce = true
+
+	authURL, err := s.Redirect(ctx, store, "idp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.Callback(ctx, store, "idp", idp.authorize(t, authURL))
+	if !errors.Is(err, ErrNonceMismatch) {
+		t.Fatalf("expected ErrNonceMismatch, got %v", err)
+	}
+
+	// no nonce was stored for the login
+	idp.omitNonce = false
+
+	authURL, err = s.Redirect(ctx, store, "idp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value, _ := store.Get(sessionKey("idp"))
+	data := value.(map[string]any)
+	delete(data, "nonce")
+	store.Put(sessionKey("idp"), data)
+
+	_, err = s.Callback(ctx, store, "idp", idp.authorize(t, authURL))
+	if !errors.Is(err, ErrNonceMismatch) {
+		t.Fatalf("expected ErrNonceMismatch, got %v", err)
+	}
+}
+
+func TestIDTokenKeysRefreshIsRateLimited(t *testing.T) {
+	ctx := context.Background()
+	idp := newIdentityProvider(t)
+	provider := NewOIDCProvider(idp.URL, "client", "secret", "https://app.example/callback")
+
+	metadata, err := provider.Discover(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	forger, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged, _ := jwt.Sign(&jwt.Claims{
+		Issuer:    idp.URL,
+		Subject:   "external-42",
+		Audience:  jwt.Audience{"client"},
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	}, jwt.RSAKey("unknown", forger))
+
+	for i := 0; i < 5; i++ {
+		if _, err := provider.verifyIDToken(ctx, metadata, forged); !errors.Is(err, ErrInvalidIDToken) {
+			t.Fatalf("expected ErrInvalidIDToken, got %v", err)
+		}
+	}
+	if n := idp.jwksRequests.Load(); n != 1 {
+		t.Errorf("expected the keys to be fetched once, got %d", n)
+	}
+
+	// the keys are fetched again once the interval has passed
+	provider.mu.Lock()
+	provider.keysFetchedAt = time.Now().Add(-time.Minute)
+	provider.mu.Unlock()
+
+	provider.verifyIDToken(ctx, metadata, forged)
+	provider.verifyIDToken(ctx, metadata, forged)
+	if n := idp.jwksRequests.Load(); n != 2 {
+		t.Errorf("expected the keys to be refreshed once, got %d", n)
+	}
+}
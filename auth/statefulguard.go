@@ -21,3 +21,15 @@ type StatefulGuard interface {
 }
 
 type ShouldLogin func(ctx context.Context, user Authenticatable) (valid bool, err error)
+
+// TwoFactorGuard holds users whose password is validated in a pending state
+// until the second factor is verified.
+type TwoFactorGuard interface {
+	StatefulGuard
+
+	HasPendingTwoFactor() bool
+	PendingTwoFactorUser(ctx context.Context) (Authenticatable, error)
+	AttemptTwoFactor(ctx context.Context, verify func(ctx context.Context, user Authenticatable) (bool, error)) (bool, error)
+	CompleteTwoFactor(ctx context.Context) error
+	CancelTwoFactor()
+}
@@ -0,0 +1,152 @@
+package throttle
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/wolftotem4/golava-core/auth"
+	"github.com/wolftotem4/golava-core/auth/generic"
+)
+
+type attempt struct {
+	account string
+	key     string
+}
+
+// ThrottledGuard limits the login attempts of a SessionGuard per username and
+// IP address, and optionally locks accounts out after repeated failures.
+type ThrottledGuard struct {
+	*generic.SessionGuard
+
+	Limiter *RateLimiter
+
+	// The credentials field holding the username. Defaults to "username".
+	UsernameKey string
+
+	// The client IP address, e.g. gin.Context.ClientIP().
+	IPAddress string
+}
+
+// NewThrottledGuard wraps the guard, counting the failed attempts of Attempt, Once and Validate.
+func NewThrottledGuard(guard *generic.SessionGuard, limiter *RateLimiter, ipAddress string) *ThrottledGuard {
+	return &ThrottledGuard{
+		SessionGuard: guard,
+		Limiter:      limiter,
+		IPAddress:    ipAddress,
+	}
+}
+
+func (tg *ThrottledGuard) Unwrap() auth.Guard {
+	return tg.SessionGuard
+}
+
+// Attempt returns a *TooManyAttemptsError without checking the credentials
+// when the limit has been reached.
+// The attempts of users with two-factor authentication are only cleared once
+// their second factor is verified with AttemptTwoFactor.
+func (tg *ThrottledGuard) Attempt(ctx context.Context, credentials map[string]any, remember bool, shouldLogin ...auth.ShouldLogin) (bool, error) {
+	current := tg.attempt(credentials)
+
+	ok, err := tg.throttle(ctx, current, func() (bool, error) {
+		return tg.SessionGuard.Attempt(ctx, credentials, remember, shouldLogin...)
+	})
+	if err != nil || !ok {
+		return ok, err
+	}
+
+	if tg.HasPendingTwoFactor() {
+		tg.Session.Store.Put(tg.GetThrottleName(), current.account)
+		return true, nil
+	}
+
+	return true, tg.Limiter.Clear(ctx, current.account, current.key)
+}
+
+// AttemptTwoFactor is throttled along with the password step, the invalid codes
+// counting as failed attempts of the account.
+func (tg *ThrottledGuard) AttemptTwoFactor(ctx context.Context, verify func(ctx context.Context, user auth.Authenticatable) (bool, error)) (bool, error) {
+	account, _ := tg.Session.Store.Get(tg.GetThrottleName())
+	current := tg.attemptFor(account)
+
+	ok, err := tg.throttle(ctx, current, func() (bool, error) {
+		return tg.SessionGuard.AttemptTwoFactor(ctx, verify)
+	})
+	if err != nil || !ok {
+		return ok, err
+	}
+
+	tg.Session.Store.Remove(tg.GetThrottleName())
+	return true, tg.Limiter.Clear(ctx, current.account, current.key)
+}
+
+// Once is throttled as Attempt.
+func (tg *ThrottledGuard) Once(ctx context.Context, credentials map[string]any) (bool, error) {
+	current := tg.attempt(credentials)
+
+	ok, err := tg.throttle(ctx, current, func() (bool, error) {
+		return tg.SessionGuard.Once(ctx, credentials)
+	})
+	if err != nil || !ok {
+		return ok, err
+	}
+
+	return true, tg.Limiter.Clear(ctx, current.account, current.key)
+}
+
+// Validate is throttled as Attempt, so that it cannot be used to guess passwords past the limit.
+func (tg *ThrottledGuard) Validate(ctx context.Context, credentials map[string]any) (bool, error) {
+	current := tg.attempt(credentials)
+
+	ok, err := tg.throttle(ctx, current, func() (bool, error) {
+		return tg.SessionGuard.Validate(ctx, credentials)
+	})
+	if err != nil || !ok {
+		return ok, err
+	}
+
+	return true, tg.Limiter.Clear(ctx, current.account, current.key)
+}
+
+// The session key holding the account of a login awaiting its second factor.
+func (tg *ThrottledGuard) GetThrottleName() string {
+	return fmt.Sprintf("login_throttle_%s", tg.Name)
+}
+
+// throttle runs the check unless the limit has been reached, and counts its failures.
+func (tg *ThrottledGuard) throttle(ctx context.Context, current attempt, check func() (bool, error)) (bool, error) {
+	err := tg.Limiter.Check(ctx, current.account, current.key)
+	if err != nil {
+		return false, err
+	}
+
+	ok, err := check()
+	if err != nil {
+		return false, err
+	} else if !ok {
+		return false, tg.Limiter.Hit(ctx, current.account, current.key)
+	}
+
+	return true, nil
+}
+
+func (tg *ThrottledGuard) attempt(credentials map[string]any) attempt {
+	usernameKey := tg.UsernameKey
+	if usernameKey == "" {
+		usernameKey = "username"
+	}
+
+	return tg.attemptFor(credentials[usernameKey])
+}
+
+func (tg *ThrottledGuard) attemptFor(username any) attempt {
+	account := strings.ToLower(fmt.Sprintf("%v", username))
+	if username == nil {
+		account = ""
+	}
+
+	return attempt{
+		account: account,
+		key:     fmt.Sprintf("login|%s|%s", account, tg.IPAddress),
+	}
+}
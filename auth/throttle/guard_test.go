@@ -0,0 +1,183 @@
+package throttle
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/wolftotem4/golava-core/auth"
+	"github.com/wolftotem4/golava-core/auth/generic"
+	"github.com/wolftotem4/golava-core/cookie"
+	"github.com/wolftotem4/golava-core/encryption"
+	"github.com/wolftotem4/golava-core/session"
+	"github.com/wolftotem4/golava-core/session/memory"
+)
+
+type twoFactorUser struct {
+	generic.User
+}
+
+func (u *twoFactorUser) HasTwoFactorEnabled() bool {
+	return true
+}
+
+type passwordProvider struct {
+	auth.UserProvider
+	users map[string]auth.Authenticatable
+}
+
+func (p *passwordProvider) RetrieveById(ctx context.Context, identifier any) (auth.Authenticatable, error) {
+	for _, user := range p.users {
+		if user.GetAuthIdentifier() == identifier {
+			return user, nil
+		}
+	}
+	return nil, auth.ErrUserNotFound
+}
+
+func (p *passwordProvider) RetrieveByCredentials(ctx context.Context, credentials map[string]any) (auth.Authenticatable, error) {
+	user, ok := p.users[credentials["username"].(string)]
+	if !ok {
+		return nil, auth.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (p *passwordProvider) ValidateCredentials(ctx context.Context, user auth.Authenticatable, credentials map[string]any) (bool, error) {
+	return user.GetAuthPassword() == credentials["password"], nil
+}
+
+func (p *passwordProvider) RehashPasswordIfRequired(ctx context.Context, user auth.Authenticatable, credentials map[string]any, force bool) (string, error) {
+	return "rehashed", nil
+}
+
+func TestThrottledGuardValidateAndOnce(t *testing.T) {
+	ctx := context.Background()
+	provider := &passwordProvider{users: map[string]auth.Authenticatable{"alice": &generic.User{ID: 1, Username: "alice", Password: "secret"}}}
+
+	for name, check := range map[string]func(guard *ThrottledGuard, credentials map[string]any) (bool, error){
+		"Validate": func(guard *ThrottledGuard, credentials map[string]any) (bool, error) {
+			return guard.Validate(ctx, credentials)
+		},
+		"Once": func(guard *ThrottledGuard, credentials map[string]any) (bool, error) {
+			return guard.Once(ctx, credentials)
+		},
+	} {
+		limiter := &RateLimiter{Store: NewMemoryStore(), MaxAttempts: 2}
+		guard := NewThrottledGuard(&generic.SessionGuard{Name: "web", Provider: provider}, limiter, "127.0.0.1")
+
+		for i := 0; i < 2; i++ {
+			ok, err := check(guard, map[string]any{"username": "alice", "password": "wrong"})
+			if err != nil || ok {
+				t.Fatalf("%s: attempt %d: expected to fail, got %v, %v", name, i, ok, err)
+			}
+		}
+
+		_, err := check(guard, map[string]any{"username": "alice", "password": "secret"})
+		if !errors.Is(err, ErrTooManyAttempts) {
+			t.Fatalf("%s: expected ErrTooManyAttempts, got %v", name, err)
+		}
+
+		if err := limiter.Clear(ctx, "alice", "login|alice|127.0.0.1"); err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := check(guard, map[string]any{"username": "alice", "password": "secret"}); err != nil || !ok {
+			t.Fatalf("%s: expected to succeed, got %v, %v", name, ok, err)
+		}
+	}
+}
+
+func newTwoFactorGuard(t *testing.T, limiter *RateLimiter) *ThrottledGuard {
+	key, err := encryption.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := memory.NewMemorySessionHandler(time.Hour, 0)
+	t.Cleanup(func() { handler.Close() })
+
+	cookies := cookie.NewEncryptableCookieManager(&cookie.CookieManager{Path: "/"}, encryption.NewEncrypter(key))
+	cookies.SetRequest(httptest.NewRequest("POST", "/login", nil))
+	cookies.SetResponseWriter(httptest.NewRecorder())
+
+	provider := &passwordProvider{users: map[string]auth.Authenticatable{
+		"bob": &twoFactorUser{generic.User{ID: 2, Username: "bob", Password: "secret"}},
+	}}
+
+	return NewThrottledGuard(&generic.SessionGuard{
+		Name:     "web",
+		Provider: provider,
+		Cookie:   cookies,
+		Session: &session.SessionManager{
+			Name:     "session",
+			Store:    session.NewStore("id", handler),
+			Lifetime: time.Hour,
+		},
+	}, limiter, "127.0.0.1")
+}
+
+func TestThrottledGuardTwoFactor(t *testing.T) {
+	ctx := context.Background()
+	limiter := &RateLimiter{Store: NewMemoryStore(), MaxAttempts: 3}
+	guard := newTwoFactorGuard(t, limiter)
+
+	if ok, err := guard.Attempt(ctx, map[string]any{"username": "bob", "password": "wrong"}, false); err != nil || ok {
+		t.Fatalf("expected the password to be rejected, got %v, %v", ok, err)
+	}
+	if ok, err := guard.Attempt(ctx, map[string]any{"username": "bob", "password": "secret"}, false); err != nil || !ok || !guard.HasPendingTwoFactor() {
+		t.Fatalf("expected the two-factor challenge to be pending, got %v, %v", ok, err)
+	}
+
+	// the attempts are kept until the second factor is verified
+	if attempts, _ := limiter.Attempts(ctx, "login|bob|127.0.0.1"); attempts != 1 {
+		t.Fatalf("expected 1 failed attempt but got %d", attempts)
+	}
+
+	reject := func(ctx context.Context, user auth.Authenticatable) (bool, error) { return false, nil }
+	accept := func(ctx context.Context, user auth.Authenticatable) (bool, error) { return true, nil }
+
+	if ok, err := guard.AttemptTwoFactor(ctx, reject); err != nil || ok {
+		t.Fatalf("expected the code to be rejected, got %v, %v", ok, err)
+	}
+	if ok, err := guard.AttemptTwoFactor(ctx, accept); err != nil || !ok {
+		t.Fatalf("expected the code to be accepted, got %v, %v", ok, err)
+	}
+
+	if !guard.Check() || guard.UserHash() != "rehashed" {
+		t.Errorf("expected the user to be logged in with the rehashed password, got %v, %q", guard.Check(), guard.UserHash())
+	}
+	if attempts, _ := limiter.Attempts(ctx, "login|bob|127.0.0.1"); attempts != 0 {
+		t.Errorf("expected the attempts to be cleared but got %d", attempts)
+	}
+}
+
+func TestThrottledGuardTwoFactorLimit(t *testing.T) {
+	ctx := context.Background()
+	limiter := &RateLimiter{Store: NewMemoryStore(), MaxAttempts: 2}
+	guard := newTwoFactorGuard(t, limiter)
+
+	if ok, err := guard.Attempt(ctx, map[string]any{"username": "bob", "password": "secret"}, false); err != nil || !ok {
+		t.Fatalf("expected the password to be accepted, got %v, %v", ok, err)
+	}
+
+	verified := 0
+	verify := func(ctx context.Context, user auth.Authenticatable) (bool, error) {
+		verified++
+		return false, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		if ok, err := guard.AttemptTwoFactor(ctx, verify); err != nil || ok {
+			t.Fatalf("attempt %d: expected the code to be rejected, got %v, %v", i, ok, err)
+		}
+	}
+
+	if _, err := guard.AttemptTwoFactor(ctx, verify); !errors.Is(err, ErrTooManyAttempts) {
+		t.Fatalf("expected ErrTooManyAttempts, got %v", err)
+	}
+	if verified != 2 {
+		t.Errorf("expected the code not to be verified past the limit, got %d verifications", verified)
+	}
+}
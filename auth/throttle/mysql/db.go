@@ -0,0 +1,70 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wolftotem4/golava-core/auth/throttle"
+)
+
+type MySQLThrottleStore struct {
+	DB    *sql.DB
+	Table string
+}
+
+func NewMySQLThrottleStore(db *sql.DB, table string) *MySQLThrottleStore {
+	return &MySQLThrottleStore{
+		DB:    db,
+		Table: table,
+	}
+}
+
+func (d *MySQLThrottleStore) Get(ctx context.Context, key string) (throttle.Entry, error) {
+	row := d.DB.QueryRowContext(ctx, fmt.Sprintf(
+		"SELECT attempts, expires_at FROM `%s` WHERE id = ? AND expires_at > ?", d.Table,
+	), key, time.Now().Unix())
+	if err := row.Err(); err != nil {
+		return throttle.Entry{}, err
+	}
+
+	var (
+		entry     throttle.Entry
+		expiresAt int64
+	)
+	err := row.Scan(&entry.Count, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return throttle.Entry{}, nil
+	} else if err != nil {
+		return throttle.Entry{}, err
+	}
+
+	entry.ExpiresAt = time.Unix(expiresAt, 0)
+	return entry, nil
+}
+
+func (d *MySQLThrottleStore) Increment(ctx context.Context, key string, decay time.Duration) (throttle.Entry, error) {
+	now := time.Now()
+	_, err := d.DB.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			"INSERT INTO `%s` (id, attempts, expires_at) VALUES (?, 1, ?) ON DUPLICATE KEY UPDATE attempts = IF(expires_at <= ?, 1, attempts + 1), expires_at = IF(attempts = 1, VALUES(expires_at), expires_at)",
+			d.Table,
+		),
+		key, now.Add(decay).Unix(), now.Unix(),
+	)
+	if err != nil {
+		return throttle.Entry{}, err
+	}
+
+	return d.Get(ctx, key)
+}
+
+func (d *MySQLThrottleStore) Delete(ctx context.Context, key string) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		"DELETE FROM `%s` WHERE id = ?", d.Table,
+	), key)
+	return err
+}
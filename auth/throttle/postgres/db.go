@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wolftotem4/golava-core/auth/throttle"
+)
+
+type PostgresThrottleStore struct {
+	DB    *sql.DB
+	Table string
+}
+
+func NewPostgresThrottleStore(db *sql.DB, table string) *PostgresThrottleStore {
+	return &PostgresThrottleStore{
+		DB:    db,
+		Table: table,
+	}
+}
+
+func (d *PostgresThrottleStore) Get(ctx context.Context, key string) (throttle.Entry, error) {
+	row := d.DB.QueryRowContext(ctx, fmt.Sprintf(
+		`SELECT attempts, expires_at FROM "%s" WHERE id = $1 AND expires_at > $2`, d.Table,
+	), key, time.Now().Unix())
+	if err := row.Err(); err != nil {
+		return throttle.Entry{}, err
+	}
+
+	var (
+		entry     throttle.Entry
+		expiresAt int64
+	)
+	err := row.Scan(&entry.Count, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return throttle.Entry{}, nil
+	} else if err != nil {
+		return throttle.Entry{}, err
+	}
+
+	entry.ExpiresAt = time.Unix(expiresAt, 0)
+	return entry, nil
+}
+
+func (d *PostgresThrottleStore) Increment(ctx context.Context, key string, decay time.Duration) (throttle.Entry, error) {
+	now := time.Now()
+	row := d.DB.QueryRowContext(
+		ctx,
+		fmt.Sprintf(
+			`INSERT INTO "%s" AS t (id, attempts, expires_at) VALUES ($1, 1, $2) ON CONFLICT (id) DO UPDATE SET attempts = CASE WHEN t.expires_at <= $3 THEN 1 ELSE t.attempts + 1 END, expires_at = CASE WHEN t.expires_at <= $3 THEN EXCLUDED.expires_at ELSE t.expires_at END RETURNING attempts, expires_at`,
+			d.Table,
+		),
+		key, now.Add(decay).Unix(), now.Unix(),
+	)
+	if err := row.Err(); err != nil {
+		return throttle.Entry{}, err
+	}
+
+	var (
+		entry     throttle.Entry
+		expiresAt int64
+	)
+	err := row.Scan(&entry.Count, &expiresAt)
+	if err != nil {
+		return throttle.Entry{}, err
+	}
+
+	entry.ExpiresAt = time.Unix(expiresAt, 0)
+	return entry, nil
+}
+
+func (d *PostgresThrottleStore) Delete(ctx context.Context, key string) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		`DELETE FROM "%s" WHERE id = $1`, d.Table,
+	), key)
+	return err
+}
@@ -0,0 +1,197 @@
+package throttle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrTooManyAttempts = errors.New("too many login attempts")
+
+// TooManyAttemptsError matches ErrTooManyAttempts with errors.Is.
+type TooManyAttemptsError struct {
+	RetryAfter time.Duration
+
+	// Whether the account is locked out, rather than the attempt window being exhausted.
+	Locked bool
+}
+
+func (e *TooManyAttemptsError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrTooManyAttempts, e.RetryAfter.Round(time.Second))
+}
+
+func (e *TooManyAttemptsError) Is(target error) bool {
+	return target == ErrTooManyAttempts
+}
+
+// Lockout locks an account out after repeated failures, whatever their origin.
+// Each subsequent lockout within Window lasts twice as long as the previous one.
+type Lockout struct {
+	// Number of failures before the account is locked.
+	Threshold int
+
+	// Period over which failures are counted. Defaults to one hour.
+	Window time.Duration
+
+	// Length of the first lockout. Defaults to one minute.
+	Duration time.Duration
+
+	// Upper bound of the lockout length. Unbounded when zero.
+	MaxDuration time.Duration
+}
+
+func (l *Lockout) duration(failures int) time.Duration {
+	duration := l.firstDuration()
+	for n := failures/l.Threshold - 1; n > 0; n-- {
+		duration *= 2
+		if l.MaxDuration > 0 && duration >= l.MaxDuration {
+			return l.MaxDuration
+		}
+	}
+	return duration
+}
+
+func (l *Lockout) window() time.Duration {
+	if l.Window > 0 {
+		return l.Window
+	}
+	return time.Hour
+}
+
+func (l *Lockout) firstDuration() time.Duration {
+	if l.Duration > 0 {
+		return l.Duration
+	}
+	return time.Minute
+}
+
+type RateLimiter struct {
+	Store Store
+
+	// Attempts allowed per key within Decay. Defaults to 5.
+	MaxAttempts int
+
+	// Defaults to one minute.
+	Decay time.Duration
+
+	// Optional progressive account lockout.
+	Lockout *Lockout
+}
+
+// Check returns a *TooManyAttemptsError when the account is locked out, or when
+// the key has used all its attempts. Either may be empty.
+func (l *RateLimiter) Check(ctx context.Context, account string, key string) error {
+	now := time.Now()
+
+	if account != "" && l.Lockout != nil {
+		lock, err := l.Store.Get(ctx, lockoutKey(account))
+		if err != nil {
+			return err
+		}
+
+		if lock.Count > 0 {
+			return &TooManyAttemptsError{RetryAfter: lock.ExpiresAt.Sub(now), Locked: true}
+		}
+	}
+
+	if key != "" {
+		attempts, err := l.Store.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		if attempts.Count >= l.maxAttempts() {
+			return &TooManyAttemptsError{RetryAfter: attempts.ExpiresAt.Sub(now)}
+		}
+	}
+
+	return nil
+}
+
+// Hit records a failed attempt.
+func (l *RateLimiter) Hit(ctx context.Context, account string, key string) error {
+	if key != "" {
+		_, err := l.Store.Increment(ctx, key, l.decay())
+		if err != nil {
+			return err
+		}
+	}
+
+	if account == "" || l.Lockout == nil || l.Lockout.Threshold <= 0 {
+		return nil
+	}
+
+	failures, err := l.Store.Increment(ctx, failuresKey(account), l.Lockout.window())
+	if err != nil {
+		return err
+	}
+
+	if failures.Count%l.Lockout.Threshold == 0 {
+		_, err = l.Store.Increment(ctx, lockoutKey(account), l.Lockout.duration(failures.Count))
+	}
+
+	return err
+}
+
+// Attempts returns the number of failed attempts of the key in the current window.
+func (l *RateLimiter) Attempts(ctx context.Context, key string) (int, error) {
+	entry, err := l.Store.Get(ctx, key)
+	return entry.Count, err
+}
+
+// Clear resets the attempts of the key and the failures of the account, e.g. after a successful login.
+func (l *RateLimiter) Clear(ctx context.Context, account string, key string) error {
+	if key != "" {
+		if err := l.Store.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	if account != "" {
+		return l.Store.Delete(ctx, failuresKey(account))
+	}
+
+	return nil
+}
+
+// Unlock lifts the lockout of the account and resets its failures.
+func (l *RateLimiter) Unlock(ctx context.Context, account string) error {
+	err := l.Store.Delete(ctx, lockoutKey(account))
+	if err != nil {
+		return err
+	}
+
+	return l.Store.Delete(ctx, failuresKey(account))
+}
+
+// LockedUntil returns the end of the account's lockout, or the zero time when it is not locked.
+func (l *RateLimiter) LockedUntil(ctx context.Context, account string) (time.Time, error) {
+	lock, err := l.Store.Get(ctx, lockoutKey(account))
+	if err != nil || lock.Count == 0 {
+		return time.Time{}, err
+	}
+	return lock.ExpiresAt, nil
+}
+
+func (l *RateLimiter) maxAttempts() int {
+	if l.MaxAttempts > 0 {
+		return l.MaxAttempts
+	}
+	return 5
+}
+
+func (l *RateLimiter) decay() time.Duration {
+	if l.Decay > 0 {
+		return l.Decay
+	}
+	return time.Minute
+}
+
+func lockoutKey(account string) string {
+	return "lockout|" + account
+}
+
+func failuresKey(account string) string {
+	return "failures|" + account
+}
@@ -0,0 +1,121 @@
+package throttle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	ctx := context.Background()
+	limiter := &RateLimiter{Store: NewMemoryStore(), MaxAttempts: 3}
+
+	for i := 0; i < 3; i++ {
+		if err := limiter.Check(ctx, "alice", "login|alice|127.0.0.1"); err != nil {
+			t.Fatalf("attempt %d: %v", i, err)
+		}
+		if err := limiter.Hit(ctx, "alice", "login|alice|127.0.0.1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := limiter.Check(ctx, "alice", "login|alice|127.0.0.1")
+	if !errors.Is(err, ErrTooManyAttempts) {
+		t.Fatalf("expected ErrTooManyAttempts, got %v", err)
+	}
+
+	var tooMany *TooManyAttemptsError
+	if !errors.As(err, &tooMany) || tooMany.RetryAfter <= 0 || tooMany.Locked {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if err := limiter.Check(ctx, "alice", "login|alice|10.0.0.1"); err != nil {
+		t.Fatalf("other IP address should not be throttled: %v", err)
+	}
+
+	if err := limiter.Clear(ctx, "alice", "login|alice|127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := limiter.Check(ctx, "alice", "login|alice|127.0.0.1"); err != nil {
+		t.Fatalf("expected attempts to be cleared: %v", err)
+	}
+}
+
+func TestRateLimiterLockout(t *testing.T) {
+	ctx := context.Background()
+	limiter := &RateLimiter{
+		Store:       NewMemoryStore(),
+		MaxAttempts: 100,
+		Lockout: &Lockout{
+			Threshold:   2,
+			Window:      time.Hour,
+			Duration:    time.Minute,
+			MaxDuration: 3 * time.Minute,
+		},
+	}
+
+	for _, ip := range []string{"127.0.0.1", "10.0.0.1"} {
+		if err := limiter.Hit(ctx, "bob", "login|bob|"+ip); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var tooMany *TooManyAttemptsError
+	err := limiter.Check(ctx, "bob", "login|bob|192.168.0.1")
+	if !errors.As(err, &tooMany) || !tooMany.Locked {
+		t.Fatalf("expected account to be locked, got %v", err)
+	}
+
+	until, err := limiter.LockedUntil(ctx, "bob")
+	if err != nil {
+		t.Fatal(err)
+	} else if d := time.Until(until); d <= 0 || d > time.Minute {
+		t.Fatalf("unexpected lockout end %v", until)
+	}
+
+	if err := limiter.Unlock(ctx, "bob"); err != nil {
+		t.Fatal(err)
+	}
+	if err := limiter.Check(ctx, "bob", "login|bob|192.168.0.1"); err != nil {
+		t.Fatalf("expected account to be unlocked: %v", err)
+	}
+}
+
+func TestLockoutDuration(t *testing.T) {
+	lockout := &Lockout{Threshold: 5, Duration: time.Minute, MaxDuration: 3 * time.Minute}
+
+	tests := []struct {
+		failures int
+		expected time.Duration
+	}{
+		{5, time.Minute},
+		{10, 2 * time.Minute},
+		{15, 3 * time.Minute},
+		{50, 3 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if actual := lockout.duration(tt.failures); actual != tt.expected {
+			t.Errorf("duration(%d) = %s, expected %s", tt.failures, actual, tt.expected)
+		}
+	}
+}
+
+func TestLockoutDefaults(t *testing.T) {
+	ctx := context.Background()
+	limiter := &RateLimiter{Store: NewMemoryStore(), MaxAttempts: 100, Lockout: &Lockout{Threshold: 2}}
+
+	for i := 0; i < 2; i++ {
+		if err := limiter.Hit(ctx, "carol", "login|carol|127.0.0.1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	until, err := limiter.LockedUntil(ctx, "carol")
+	if err != nil {
+		t.Fatal(err)
+	} else if d := time.Until(until); d <= 0 || d > time.Minute {
+		t.Fatalf("expected the account to be locked for a minute, got %v", until)
+	}
+}
@@ -0,0 +1,70 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wolftotem4/golava-core/auth/throttle"
+)
+
+type SqliteThrottleStore struct {
+	DB    *sql.DB
+	Table string
+}
+
+func NewSqliteThrottleStore(db *sql.DB, table string) *SqliteThrottleStore {
+	return &SqliteThrottleStore{
+		DB:    db,
+		Table: table,
+	}
+}
+
+func (d *SqliteThrottleStore) Get(ctx context.Context, key string) (throttle.Entry, error) {
+	row := d.DB.QueryRowContext(ctx, fmt.Sprintf(
+		`SELECT attempts, expires_at FROM "%s" WHERE id = $1 AND expires_at > $2`, d.Table,
+	), key, time.Now().Unix())
+	if err := row.Err(); err != nil {
+		return throttle.Entry{}, err
+	}
+
+	var (
+		entry     throttle.Entry
+		expiresAt int64
+	)
+	err := row.Scan(&entry.Count, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return throttle.Entry{}, nil
+	} else if err != nil {
+		return throttle.Entry{}, err
+	}
+
+	entry.ExpiresAt = time.Unix(expiresAt, 0)
+	return entry, nil
+}
+
+func (d *SqliteThrottleStore) Increment(ctx context.Context, key string, decay time.Duration) (throttle.Entry, error) {
+	now := time.Now()
+	_, err := d.DB.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			`INSERT INTO "%[1]s" (id, attempts, expires_at) VALUES ($1, 1, $2) ON CONFLICT (id) DO UPDATE SET attempts = CASE WHEN "%[1]s".expires_at <= $3 THEN 1 ELSE "%[1]s".attempts + 1 END, expires_at = CASE WHEN "%[1]s".expires_at <= $3 THEN excluded.expires_at ELSE "%[1]s".expires_at END`,
+			d.Table,
+		),
+		key, now.Add(decay).Unix(), now.Unix(),
+	)
+	if err != nil {
+		return throttle.Entry{}, err
+	}
+
+	return d.Get(ctx, key)
+}
+
+func (d *SqliteThrottleStore) Delete(ctx context.Context, key string) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		`DELETE FROM "%s" WHERE id = $1`, d.Table,
+	), key)
+	return err
+}
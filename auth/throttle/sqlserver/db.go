@@ -0,0 +1,77 @@
+package sqlserver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wolftotem4/golava-core/auth/throttle"
+)
+
+type SQLServerThrottleStore struct {
+	DB    *sql.DB
+	Table string
+}
+
+func NewSQLServerThrottleStore(db *sql.DB, table string) *SQLServerThrottleStore {
+	return &SQLServerThrottleStore{
+		DB:    db,
+		Table: table,
+	}
+}
+
+func (d *SQLServerThrottleStore) Get(ctx context.Context, key string) (throttle.Entry, error) {
+	row := d.DB.QueryRowContext(ctx, fmt.Sprintf(
+		"SELECT attempts, expires_at FROM [%s] WHERE id = @p1 AND expires_at > @p2", d.Table,
+	), key, time.Now().Unix())
+	if err := row.Err(); err != nil {
+		return throttle.Entry{}, err
+	}
+
+	var (
+		entry     throttle.Entry
+		expiresAt int64
+	)
+	err := row.Scan(&entry.Count, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return throttle.Entry{}, nil
+	} else if err != nil {
+		return throttle.Entry{}, err
+	}
+
+	entry.ExpiresAt = time.Unix(expiresAt, 0)
+	return entry, nil
+}
+
+func (d *SQLServerThrottleStore) Increment(ctx context.Context, key string, decay time.Duration) (throttle.Entry, error) {
+	now := time.Now()
+	_, err := d.DB.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			`
+BEGIN tran
+	UPDATE [%s] WITH (serializable) SET attempts = CASE WHEN expires_at <= @p3 THEN 1 ELSE attempts + 1 END, expires_at = CASE WHEN expires_at <= @p3 THEN @p2 ELSE expires_at END WHERE id = @p1;
+	IF @@rowcount = 0
+	BEGIN
+		INSERT INTO [%[1]s] (id, attempts, expires_at) VALUES (@p1, 1, @p2);
+	END
+COMMIT tran`,
+			d.Table,
+		),
+		key, now.Add(decay).Unix(), now.Unix(),
+	)
+	if err != nil {
+		return throttle.Entry{}, err
+	}
+
+	return d.Get(ctx, key)
+}
+
+func (d *SQLServerThrottleStore) Delete(ctx context.Context, key string) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		"DELETE FROM [%s] WHERE id = @p1", d.Table,
+	), key)
+	return err
+}
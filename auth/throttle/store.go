@@ -0,0 +1,78 @@
+package throttle
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Entry is a counter that expires at the end of its window.
+type Entry struct {
+	Count     int
+	ExpiresAt time.Time
+}
+
+type Store interface {
+	// Get returns the zero Entry when the key is missing or expired.
+	Get(ctx context.Context, key string) (Entry, error)
+
+	// Increment adds one to the counter, starting a new window of the given
+	// length when the key is missing or expired.
+	Increment(ctx context.Context, key string, decay time.Duration) (Entry, error)
+
+	Delete(ctx context.Context, key string) error
+}
+
+// MemoryStore keeps the counters of a single process.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]Entry
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]Entry)}
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok || !entry.ExpiresAt.After(time.Now()) {
+		return Entry{}, nil
+	}
+
+	return entry, nil
+}
+
+func (s *MemoryStore) Increment(ctx context.Context, key string, decay time.Duration) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	entry, ok := s.entries[key]
+	if !ok || !entry.ExpiresAt.After(now) {
+		s.prune(now)
+		entry = Entry{ExpiresAt: now.Add(decay)}
+	}
+
+	entry.Count++
+	s.entries[key] = entry
+	return entry, nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+func (s *MemoryStore) prune(now time.Time) {
+	for key, entry := range s.entries {
+		if !entry.ExpiresAt.After(now) {
+			delete(s.entries, key)
+		}
+	}
+}
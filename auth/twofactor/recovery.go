@@ -0,0 +1,53 @@
+package twofactor
+
+import (
+	"strings"
+
+	"github.com/wolftotem4/golava-core/hashing"
+	"github.com/wolftotem4/golava-core/util"
+)
+
+// GenerateRecoveryCodes returns n random codes of the form "xxxxx-xxxxx".
+// Show them to the user once and store only their hashes.
+func GenerateRecoveryCodes(n int) []string {
+	codes := make([]string, n)
+	for i := range codes {
+		code := strings.ToLower(util.RandomString(10))
+		codes[i] = code[:5] + "-" + code[5:]
+	}
+	return codes
+}
+
+func HashRecoveryCodes(hasher *hashing.HasherManager, codes []string) ([]string, error) {
+	hashed := make([]string, len(codes))
+	for i, code := range codes {
+		var err error
+		hashed[i], err = hasher.Make(code)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return hashed, nil
+}
+
+// UseRecoveryCode checks the code against the hashed codes and, on a match,
+// returns the hashed codes without the used one, to be stored in its place.
+func UseRecoveryCode(hasher *hashing.HasherManager, hashed []string, code string) (remaining []string, ok bool, err error) {
+	code = strings.ToLower(strings.TrimSpace(code))
+
+	for i, hashedCode := range hashed {
+		match, err := hasher.Check(code, hashedCode)
+		if err != nil {
+			return hashed, false, err
+		}
+
+		if match {
+			remaining = make([]string, 0, len(hashed)-1)
+			remaining = append(remaining, hashed[:i]...)
+			remaining = append(remaining, hashed[i+1:]...)
+			return remaining, true, nil
+		}
+	}
+
+	return hashed, false, nil
+}
@@ -0,0 +1,164 @@
+package twofactor
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var ErrInvalidSecret = errors.New("invalid two-factor secret")
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a random 160-bit secret encoded in base32, as expected by authenticator apps.
+func GenerateSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(b), nil
+}
+
+// TOTP generates and verifies time-based one-time passwords (RFC 6238) with HMAC-SHA1.
+type TOTP struct {
+	// Shown by authenticator apps, e.g. the application name.
+	Issuer string
+
+	// Defaults to 6.
+	Digits int
+
+	// Defaults to 30 seconds.
+	Period time.Duration
+
+	// Number of periods before and after the current one that are also accepted,
+	// to allow for clock skew. Defaults to 1.
+	Skew int
+
+	Now func() time.Time
+}
+
+// URI returns the otpauth URI to be encoded in a QR code.
+func (t *TOTP) URI(secret string, account string) string {
+	label := url.PathEscape(account)
+	if t.Issuer != "" {
+		label = url.PathEscape(t.Issuer) + ":" + label
+	}
+
+	query := url.Values{}
+	query.Set("secret", secret)
+	if t.Issuer != "" {
+		query.Set("issuer", t.Issuer)
+	}
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprintf("%d", t.digits()))
+	query.Set("period", fmt.Sprintf("%d", int(t.period().Seconds())))
+
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// Code returns the code at the given time.
+func (t *TOTP) Code(secret string, at time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return t.code(key, t.step(at)), nil
+}
+
+func (t *TOTP) Verify(secret string, code string) (bool, error) {
+	_, ok, err := t.VerifyStep(secret, code)
+	return ok, err
+}
+
+// VerifyStep also returns the time step of the matching code. Storing it and
+// rejecting steps that are not greater prevents a code from being replayed.
+func (t *TOTP) VerifyStep(secret string, code string) (int64, bool, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return 0, false, err
+	}
+
+	code = strings.ReplaceAll(code, " ", "")
+	if len(code) != t.digits() {
+		return 0, false, nil
+	}
+
+	current := t.step(t.now())
+	skew := t.skew()
+	for step := current - skew; step <= current+skew; step++ {
+		if subtle.ConstantTimeCompare([]byte(t.code(key, step)), []byte(code)) == 1 {
+			return step, true, nil
+		}
+	}
+
+	return 0, false, nil
+}
+
+func (t *TOTP) code(key []byte, step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < t.digits(); i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", t.digits(), value%mod)
+}
+
+func (t *TOTP) step(at time.Time) int64 {
+	return at.Unix() / int64(t.period().Seconds())
+}
+
+func (t *TOTP) now() time.Time {
+	if t.Now != nil {
+		return t.Now()
+	}
+	return time.Now()
+}
+
+func (t *TOTP) digits() int {
+	if t.Digits > 0 {
+		return t.Digits
+	}
+	return 6
+}
+
+func (t *TOTP) period() time.Duration {
+	if t.Period >= time.Second {
+		return t.Period
+	}
+	return 30 * time.Second
+}
+
+func (t *TOTP) skew() int64 {
+	if t.Skew > 0 {
+		return int64(t.Skew)
+	}
+	return 1
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	key, err := encoding.DecodeString(strings.TrimRight(secret, "="))
+	if err != nil || len(key) == 0 {
+		return nil, ErrInvalidSecret
+	}
+	return key, nil
+}
@@ -0,0 +1,94 @@
+package twofactor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wolftotem4/golava-core/hashing"
+)
+
+// RFC 6238 test vector secret "12345678901234567890"
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	totp := &TOTP{Digits: 8}
+
+	tests := []struct {
+		at       int64
+		expected string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{2000000000, "69279037"},
+	}
+
+	for _, tt := range tests {
+		code, err := totp.Code(rfcSecret, time.Unix(tt.at, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != tt.expected {
+			t.Errorf("Code(%d) = %s, expected %s", tt.at, code, tt.expected)
+		}
+	}
+}
+
+func TestTOTPVerify(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	totp := &TOTP{Now: func() time.Time { return now }}
+
+	previous, _ := totp.Code(rfcSecret, now.Add(-30*time.Second))
+	if ok, err := totp.Verify(rfcSecret, previous); err != nil || !ok {
+		t.Fatalf("expected code of the previous period to be accepted")
+	}
+
+	stale, _ := totp.Code(rfcSecret, now.Add(-90*time.Second))
+	if ok, _ := totp.Verify(rfcSecret, stale); ok {
+		t.Fatalf("expected stale code to be rejected")
+	}
+
+	if _, err := totp.Verify("not base32!", "123456"); err != ErrInvalidSecret {
+		t.Fatalf("expected ErrInvalidSecret, got %v", err)
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	totp := &TOTP{Issuer: "Golava"}
+	uri := totp.URI("JBSWY3DPEHPK3PXP", "alice@example.com")
+
+	if !strings.HasPrefix(uri, "otpauth://totp/Golava:alice@example.com?") {
+		t.Fatalf("unexpected URI %s", uri)
+	}
+	if !strings.Contains(uri, "secret=JBSWY3DPEHPK3PXP") || !strings.Contains(uri, "issuer=Golava") {
+		t.Fatalf("unexpected URI %s", uri)
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	hasher := &hashing.HasherManager{
+		DefaultHasher: "bcrypt",
+		Hashers:       map[string]hashing.Hasher{"bcrypt": &hashing.BcryptHasher{Cost: 4}},
+		MapHashPrefix: map[string]string{"$2a$": "bcrypt"},
+	}
+
+	codes := GenerateRecoveryCodes(3)
+	hashed, err := HashRecoveryCodes(hasher, codes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remaining, ok, err := UseRecoveryCode(hasher, hashed, strings.ToUpper(codes[1]))
+	if err != nil {
+		t.Fatal(err)
+	} else if !ok || len(remaining) != 2 {
+		t.Fatalf("expected recovery code to be used")
+	}
+
+	_, ok, err = UseRecoveryCode(hasher, remaining, codes[1])
+	if err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatalf("expected recovery code to be single-use")
+	}
+}
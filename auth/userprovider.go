@@ -10,3 +10,12 @@ type UserProvider interface {
 	ValidateCredentials(ctx context.Context, user Authenticatable, credentials map[string]any) (bool, error)
 	RehashPasswordIfRequired(ctx context.Context, user Authenticatable, credentials map[string]any, force bool) (newhash string, err error)
 }
+
+// ApiTokenUserProvider retrieves users by the API token stored alongside them.
+//
+// When the guard hashes tokens, the given token is the SHA-256 hex digest of the
+// plaintext token sent by the client.
+type ApiTokenUserProvider interface {
+	UserProvider
+	RetrieveByApiToken(ctx context.Context, token string) (Authenticatable, error)
+}
@@ -0,0 +1,121 @@
+package verification
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wolftotem4/golava-core/auth"
+	"github.com/wolftotem4/golava-core/auth/throttle"
+	"github.com/wolftotem4/golava-core/routing"
+)
+
+var (
+	ErrInvalidSignature = routing.ErrInvalidSignature
+	ErrLinkExpired      = routing.ErrSignatureExpired
+	ErrInvalidLink      = errors.New("the verification link does not belong to the user")
+)
+
+type UserProvider interface {
+	auth.UserProvider
+	MarkEmailAsVerified(ctx context.Context, user auth.Authenticatable) error
+}
+
+// Notifier sends the verification link, e.g. by email.
+type Notifier interface {
+	SendEmailVerificationNotification(ctx context.Context, user auth.MustVerifyEmail, link string) error
+}
+
+// Verifier sends and checks email verification links of the form
+// <Path>/<id>/<hash>?expires=...&signature=...
+type Verifier struct {
+	// Signs the links. See routing.Router.TemporarySignedURL.
+	Router *routing.Router
+
+	// The path of the verification route, e.g. "email/verify", registered as
+	// "email/verify/:id/:hash".
+	Path string
+
+	// Lifetime of the links. Defaults to one hour.
+	Expire time.Duration
+
+	Users    UserProvider
+	Notifier Notifier
+
+	// Throttles resending the link, keyed by user. Optional.
+	Limiter *throttle.RateLimiter
+}
+
+// Send notifies the user with a new verification link. It returns a
+// *throttle.TooManyAttemptsError when links are requested too often.
+func (v *Verifier) Send(ctx context.Context, user auth.MustVerifyEmail) error {
+	if v.Limiter != nil {
+		key := fmt.Sprintf("verify|%v", user.GetAuthIdentifier())
+
+		err := v.Limiter.Check(ctx, "", key)
+		if err != nil {
+			return err
+		}
+
+		err = v.Limiter.Hit(ctx, "", key)
+		if err != nil {
+			return err
+		}
+	}
+
+	link, err := v.URL(user)
+	if err != nil {
+		return err
+	}
+
+	return v.Notifier.SendEmailVerificationNotification(ctx, user, link)
+}
+
+// URL returns a signed verification link that expires after Expire.
+func (v *Verifier) URL(user auth.MustVerifyEmail) (string, error) {
+	path := fmt.Sprintf("%s/%v/%s", strings.Trim(v.Path, "/"), user.GetAuthIdentifier(), emailHash(user))
+
+	u, err := v.Router.TemporarySignedURL(path, v.expire(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	return u.String(), nil
+}
+
+// Verify checks the link of the request against the user, and marks their email as verified.
+func (v *Verifier) Verify(c *gin.Context, user auth.MustVerifyEmail) error {
+	err := v.Router.ValidateSignature(c.Request.URL)
+	if err != nil {
+		return err
+	}
+
+	if c.Param("id") != fmt.Sprintf("%v", user.GetAuthIdentifier()) ||
+		!hmac.Equal([]byte(c.Param("hash")), []byte(emailHash(user))) {
+		return ErrInvalidLink
+	}
+
+	if user.HasVerifiedEmail() {
+		return nil
+	}
+
+	return v.Users.MarkEmailAsVerified(c, user)
+}
+
+func (v *Verifier) expire() time.Duration {
+	if v.Expire > 0 {
+		return v.Expire
+	}
+	return time.Hour
+}
+
+func emailHash(user auth.MustVerifyEmail) string {
+	sum := sha1.Sum([]byte(user.GetEmailForVerification()))
+	return hex.EncodeToString(sum[:])
+}
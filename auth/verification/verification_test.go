@@ -0,0 +1,135 @@
+package verification
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wolftotem4/golava-core/auth"
+	"github.com/wolftotem4/golava-core/routing"
+)
+
+type user struct {
+	id       int
+	email    string
+	verified bool
+}
+
+func (u *user) GetAuthIdentifierName() string   { return "id" }
+func (u *user) GetAuthIdentifier() any          { return u.id }
+func (u *user) GetAuthPasswordName() string     { return "password" }
+func (u *user) GetAuthPassword() string         { return "" }
+func (u *user) GetRememberToken() string        { return "" }
+func (u *user) SetRememberToken(string)         {}
+func (u *user) GetRememberTokenName() string    { return "remember_token" }
+func (u *user) HasVerifiedEmail() bool          { return u.verified }
+func (u *user) GetEmailForVerification() string { return u.email }
+
+type userProvider struct {
+	auth.UserProvider
+}
+
+func (p *userProvider) MarkEmailAsVerified(ctx context.Context, u auth.Authenticatable) error {
+	u.(*user).verified = true
+	return nil
+}
+
+// visit serves the link through a router mounted under the base URL's path.
+func visit(t *testing.T, v *Verifier, u *user, link string) error {
+	gin.SetMode(gin.TestMode)
+
+	var err error
+	r := gin.New()
+	r.GET("/app/email/verify/:id/:hash", func(c *gin.Context) {
+		err = v.Verify(c, u)
+	})
+
+	parsed, parseErr := url.Parse(link)
+	if parseErr != nil {
+		t.Fatal(parseErr)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, parsed.RequestURI(), nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d for %s", w.Code, link)
+	}
+	return err
+}
+
+func newVerifier(t *testing.T) *Verifier {
+	router, err := routing.NewRouter("https://example.com/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router.Key = []byte("app key")
+
+	return &Verifier{
+		Router: router,
+		Path:   "email/verify",
+		Users:  &userProvider{},
+	}
+}
+
+func TestVerify(t *testing.T) {
+	v := newVerifier(t)
+	u := &user{id: 7, email: "alice@example.com"}
+
+	link, err := v.URL(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := visit(t, v, u, link); err != nil {
+		t.Fatal(err)
+	}
+	if !u.verified {
+		t.Fatalf("expected the email to be verified")
+	}
+}
+
+func TestVerifyRejectsTamperedLinks(t *testing.T) {
+	v := newVerifier(t)
+	u := &user{id: 7, email: "alice@example.com"}
+
+	link, err := v.URL(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, _ := url.Parse(link)
+	query := parsed.Query()
+	query.Set("expires", "9999999999")
+	parsed.RawQuery = query.Encode()
+
+	if err := visit(t, v, u, parsed.String()); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature, got %v", err)
+	}
+
+	other := &user{id: 8, email: "bob@example.com"}
+	link, _ = v.URL(u)
+	if err := visit(t, v, other, link); !errors.Is(err, ErrInvalidLink) {
+		t.Fatalf("expected ErrInvalidLink, got %v", err)
+	}
+}
+
+func TestVerifyRejectsExpiredLinks(t *testing.T) {
+	v := newVerifier(t)
+	v.Expire = time.Nanosecond
+	u := &user{id: 7, email: "alice@example.com"}
+
+	link, err := v.URL(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := visit(t, v, u, link); !errors.Is(err, ErrLinkExpired) {
+		t.Fatalf("expected ErrLinkExpired, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/ugorji/go/codec"
+)
+
+// COSE algorithm identifiers.
+const (
+	AlgES256 = -7
+	AlgEdDSA = -8
+	AlgRS256 = -257
+)
+
+// COSE key parameters.
+const (
+	coseKty = 1
+	coseAlg = 3
+	coseCrv = -1
+	coseX   = -2
+	coseY   = -3
+	coseN   = -1
+	coseE   = -2
+)
+
+const (
+	ktyOKP = 1
+	ktyEC2 = 2
+	ktyRSA = 3
+
+	crvP256    = 1
+	crvEd25519 = 6
+)
+
+// VerifySignature verifies the signature of data with a COSE-encoded public key.
+func VerifySignature(publicKey []byte, data []byte, signature []byte) error {
+	var key map[int]any
+	if err := codec.NewDecoderBytes(publicKey, cborHandle()).Decode(&key); err != nil {
+		return ErrMalformedData
+	}
+
+	switch coseInt(key[coseAlg]) {
+	case AlgES256:
+		x, _ := key[coseX].([]byte)
+		y, _ := key[coseY].([]byte)
+		if coseInt(key[coseKty]) != ktyEC2 || coseInt(key[coseCrv]) != crvP256 || len(x) != 32 || len(y) != 32 {
+			return ErrMalformedData
+		}
+
+		pub := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		hash := sha256.Sum256(data)
+		if !ecdsa.VerifyASN1(pub, hash[:], signature) {
+			return ErrInvalidSignature
+		}
+		return nil
+
+	case AlgEdDSA:
+		x, _ := key[coseX].([]byte)
+		if coseInt(key[coseKty]) != ktyOKP || coseInt(key[coseCrv]) != crvEd25519 || len(x) != ed25519.PublicKeySize {
+			return ErrMalformedData
+		}
+
+		if !ed25519.Verify(ed25519.PublicKey(x), data, signature) {
+			return ErrInvalidSignature
+		}
+		return nil
+
+	case AlgRS256:
+		n, _ := key[coseN].([]byte)
+		e, _ := key[coseE].([]byte)
+		if coseInt(key[coseKty]) != ktyRSA || len(n) == 0 || len(e) == 0 || len(e) > 4 {
+			return ErrMalformedData
+		}
+
+		pub := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		hash := sha256.Sum256(data)
+		if rsa.VerifyPKCS1v15(pub, crypto.SHA256, hash[:], signature) != nil {
+			return ErrInvalidSignature
+		}
+		return nil
+
+	default:
+		return ErrUnsupportedAlgorithm
+	}
+}
+
+func coseInt(value any) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case uint64:
+		return int64(v)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,39 @@
+package webauthn
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrCredentialNotFound = errors.New("webauthn credential not found")
+
+// Credential is a public key credential registered by an authenticator, e.g. a passkey.
+type Credential struct {
+	ID     []byte
+	UserID string
+	Name   string
+
+	// COSE-encoded public key.
+	PublicKey []byte
+
+	SignCount  uint32
+	Transports []string
+	AAGUID     []byte
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
+
+type CredentialRepository interface {
+	Create(ctx context.Context, credential *Credential) error
+
+	// Find returns ErrCredentialNotFound when the credential does not exist.
+	Find(ctx context.Context, id []byte) (*Credential, error)
+
+	ListByUser(ctx context.Context, userID string) ([]*Credential, error)
+
+	// Touch records a successful assertion.
+	Touch(ctx context.Context, id []byte, signCount uint32, lastUsedAt time.Time) error
+
+	Delete(ctx context.Context, id []byte) error
+}
@@ -0,0 +1,20 @@
+package webauthn
+
+import "errors"
+
+var (
+	ErrChallengeNotFound      = errors.New("webauthn challenge not found or expired")
+	ErrChallengeMismatch      = errors.New("webauthn challenge mismatch")
+	ErrInvalidType            = errors.New("invalid webauthn ceremony type")
+	ErrInvalidOrigin          = errors.New("invalid webauthn origin")
+	ErrInvalidRPID            = errors.New("invalid webauthn relying party ID")
+	ErrUserNotPresent         = errors.New("webauthn user presence is required")
+	ErrUserNotVerified        = errors.New("webauthn user verification is required")
+	ErrInvalidSignature       = errors.New("invalid webauthn signature")
+	ErrUnsupportedAttestation = errors.New("unsupported webauthn attestation format")
+	ErrUnsupportedAlgorithm   = errors.New("unsupported webauthn public key algorithm")
+	ErrMalformedData          = errors.New("malformed webauthn data")
+	ErrCredentialNotAllowed   = errors.New("webauthn credential is not allowed for the user")
+	ErrCredentialExists       = errors.New("webauthn credential is already registered")
+	ErrSignCountRegression    = errors.New("webauthn signature counter went backwards, the authenticator may be cloned")
+)
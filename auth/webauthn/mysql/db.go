@@ -0,0 +1,72 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wolftotem4/golava-core/auth/webauthn"
+)
+
+type MySQLCredentialRepository struct {
+	DB    *sql.DB
+	Table string
+}
+
+func NewMySQLCredentialRepository(db *sql.DB, table string) *MySQLCredentialRepository {
+	return &MySQLCredentialRepository{
+		DB:    db,
+		Table: table,
+	}
+}
+
+func (d *MySQLCredentialRepository) Create(ctx context.Context, credential *webauthn.Credential) error {
+	_, err := d.DB.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			"INSERT INTO `%s` (id, user_id, name, public_key, sign_count, transports, aaguid, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			d.Table,
+		),
+		webauthn.EncodeID(credential.ID), credential.UserID, credential.Name, credential.PublicKey, credential.SignCount, webauthn.EncodeTransports(credential.Transports), credential.AAGUID, credential.CreatedAt.Unix(),
+	)
+	return err
+}
+
+func (d *MySQLCredentialRepository) Find(ctx context.Context, id []byte) (*webauthn.Credential, error) {
+	row := d.DB.QueryRowContext(ctx, fmt.Sprintf(
+		"SELECT %s FROM `%s` WHERE id = ?", webauthn.Columns, d.Table,
+	), webauthn.EncodeID(id))
+
+	credential, err := webauthn.ScanCredential(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, webauthn.ErrCredentialNotFound
+	}
+	return credential, err
+}
+
+func (d *MySQLCredentialRepository) ListByUser(ctx context.Context, userID string) ([]*webauthn.Credential, error) {
+	rows, err := d.DB.QueryContext(ctx, fmt.Sprintf(
+		"SELECT %s FROM `%s` WHERE user_id = ? ORDER BY created_at", webauthn.Columns, d.Table,
+	), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return webauthn.ScanCredentials(rows)
+}
+
+func (d *MySQLCredentialRepository) Touch(ctx context.Context, id []byte, signCount uint32, lastUsedAt time.Time) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		"UPDATE `%s` SET sign_count = ?, last_used_at = ? WHERE id = ?", d.Table,
+	), signCount, lastUsedAt.Unix(), webauthn.EncodeID(id))
+	return err
+}
+
+func (d *MySQLCredentialRepository) Delete(ctx context.Context, id []byte) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		"DELETE FROM `%s` WHERE id = ?", d.Table,
+	), webauthn.EncodeID(id))
+	return err
+}
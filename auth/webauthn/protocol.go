@@ -0,0 +1,222 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ugorji/go/codec"
+)
+
+// URLEncodedBytes is encoded in JSON as unpadded base64url, as in the WebAuthn JSON serialization.
+type URLEncodedBytes []byte
+
+func (b URLEncodedBytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(base64.RawURLEncoding.EncodeToString(b))
+}
+
+func (b *URLEncodedBytes) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(trimPadding(s))
+	if err != nil {
+		return err
+	}
+
+	*b = decoded
+	return nil
+}
+
+func trimPadding(s string) string {
+	for len(s) > 0 && s[len(s)-1] == '=' {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+type RelyingParty struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type UserEntity struct {
+	ID          URLEncodedBytes `json:"id"`
+	Name        string          `json:"name"`
+	DisplayName string          `json:"displayName"`
+}
+
+type CredentialParameter struct {
+	Type string `json:"type"`
+	Alg  int    `json:"alg"`
+}
+
+type CredentialDescriptor struct {
+	Type       string          `json:"type"`
+	ID         URLEncodedBytes `json:"id"`
+	Transports []string        `json:"transports,omitempty"`
+}
+
+type AuthenticatorSelection struct {
+	ResidentKey      string `json:"residentKey,omitempty"`
+	UserVerification string `json:"userVerification,omitempty"`
+}
+
+// CreationOptions are passed to navigator.credentials.create().
+type CreationOptions struct {
+	RP                     RelyingParty           `json:"rp"`
+	User                   UserEntity             `json:"user"`
+	Challenge              URLEncodedBytes        `json:"challenge"`
+	PubKeyCredParams       []CredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                  `json:"timeout,omitempty"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials,omitempty"`
+	AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                 `json:"attestation"`
+}
+
+// RequestOptions are passed to navigator.credentials.get().
+type RequestOptions struct {
+	Challenge        URLEncodedBytes        `json:"challenge"`
+	Timeout          int64                  `json:"timeout,omitempty"`
+	RPID             string                 `json:"rpId"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials,omitempty"`
+	UserVerification string                 `json:"userVerification,omitempty"`
+}
+
+type AuthenticatorAttestationResponse struct {
+	ClientDataJSON    URLEncodedBytes `json:"clientDataJSON"`
+	AttestationObject URLEncodedBytes `json:"attestationObject"`
+	Transports        []string        `json:"transports,omitempty"`
+}
+
+// AttestationResponse is the JSON serialization of the credential returned by navigator.credentials.create().
+type AttestationResponse struct {
+	ID       string                           `json:"id"`
+	RawID    URLEncodedBytes                  `json:"rawId"`
+	Type     string                           `json:"type"`
+	Response AuthenticatorAttestationResponse `json:"response"`
+}
+
+type AuthenticatorAssertionResponse struct {
+	ClientDataJSON    URLEncodedBytes `json:"clientDataJSON"`
+	AuthenticatorData URLEncodedBytes `json:"authenticatorData"`
+	Signature         URLEncodedBytes `json:"signature"`
+	UserHandle        URLEncodedBytes `json:"userHandle,omitempty"`
+}
+
+// AssertionResponse is the JSON serialization of the credential returned by navigator.credentials.get().
+type AssertionResponse struct {
+	ID       string                         `json:"id"`
+	RawID    URLEncodedBytes                `json:"rawId"`
+	Type     string                         `json:"type"`
+	Response AuthenticatorAssertionResponse `json:"response"`
+}
+
+type CollectedClientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+const (
+	flagUserPresent            = 0x01
+	flagUserVerified           = 0x04
+	flagAttestedCredentialData = 0x40
+)
+
+type authenticatorData struct {
+	raw          []byte
+	rpIDHash     []byte
+	flags        byte
+	signCount    uint32
+	aaguid       []byte
+	credentialID []byte
+	publicKey    []byte
+}
+
+func parseAuthenticatorData(data []byte) (*authenticatorData, error) {
+	if len(data) < 37 {
+		return nil, ErrMalformedData
+	}
+
+	ad := &authenticatorData{
+		raw:       data,
+		rpIDHash:  data[:32],
+		flags:     data[32],
+		signCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+
+	if ad.flags&flagAttestedCredentialData == 0 {
+		return ad, nil
+	}
+
+	rest := data[37:]
+	if len(rest) < 18 {
+		return nil, ErrMalformedData
+	}
+	ad.aaguid = rest[:16]
+
+	length := int(binary.BigEndian.Uint16(rest[16:18]))
+	rest = rest[18:]
+	if len(rest) < length {
+		return nil, ErrMalformedData
+	}
+	ad.credentialID = rest[:length]
+	rest = rest[length:]
+
+	// the public key may be followed by extensions, re-encode it alone
+	var key map[int]any
+	if err := codec.NewDecoderBytes(rest, cborHandle()).Decode(&key); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedData, err)
+	}
+
+	var buf bytes.Buffer
+	if err := codec.NewEncoder(&buf, cborHandle()).Encode(key); err != nil {
+		return nil, err
+	}
+	ad.publicKey = buf.Bytes()
+
+	return ad, nil
+}
+
+func (ad *authenticatorData) verify(rpID string, userVerification bool) error {
+	hash := sha256.Sum256([]byte(rpID))
+	if !bytes.Equal(ad.rpIDHash, hash[:]) {
+		return ErrInvalidRPID
+	}
+
+	if ad.flags&flagUserPresent == 0 {
+		return ErrUserNotPresent
+	}
+
+	if userVerification && ad.flags&flagUserVerified == 0 {
+		return ErrUserNotVerified
+	}
+
+	return nil
+}
+
+type attestationObject struct {
+	Fmt      string         `codec:"fmt"`
+	AttStmt  map[string]any `codec:"attStmt"`
+	AuthData []byte         `codec:"authData"`
+}
+
+func parseAttestationObject(data []byte) (*attestationObject, error) {
+	var obj attestationObject
+	if err := codec.NewDecoderBytes(data, cborHandle()).Decode(&obj); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedData, err)
+	}
+	return &obj, nil
+}
+
+func cborHandle() *codec.CborHandle {
+	h := &codec.CborHandle{}
+	h.SignedInteger = true
+	return h
+}
@@ -0,0 +1,74 @@
+package webauthn
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"strings"
+	"time"
+)
+
+// Columns lists the columns read by ScanCredential, in order.
+const Columns = "id, user_id, name, public_key, sign_count, transports, aaguid, last_used_at, created_at"
+
+// Scanner is implemented by *sql.Row and *sql.Rows.
+type Scanner interface {
+	Scan(dest ...any) error
+}
+
+// EncodeID converts a credential ID to the base64url form stored in the id column.
+func EncodeID(id []byte) string {
+	return base64.RawURLEncoding.EncodeToString(id)
+}
+
+func EncodeTransports(transports []string) string {
+	return strings.Join(transports, ",")
+}
+
+// ScanCredential reads a row selected with Columns.
+func ScanCredential(row Scanner) (*Credential, error) {
+	var (
+		credential Credential
+		id         string
+		transports string
+		lastUsedAt sql.NullInt64
+		createdAt  int64
+	)
+
+	err := row.Scan(&id, &credential.UserID, &credential.Name, &credential.PublicKey, &credential.SignCount, &transports, &credential.AAGUID, &lastUsedAt, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+
+	credential.ID, err = base64.RawURLEncoding.DecodeString(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if transports != "" {
+		credential.Transports = strings.Split(transports, ",")
+	}
+
+	if lastUsedAt.Valid {
+		t := time.Unix(lastUsedAt.Int64, 0)
+		credential.LastUsedAt = &t
+	}
+	credential.CreatedAt = time.Unix(createdAt, 0)
+
+	return &credential, nil
+}
+
+// ScanCredentials reads all rows selected with Columns.
+func ScanCredentials(rows *sql.Rows) ([]*Credential, error) {
+	defer rows.Close()
+
+	var credentials []*Credential
+	for rows.Next() {
+		credential, err := ScanCredential(rows)
+		if err != nil {
+			return nil, err
+		}
+		credentials = append(credentials, credential)
+	}
+
+	return credentials, rows.Err()
+}
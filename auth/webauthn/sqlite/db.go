@@ -0,0 +1,72 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wolftotem4/golava-core/auth/webauthn"
+)
+
+type SqliteCredentialRepository struct {
+	DB    *sql.DB
+	Table string
+}
+
+func NewSqliteCredentialRepository(db *sql.DB, table string) *SqliteCredentialRepository {
+	return &SqliteCredentialRepository{
+		DB:    db,
+		Table: table,
+	}
+}
+
+func (d *SqliteCredentialRepository) Create(ctx context.Context, credential *webauthn.Credential) error {
+	_, err := d.DB.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			`INSERT INTO "%s" (id, user_id, name, public_key, sign_count, transports, aaguid, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			d.Table,
+		),
+		webauthn.EncodeID(credential.ID), credential.UserID, credential.Name, credential.PublicKey, credential.SignCount, webauthn.EncodeTransports(credential.Transports), credential.AAGUID, credential.CreatedAt.Unix(),
+	)
+	return err
+}
+
+func (d *SqliteCredentialRepository) Find(ctx context.Context, id []byte) (*webauthn.Credential, error) {
+	row := d.DB.QueryRowContext(ctx, fmt.Sprintf(
+		`SELECT %s FROM "%s" WHERE id = $1`, webauthn.Columns, d.Table,
+	), webauthn.EncodeID(id))
+
+	credential, err := webauthn.ScanCredential(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, webauthn.ErrCredentialNotFound
+	}
+	return credential, err
+}
+
+func (d *SqliteCredentialRepository) ListByUser(ctx context.Context, userID string) ([]*webauthn.Credential, error) {
+	rows, err := d.DB.QueryContext(ctx, fmt.Sprintf(
+		`SELECT %s FROM "%s" WHERE user_id = $1 ORDER BY created_at`, webauthn.Columns, d.Table,
+	), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return webauthn.ScanCredentials(rows)
+}
+
+func (d *SqliteCredentialRepository) Touch(ctx context.Context, id []byte, signCount uint32, lastUsedAt time.Time) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		`UPDATE "%s" SET sign_count = $1, last_used_at = $2 WHERE id = $3`, d.Table,
+	), signCount, lastUsedAt.Unix(), webauthn.EncodeID(id))
+	return err
+}
+
+func (d *SqliteCredentialRepository) Delete(ctx context.Context, id []byte) error {
+	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		`DELETE FROM "%s" WHERE id = $1`, d.Table,
+	), webauthn.EncodeID(id))
+	return err
+}
@@ -0,0 +1,429 @@
+package webauthn
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/wolftotem4/golava-core/auth"
+	"github.com/wolftotem4/golava-core/auth/generic"
+	"github.com/wolftotem4/golava-core/session"
+)
+
+const (
+	registrationSessionKey = "webauthn.registration"
+	loginSessionKey        = "webauthn.login"
+)
+
+// User is a user who can register credentials.
+type User interface {
+	auth.Authenticatable
+
+	// e.g. an email address, shown by the authenticator to tell accounts apart
+	GetWebAuthnName() string
+	GetWebAuthnDisplayName() string
+}
+
+// WebAuthn runs the registration and assertion ceremonies of the Web Authentication API.
+type WebAuthn struct {
+	// The relying party ID, usually the domain name, e.g. "example.com".
+	RPID   string
+	RPName string
+
+	// Allowed origins, e.g. "https://example.com".
+	Origins []string
+
+	// How long a ceremony can be completed. Defaults to 5 minutes.
+	Timeout time.Duration
+
+	// "required", "preferred" (default) or "discouraged".
+	UserVerification string
+
+	// "required", "preferred" (default) or "discouraged". Required for passkeys.
+	ResidentKey string
+
+	Repository CredentialRepository
+}
+
+type ceremony struct {
+	challenge string
+	userID    string
+	expiresAt int64
+}
+
+// BeginRegistration creates the options for navigator.credentials.create() and
+// stores the challenge in the session.
+func (w *WebAuthn) BeginRegistration(ctx context.Context, store *session.Store, user User) (*CreationOptions, error) {
+	challenge, err := w.newChallenge(store, registrationSessionKey, userID(user))
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := w.Repository.ListByUser(ctx, userID(user))
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreationOptions{
+		RP: RelyingParty{ID: w.RPID, Name: w.RPName},
+		User: UserEntity{
+			ID:          URLEncodedBytes(userID(user)),
+			Name:        user.GetWebAuthnName(),
+			DisplayName: user.GetWebAuthnDisplayName(),
+		},
+		Challenge: challenge,
+		PubKeyCredParams: []CredentialParameter{
+			{Type: "public-key", Alg: AlgES256},
+			{Type: "public-key", Alg: AlgEdDSA},
+			{Type: "public-key", Alg: AlgRS256},
+		},
+		Timeout:            w.timeout().Milliseconds(),
+		ExcludeCredentials: descriptors(credentials),
+		AuthenticatorSelection: AuthenticatorSelection{
+			ResidentKey:      valueOr(w.ResidentKey, "preferred"),
+			UserVerification: w.userVerification(),
+		},
+		Attestation: "none",
+	}, nil
+}
+
+// FinishRegistration verifies the authenticator's response and stores the new credential.
+func (w *WebAuthn) FinishRegistration(ctx context.Context, store *session.Store, user User, response *AttestationResponse, name string) (*Credential, error) {
+	pending, err := w.takeCeremony(store, registrationSessionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if pending.userID != userID(user) {
+		return nil, ErrChallengeMismatch
+	}
+
+	err = w.verifyClientData(response.Response.ClientDataJSON, "webauthn.create", pending.challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := parseAttestationObject(response.Response.AttestationObject)
+	if err != nil {
+		return nil, err
+	}
+
+	authData, err := parseAuthenticatorData(obj.AuthData)
+	if err != nil {
+		return nil, err
+	}
+
+	err = authData.verify(w.RPID, w.userVerification() == "required")
+	if err != nil {
+		return nil, err
+	}
+
+	if authData.publicKey == nil {
+		return nil, ErrMalformedData
+	}
+
+	clientDataHash := sha256.Sum256(response.Response.ClientDataJSON)
+	err = verifyAttestation(obj, authData, clientDataHash[:])
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := w.Repository.Find(ctx, authData.credentialID); err == nil {
+		return nil, ErrCredentialExists
+	} else if !errors.Is(err, ErrCredentialNotFound) {
+		return nil, err
+	}
+
+	credential := &Credential{
+		ID:         authData.credentialID,
+		UserID:     userID(user),
+		Name:       name,
+		PublicKey:  authData.publicKey,
+		SignCount:  authData.signCount,
+		Transports: response.Response.Transports,
+		AAGUID:     authData.aaguid,
+		CreatedAt:  time.Now(),
+	}
+
+	err = w.Repository.Create(ctx, credential)
+	if err != nil {
+		return nil, err
+	}
+
+	return credential, nil
+}
+
+// BeginLogin creates the options for navigator.credentials.get() and stores the
+// challenge in the session. Without a user, any discoverable credential (passkey) is accepted.
+func (w *WebAuthn) BeginLogin(ctx context.Context, store *session.Store, user auth.Authenticatable) (*RequestOptions, error) {
+	var (
+		id          string
+		credentials []*Credential
+	)
+	if user != nil {
+		id = userID(user)
+
+		var err error
+		credentials, err = w.Repository.ListByUser(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	challenge, err := w.newChallenge(store, loginSessionKey, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RequestOptions{
+		Challenge:        challenge,
+		Timeout:          w.timeout().Milliseconds(),
+		RPID:             w.RPID,
+		AllowCredentials: descriptors(credentials),
+		UserVerification: w.userVerification(),
+	}, nil
+}
+
+// FinishLogin verifies the assertion and returns the credential it was made with.
+func (w *WebAuthn) FinishLogin(ctx context.Context, store *session.Store, response *AssertionResponse) (*Credential, error) {
+	pending, err := w.takeCeremony(store, loginSessionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := w.Repository.Find(ctx, response.RawID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pending.userID != "" && pending.userID != credential.UserID {
+		return nil, ErrCredentialNotAllowed
+	}
+
+	userHandle := response.Response.UserHandle
+	if len(userHandle) > 0 && string(userHandle) != credential.UserID {
+		return nil, ErrCredentialNotAllowed
+	}
+
+	err = w.verifyClientData(response.Response.ClientDataJSON, "webauthn.get", pending.challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	authData, err := parseAuthenticatorData(response.Response.AuthenticatorData)
+	if err != nil {
+		return nil, err
+	}
+
+	err = authData.verify(w.RPID, w.userVerification() == "required")
+	if err != nil {
+		return nil, err
+	}
+
+	clientDataHash := sha256.Sum256(response.Response.ClientDataJSON)
+	signed := append(bytes.Clone(authData.raw), clientDataHash[:]...)
+	err = VerifySignature(credential.PublicKey, signed, response.Response.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	// authenticators without a counter always report zero
+	if (authData.signCount != 0 || credential.SignCount != 0) && authData.signCount <= credential.SignCount {
+		return nil, ErrSignCountRegression
+	}
+
+	now := time.Now()
+	err = w.Repository.Touch(ctx, credential.ID, authData.signCount, now)
+	if err != nil {
+		return nil, err
+	}
+
+	credential.SignCount = authData.signCount
+	credential.LastUsedAt = &now
+	return credential, nil
+}
+
+// Login verifies the assertion and logs the credential's owner in through the guard.
+func (w *WebAuthn) Login(ctx context.Context, guard *generic.SessionGuard, response *AssertionResponse, remember bool) (auth.Authenticatable, error) {
+	credential, err := w.FinishLogin(ctx, guard.Session.Store, response)
+	if err != nil {
+		return nil, err
+	}
+
+	var id any = credential.UserID
+	if guard.RecallerIdMorph != nil {
+		id, err = guard.RecallerIdMorph(credential.UserID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	user, err := guard.Provider.RetrieveById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, guard.Login(ctx, user, remember)
+}
+
+func (w *WebAuthn) newChallenge(store *session.Store, key string, userID string) ([]byte, error) {
+	challenge := make([]byte, 32)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, err
+	}
+
+	store.Put(key, map[string]any{
+		"challenge":  base64.RawURLEncoding.EncodeToString(challenge),
+		"user_id":    userID,
+		"expires_at": time.Now().Add(w.timeout()).Unix(),
+	})
+
+	return challenge, nil
+}
+
+// takeCeremony removes the challenge from the session, so that it can only be answered once.
+func (w *WebAuthn) takeCeremony(store *session.Store, key string) (ceremony, error) {
+	value, ok := store.Get(key)
+	store.Forget(key)
+	if !ok {
+		return ceremony{}, ErrChallengeNotFound
+	}
+
+	data, ok := value.(map[string]any)
+	if !ok {
+		return ceremony{}, ErrChallengeNotFound
+	}
+
+	var c ceremony
+	c.challenge, _ = data["challenge"].(string)
+	c.userID, _ = data["user_id"].(string)
+	c.expiresAt, _ = data["expires_at"].(int64)
+	if c.challenge == "" || c.expiresAt <= time.Now().Unix() {
+		return ceremony{}, ErrChallengeNotFound
+	}
+
+	return c, nil
+}
+
+func (w *WebAuthn) verifyClientData(data []byte, ceremonyType string, challenge string) error {
+	var clientData CollectedClientData
+	if err := json.Unmarshal(data, &clientData); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedData, err)
+	}
+
+	if clientData.Type != ceremonyType {
+		return ErrInvalidType
+	}
+
+	if trimPadding(clientData.Challenge) != challenge {
+		return ErrChallengeMismatch
+	}
+
+	if !slices.Contains(w.Origins, clientData.Origin) {
+		return ErrInvalidOrigin
+	}
+
+	return nil
+}
+
+func (w *WebAuthn) timeout() time.Duration {
+	if w.Timeout > 0 {
+		return w.Timeout
+	}
+	return 5 * time.Minute
+}
+
+func (w *WebAuthn) userVerification() string {
+	return valueOr(w.UserVerification, "preferred")
+}
+
+// verifyAttestation checks the attestation statement's signature. Attestation
+// certificates are not validated against trust anchors.
+func verifyAttestation(obj *attestationObject, authData *authenticatorData, clientDataHash []byte) error {
+	switch obj.Fmt {
+	case "none":
+		return nil
+
+	case "packed":
+		sig, _ := obj.AttStmt["sig"].([]byte)
+		signed := append(bytes.Clone(obj.AuthData), clientDataHash...)
+
+		x5c, _ := obj.AttStmt["x5c"].([]any)
+		if len(x5c) == 0 {
+			// self attestation, signed with the credential's own key
+			return VerifySignature(authData.publicKey, signed, sig)
+		}
+
+		der, _ := x5c[0].([]byte)
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrMalformedData, err)
+		}
+
+		return verifyCertificateSignature(cert, coseInt(obj.AttStmt["alg"]), signed, sig)
+
+	default:
+		return ErrUnsupportedAttestation
+	}
+}
+
+func verifyCertificateSignature(cert *x509.Certificate, alg int64, signed []byte, sig []byte) error {
+	var algorithm x509.SignatureAlgorithm
+	switch alg {
+	case AlgES256:
+		if _, ok := cert.PublicKey.(*ecdsa.PublicKey); ok {
+			algorithm = x509.ECDSAWithSHA256
+		}
+	case AlgEdDSA:
+		if _, ok := cert.PublicKey.(ed25519.PublicKey); ok {
+			algorithm = x509.PureEd25519
+		}
+	case AlgRS256:
+		if _, ok := cert.PublicKey.(*rsa.PublicKey); ok {
+			algorithm = x509.SHA256WithRSA
+		}
+	}
+
+	if algorithm == x509.UnknownSignatureAlgorithm {
+		return ErrUnsupportedAlgorithm
+	}
+
+	if cert.CheckSignature(algorithm, signed, sig) != nil {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func descriptors(credentials []*Credential) []CredentialDescriptor {
+	var result []CredentialDescriptor
+	for _, credential := range credentials {
+		result = append(result, CredentialDescriptor{
+			Type:       "public-key",
+			ID:         credential.ID,
+			Transports: credential.Transports,
+		})
+	}
+	return result
+}
+
+func userID(user auth.Authenticatable) string {
+	return fmt.Sprintf("%v", user.GetAuthIdentifier())
+}
+
+func valueOr(value string, defaults string) string {
+	if value != "" {
+		return value
+	}
+	return defaults
+}
@@ -0,0 +1,220 @@
+package webauthn_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wolftotem4/golava-core/auth/webauthn"
+	"github.com/wolftotem4/golava-core/auth/webauthn/webauthntest"
+	"github.com/wolftotem4/golava-core/session"
+)
+
+type user struct {
+	id int
+}
+
+func (u *user) GetAuthIdentifierName() string  { return "id" }
+func (u *user) GetAuthIdentifier() any         { return u.id }
+func (u *user) GetAuthPasswordName() string    { return "password" }
+func (u *user) GetAuthPassword() string        { return "" }
+func (u *user) GetRememberToken() string       { return "" }
+func (u *user) SetRememberToken(string)        {}
+func (u *user) GetRememberTokenName() string   { return "remember_token" }
+func (u *user) GetWebAuthnName() string        { return "alice@example.com" }
+func (u *user) GetWebAuthnDisplayName() string { return "Alice" }
+
+type repository struct {
+	credentials []*webauthn.Credential
+}
+
+func (r *repository) Create(ctx context.Context, credential *webauthn.Credential) error {
+	r.credentials = append(r.credentials, credential)
+	return nil
+}
+
+func (r *repository) Find(ctx context.Context, id []byte) (*webauthn.Credential, error) {
+	for _, credential := range r.credentials {
+		if bytes.Equal(credential.ID, id) {
+			copied := *credential
+			return &copied, nil
+		}
+	}
+	return nil, webauthn.ErrCredentialNotFound
+}
+
+func (r *repository) ListByUser(ctx context.Context, userID string) ([]*webauthn.Credential, error) {
+	var result []*webauthn.Credential
+	for _, credential := range r.credentials {
+		if credential.UserID == userID {
+			result = append(result, credential)
+		}
+	}
+	return result, nil
+}
+
+func (r *repository) Touch(ctx context.Context, id []byte, signCount uint32, lastUsedAt time.Time) error {
+	for _, credential := range r.credentials {
+		if bytes.Equal(credential.ID, id) {
+			credential.SignCount = signCount
+			credential.LastUsedAt = &lastUsedAt
+		}
+	}
+	return nil
+}
+
+func (r *repository) Delete(ctx context.Context, id []byte) error {
+	return nil
+}
+
+func newWebAuthn() *webauthn.WebAuthn {
+	return &webauthn.WebAuthn{
+		RPID:       "example.com",
+		RPName:     "Example",
+		Origins:    []string{"https://example.com"},
+		Repository: &repository{},
+	}
+}
+
+func register(t *testing.T, w *webauthn.WebAuthn, store *session.Store, authenticator *webauthntest.Authenticator, u *user) *webauthn.Credential {
+	ctx := context.Background()
+
+	options, err := w.BeginRegistration(ctx, store, u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response, err := authenticator.Register(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	credential, err := w.FinishRegistration(ctx, store, u, response, "Laptop")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return credential
+}
+
+func TestRegistrationAndLogin(t *testing.T) {
+	ctx := context.Background()
+	w := newWebAuthn()
+	store := session.NewStore("session", nil)
+	authenticator := webauthntest.NewAuthenticator("https://example.com")
+	u := &user{id: 1}
+
+	registered := register(t, w, store, authenticator, u)
+	if registered.UserID != "1" || registered.Name != "Laptop" {
+		t.Fatalf("unexpected credential %+v", registered)
+	}
+
+	options, err := w.BeginLogin(ctx, store, u)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(options.AllowCredentials) != 1 {
+		t.Fatalf("expected the registered credential to be allowed")
+	}
+
+	response, err := authenticator.Login(w.RPID, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	credential, err := w.FinishLogin(ctx, store, response)
+	if err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(credential.ID, registered.ID) || credential.SignCount != 1 {
+		t.Fatalf("unexpected credential %+v", credential)
+	}
+
+	// the challenge is single-use
+	_, err = w.FinishLogin(ctx, store, response)
+	if !errors.Is(err, webauthn.ErrChallengeNotFound) {
+		t.Fatalf("expected ErrChallengeNotFound, got %v", err)
+	}
+}
+
+func TestDiscoverableLogin(t *testing.T) {
+	ctx := context.Background()
+	w := newWebAuthn()
+	store := session.NewStore("session", nil)
+	authenticator := webauthntest.NewAuthenticator("https://example.com")
+
+	register(t, w, store, authenticator, &user{id: 2})
+
+	options, err := w.BeginLogin(ctx, store, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response, err := authenticator.Login(w.RPID, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	credential, err := w.FinishLogin(ctx, store, response)
+	if err != nil {
+		t.Fatal(err)
+	} else if credential.UserID != "2" {
+		t.Fatalf("expected credential of user 2, got %s", credential.UserID)
+	}
+}
+
+func TestLoginRejections(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		origin   string
+		tamper   func(response *webauthn.AssertionResponse)
+		expected error
+	}{
+		{
+			name:     "origin",
+			origin:   "https://evil.example",
+			expected: webauthn.ErrInvalidOrigin,
+		},
+		{
+			name:   "signature",
+			origin: "https://example.com",
+			tamper: func(response *webauthn.AssertionResponse) {
+				response.Response.Signature[len(response.Response.Signature)-1] ^= 0xff
+			},
+			expected: webauthn.ErrInvalidSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newWebAuthn()
+			store := session.NewStore("session", nil)
+			authenticator := webauthntest.NewAuthenticator("https://example.com")
+			u := &user{id: 1}
+
+			register(t, w, store, authenticator, u)
+
+			options, err := w.BeginLogin(ctx, store, u)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			authenticator.Origin = tt.origin
+			response, err := authenticator.Login(w.RPID, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.tamper != nil {
+				tt.tamper(response)
+			}
+
+			_, err = w.FinishLogin(ctx, store, response)
+			if !errors.Is(err, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
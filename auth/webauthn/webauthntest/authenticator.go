@@ -0,0 +1,192 @@
+// Package webauthntest provides a software authenticator to test WebAuthn
+// ceremonies without hardware.
+package webauthntest
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+
+	"github.com/ugorji/go/codec"
+	"github.com/wolftotem4/golava-core/auth/webauthn"
+)
+
+var ErrNoCredential = errors.New("no credential available for the request")
+
+type credential struct {
+	id         []byte
+	key        *ecdsa.PrivateKey
+	userHandle []byte
+	signCount  uint32
+}
+
+// Authenticator is an ES256 software authenticator that answers ceremonies
+// as a browser and security key would.
+type Authenticator struct {
+	Origin string
+
+	// Whether the user is reported as verified. Defaults to true.
+	SkipUserVerification bool
+
+	// Whether the signature counter is left at zero.
+	NoSignCount bool
+
+	AAGUID []byte
+
+	credentials []*credential
+}
+
+func NewAuthenticator(origin string) *Authenticator {
+	return &Authenticator{Origin: origin, AAGUID: make([]byte, 16)}
+}
+
+// Register creates a credential and returns the attestation response for the options.
+func (a *Authenticator) Register(options *webauthn.CreationOptions) (*webauthn.AttestationResponse, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	cred := &credential{
+		id:         make([]byte, 16),
+		key:        key,
+		userHandle: options.User.ID,
+	}
+	if _, err := rand.Read(cred.id); err != nil {
+		return nil, err
+	}
+
+	clientData, err := a.clientData("webauthn.create", options.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := encodeCBOR(map[int]any{
+		1:  2,  // kty: EC2
+		3:  -7, // alg: ES256
+		-1: 1,  // crv: P-256
+		-2: key.PublicKey.X.FillBytes(make([]byte, 32)),
+		-3: key.PublicKey.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var attested bytes.Buffer
+	attested.Write(a.AAGUID)
+	binary.Write(&attested, binary.BigEndian, uint16(len(cred.id)))
+	attested.Write(cred.id)
+	attested.Write(publicKey)
+
+	authData := a.authenticatorData(options.RP.ID, 0x40, 0, attested.Bytes())
+
+	attestationObject, err := encodeCBOR(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": authData,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	a.credentials = append(a.credentials, cred)
+
+	return &webauthn.AttestationResponse{
+		ID:    base64.RawURLEncoding.EncodeToString(cred.id),
+		RawID: cred.id,
+		Type:  "public-key",
+		Response: webauthn.AuthenticatorAttestationResponse{
+			ClientDataJSON:    clientData,
+			AttestationObject: attestationObject,
+			Transports:        []string{"internal"},
+		},
+	}, nil
+}
+
+// Login signs the challenge with the first allowed credential, or with any
+// credential when the options allow discoverable credentials.
+func (a *Authenticator) Login(rpID string, options *webauthn.RequestOptions) (*webauthn.AssertionResponse, error) {
+	cred := a.find(options.AllowCredentials)
+	if cred == nil {
+		return nil, ErrNoCredential
+	}
+
+	clientData, err := a.clientData("webauthn.get", options.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	if !a.NoSignCount {
+		cred.signCount++
+	}
+	authData := a.authenticatorData(rpID, 0, cred.signCount, nil)
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(bytes.Clone(authData), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, cred.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &webauthn.AssertionResponse{
+		ID:    base64.RawURLEncoding.EncodeToString(cred.id),
+		RawID: cred.id,
+		Type:  "public-key",
+		Response: webauthn.AuthenticatorAssertionResponse{
+			ClientDataJSON:    clientData,
+			AuthenticatorData: authData,
+			Signature:         signature,
+			UserHandle:        cred.userHandle,
+		},
+	}, nil
+}
+
+func (a *Authenticator) find(allowed []webauthn.CredentialDescriptor) *credential {
+	for _, cred := range a.credentials {
+		if len(allowed) == 0 {
+			return cred
+		}
+		for _, descriptor := range allowed {
+			if bytes.Equal(descriptor.ID, cred.id) {
+				return cred
+			}
+		}
+	}
+	return nil
+}
+
+func (a *Authenticator) clientData(ceremonyType string, challenge []byte) ([]byte, error) {
+	return json.Marshal(webauthn.CollectedClientData{
+		Type:      ceremonyType,
+		Challenge: base64.RawURLEncoding.EncodeToString(challenge),
+		Origin:    a.Origin,
+	})
+}
+
+func (a *Authenticator) authenticatorData(rpID string, flags byte, signCount uint32, attested []byte) []byte {
+	flags |= 0x01 // user present
+	if !a.SkipUserVerification {
+		flags |= 0x04
+	}
+
+	rpIDHash := sha256.Sum256([]byte(rpID))
+
+	var buf bytes.Buffer
+	buf.Write(rpIDHash[:])
+	buf.WriteByte(flags)
+	binary.Write(&buf, binary.BigEndian, signCount)
+	buf.Write(attested)
+	return buf.Bytes()
+}
+
+func encodeCBOR(value any) ([]byte, error) {
+	var buf bytes.Buffer
+	err := codec.NewEncoder(&buf, &codec.CborHandle{}).Encode(value)
+	return buf.Bytes(), err
+}
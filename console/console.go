@@ -0,0 +1,68 @@
+package console
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+)
+
+// Command is a console command, invoked by name, e.g. `golava route:list`.
+type Command struct {
+	Name        string
+	Description string
+	Run         func(ctx context.Context, args []string, out io.Writer) error
+}
+
+// Console dispatches command line arguments to the registered commands.
+type Console struct {
+	Out      io.Writer
+	commands map[string]*Command
+}
+
+func New() *Console {
+	return &Console{Out: os.Stdout, commands: make(map[string]*Command)}
+}
+
+func (c *Console) Register(commands ...*Command) {
+	for _, command := range commands {
+		c.commands[command.Name] = command
+	}
+}
+
+func (c *Console) Has(name string) bool {
+	_, ok := c.commands[name]
+	return ok
+}
+
+// Run runs the command named by args[0] with the remaining arguments.
+// The command list is printed when no command is given.
+func (c *Console) Run(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] == "list" {
+		return c.list()
+	}
+
+	command, ok := c.commands[args[0]]
+	if !ok {
+		return fmt.Errorf("command %q is not defined", args[0])
+	}
+
+	return command.Run(ctx, args[1:], c.Out)
+}
+
+func (c *Console) list() error {
+	names := make([]string, 0, len(c.commands))
+	for name := range c.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w := tabwriter.NewWriter(c.Out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "Available commands:")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s\t%s\n", name, c.commands[name].Description)
+	}
+	return w.Flush()
+}
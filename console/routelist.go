@@ -0,0 +1,69 @@
+package console
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wolftotem4/golava-core/routing"
+)
+
+// RouteList lists the routes of the engine:
+//
+//	golava route:list [--json] [--path=/admin] [--name=users.] [--method=GET] [--without=VerifyCsrfToken]
+func RouteList(router *routing.Router, engine *gin.Engine) *Command {
+	return &Command{
+		Name:        "route:list",
+		Description: "List all registered routes",
+		Run: func(ctx context.Context, args []string, out io.Writer) error {
+			var (
+				filter  routing.RouteFilter
+				asJson  bool
+				without string
+			)
+
+			flags := flag.NewFlagSet("route:list", flag.ContinueOnError)
+			flags.SetOutput(out)
+			flags.BoolVar(&asJson, "json", false, "Output the routes as JSON")
+			flags.StringVar(&filter.Path, "path", "", "Only show routes matching the given path prefix")
+			flags.StringVar(&filter.Name, "name", "", "Only show routes matching the given name prefix")
+			flags.StringVar(&filter.Method, "method", "", "Only show routes matching the given method")
+			flags.StringVar(&without, "without", "", "Only show routes lacking the given middleware")
+			if err := flags.Parse(args); err != nil {
+				return err
+			}
+
+			routes := router.List(engine, filter)
+			if without != "" {
+				filtered := routes[:0]
+				for _, route := range routes {
+					if !route.HasMiddleware(without) {
+						filtered = append(filtered, route)
+					}
+				}
+				routes = filtered
+			}
+
+			if asJson {
+				if routes == nil {
+					routes = []routing.RouteInfo{}
+				}
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(routes)
+			}
+
+			w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "METHOD\tPATH\tNAME\tHANDLER\tMIDDLEWARE")
+			for _, route := range routes {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", route.Method, route.Path, route.Name, route.Handler, strings.Join(route.Middleware, ", "))
+			}
+			return w.Flush()
+		},
+	}
+}
@@ -0,0 +1,65 @@
+package console
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/wolftotem4/golava-core/session"
+	"github.com/wolftotem4/golava-core/session/mysql"
+	"github.com/wolftotem4/golava-core/session/postgres"
+	"github.com/wolftotem4/golava-core/session/sqlite"
+	"github.com/wolftotem4/golava-core/session/sqlserver"
+)
+
+var sessionSchemas = map[string]func(table string) []string{
+	"mysql":     mysql.Schema,
+	"postgres":  postgres.Schema,
+	"sqlite":    sqlite.Schema,
+	"sqlserver": sqlserver.Schema,
+}
+
+// SessionTable prints the statements creating the session table:
+//
+//	golava session:table [--driver=mysql] [--table=sessions]
+//
+// Without flags, the schema of the handler is printed, if it stores the sessions in a SQL table.
+func SessionTable(handler session.SessionHandler) *Command {
+	return &Command{
+		Name:        "session:table",
+		Description: "Print the statements creating the session table",
+		Run: func(ctx context.Context, args []string, out io.Writer) error {
+			var driver, table string
+
+			flags := flag.NewFlagSet("session:table", flag.ContinueOnError)
+			flags.SetOutput(out)
+			flags.StringVar(&driver, "driver", "", "Database driver: mysql, postgres, sqlite or sqlserver")
+			flags.StringVar(&table, "table", "sessions", "Name of the table")
+			if err := flags.Parse(args); err != nil {
+				return err
+			}
+
+			var statements []string
+			if driver != "" {
+				schema, ok := sessionSchemas[driver]
+				if !ok {
+					return fmt.Errorf("unknown driver %q", driver)
+				}
+				statements = schema(table)
+			} else if migrator, ok := handler.(session.TableMigrator); ok {
+				statements = migrator.Schema()
+			} else {
+				return fmt.Errorf("the session handler has no table, use --driver")
+			}
+
+			for _, statement := range statements {
+				if _, err := fmt.Fprintln(out, strings.TrimSpace(statement)+";\n"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
@@ -2,6 +2,9 @@ package golava
 
 import (
 	ut "github.com/go-playground/universal-translator"
+	"github.com/wolftotem4/golava-core/auth/gate"
+	"github.com/wolftotem4/golava-core/auth/manager"
+	"github.com/wolftotem4/golava-core/auth/rbac"
 	"github.com/wolftotem4/golava-core/cookie"
 	"github.com/wolftotem4/golava-core/encryption"
 	"github.com/wolftotem4/golava-core/hashing"
@@ -24,8 +27,18 @@ type App struct {
 	SessionFactory *session.SessionFactory
 	Translation    *ut.UniversalTranslator
 	AppLocale      string
+	AuthManager    *manager.Manager
+	Gate           *gate.Gate
+	RoleProvider   rbac.RoleProvider
 }
 
 func (a *App) Base() *App {
 	return a
 }
+
+// Boot makes the router sign URLs with AppKey unless it has a Key of its own, and must be called once the App is set up.
+func (a *App) Boot() {
+	if a.Router != nil && len(a.Router.Key) == 0 {
+		a.Router.Key = a.AppKey
+	}
+}
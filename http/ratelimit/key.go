@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wolftotem4/golava-core/instance"
+)
+
+// KeyFunc identifies the client a request is counted against. An empty key
+// exempts the request from the limit.
+type KeyFunc func(c *gin.Context) string
+
+func ByIP(c *gin.Context) string {
+	return c.ClientIP()
+}
+
+// ByUser keys authenticated requests by user ID and guests by IP address.
+func ByUser(c *gin.Context) string {
+	i := instance.MustGetInstance(c)
+
+	if i.Auth != nil && i.Auth.Check() {
+		return fmt.Sprintf("user:%v", i.Auth.ID())
+	}
+
+	return "ip:" + c.ClientIP()
+}
+
+func ByRoute(c *gin.Context) string {
+	return c.Request.Method + " " + c.FullPath()
+}
+
+// Keys combines keys, e.g. Keys(ByUser, ByRoute) for a limit per user and route.
+func Keys(funcs ...KeyFunc) KeyFunc {
+	return func(c *gin.Context) string {
+		keys := make([]string, len(funcs))
+		for i, fn := range funcs {
+			keys[i] = fn(c)
+			if keys[i] == "" {
+				return ""
+			}
+		}
+		return strings.Join(keys, "|")
+	}
+}
@@ -0,0 +1,154 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidWindow is returned by the window limiters when their Window is not positive.
+var ErrInvalidWindow = errors.New("rate limit window must be positive")
+
+// ErrInvalidBucket is returned by TokenBucket when its Capacity or Refill is not positive.
+var ErrInvalidBucket = errors.New("token bucket capacity and refill must be positive")
+
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+type Limiter interface {
+	Allow(ctx context.Context, key string) (Result, error)
+}
+
+// FixedWindow allows Limit requests per key in each window of the given length.
+type FixedWindow struct {
+	Store  Store
+	Limit  int
+	Window time.Duration
+	Now    func() time.Time
+}
+
+func (l *FixedWindow) Allow(ctx context.Context, key string) (Result, error) {
+	if l.Window <= 0 {
+		return Result{}, ErrInvalidWindow
+	}
+
+	now := currentTime(l.Now)
+	index := now.UnixNano() / int64(l.Window)
+	end := time.Unix(0, (index+1)*int64(l.Window))
+
+	count, err := l.Store.Increment(ctx, fmt.Sprintf("%s|%d", key, index), end.Sub(now))
+	if err != nil {
+		return Result{}, err
+	}
+
+	if count > int64(l.Limit) {
+		return Result{Limit: l.Limit, RetryAfter: end.Sub(now)}, nil
+	}
+
+	return Result{Allowed: true, Limit: l.Limit, Remaining: l.Limit - int(count)}, nil
+}
+
+// SlidingWindow weighs the previous window's count by its overlap with the
+// last Window, smoothing the bursts a fixed window allows at its boundaries.
+// As with FixedWindow, rejected requests are counted too.
+type SlidingWindow struct {
+	Store  Store
+	Limit  int
+	Window time.Duration
+	Now    func() time.Time
+}
+
+func (l *SlidingWindow) Allow(ctx context.Context, key string) (Result, error) {
+	if l.Window <= 0 {
+		return Result{}, ErrInvalidWindow
+	}
+
+	now := currentTime(l.Now)
+	index := now.UnixNano() / int64(l.Window)
+	elapsed := time.Duration(now.UnixNano() - index*int64(l.Window))
+
+	// Counted before deciding, so that concurrent requests cannot all pass on the same count.
+	current, err := l.Store.Increment(ctx, fmt.Sprintf("%s|%d", key, index), 2*l.Window-elapsed)
+	if err != nil {
+		return Result{}, err
+	}
+
+	previous, err := l.Store.Get(ctx, fmt.Sprintf("%s|%d", key, index-1))
+	if err != nil {
+		return Result{}, err
+	}
+
+	weight := float64(l.Window-elapsed) / float64(l.Window)
+	estimate := float64(previous)*weight + float64(current)
+
+	if estimate > float64(l.Limit) {
+		retryAfter := l.Window - elapsed
+		if current <= int64(l.Limit) && previous > 0 {
+			// the time until the previous window's weight has decayed enough
+			retryAfter = time.Duration((1-float64(int64(l.Limit)-current)/float64(previous))*float64(l.Window)) - elapsed
+		}
+		return Result{Limit: l.Limit, RetryAfter: max(retryAfter, time.Second)}, nil
+	}
+
+	remaining := l.Limit - int(estimate+0.999999)
+	return Result{Allowed: true, Limit: l.Limit, Remaining: max(remaining, 0)}, nil
+}
+
+// TokenBucket allows bursts of up to Capacity requests, with one token refilled
+// every Refill. It is implemented as the generic cell rate algorithm, which
+// keeps a single timestamp per key.
+type TokenBucket struct {
+	Store    Store
+	Capacity int
+	Refill   time.Duration
+	Now      func() time.Time
+}
+
+// Number of times a concurrently updated bucket is read again.
+const maxSwapAttempts = 10
+
+func (l *TokenBucket) Allow(ctx context.Context, key string) (Result, error) {
+	if l.Capacity <= 0 || l.Refill <= 0 {
+		return Result{}, ErrInvalidBucket
+	}
+
+	burst := int64(l.Capacity) * int64(l.Refill)
+
+	for i := 0; i < maxSwapAttempts; i++ {
+		now := currentTime(l.Now).UnixNano()
+
+		stored, err := l.Store.Get(ctx, key)
+		if err != nil {
+			return Result{}, err
+		}
+
+		tat := max(stored, now)
+		newTat := tat + int64(l.Refill)
+		allowAt := newTat - burst
+
+		if now < allowAt {
+			return Result{Limit: l.Capacity, RetryAfter: time.Duration(allowAt - now)}, nil
+		}
+
+		swapped, err := l.Store.CompareAndSwap(ctx, key, stored, newTat, time.Duration(newTat-now))
+		if err != nil {
+			return Result{}, err
+		} else if swapped {
+			return Result{Allowed: true, Limit: l.Capacity, Remaining: int((now - allowAt) / int64(l.Refill))}, nil
+		}
+	}
+
+	return Result{}, fmt.Errorf("rate limit key %q is updated concurrently", key)
+}
+
+func currentTime(now func() time.Time) time.Time {
+	if now != nil {
+		return now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,109 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type MySQLStore struct {
+	DB    *sql.DB
+	Table string
+}
+
+func NewMySQLStore(db *sql.DB, table string) *MySQLStore {
+	return &MySQLStore{
+		DB:    db,
+		Table: table,
+	}
+}
+
+func (d *MySQLStore) Get(ctx context.Context, key string) (int64, error) {
+	row := d.DB.QueryRowContext(ctx, fmt.Sprintf(
+		"SELECT value FROM `%s` WHERE id = ? AND expires_at > ?", d.Table,
+	), key, time.Now().Unix())
+	if err := row.Err(); err != nil {
+		return 0, err
+	}
+
+	var value int64
+	err := row.Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+
+	return value, err
+}
+
+func (d *MySQLStore) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	now := time.Now()
+	_, err := d.DB.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			"INSERT INTO `%s` (id, value, expires_at) VALUES (?, 1, ?) ON DUPLICATE KEY UPDATE value = IF(expires_at <= ?, 1, value + 1), expires_at = IF(value = 1, VALUES(expires_at), expires_at)",
+			d.Table,
+		),
+		key, expiresAt(now, ttl), now.Unix(),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.Get(ctx, key)
+}
+
+func (d *MySQLStore) CompareAndSwap(ctx context.Context, key string, old int64, new int64, ttl time.Duration) (bool, error) {
+	now := time.Now()
+
+	var (
+		result sql.Result
+		err    error
+	)
+	if old == 0 {
+		result, err = d.DB.ExecContext(ctx, fmt.Sprintf(
+			"UPDATE `%s` SET value = ?, expires_at = ? WHERE id = ? AND expires_at <= ?", d.Table,
+		), new, expiresAt(now, ttl), key, now.Unix())
+		if err != nil {
+			return false, err
+		}
+
+		if swapped, err := affected(result); swapped || err != nil {
+			return swapped, err
+		}
+
+		result, err = d.DB.ExecContext(ctx, fmt.Sprintf(
+			"INSERT IGNORE INTO `%s` (id, value, expires_at) VALUES (?, ?, ?)", d.Table,
+		), key, new, expiresAt(now, ttl))
+	} else {
+		result, err = d.DB.ExecContext(ctx, fmt.Sprintf(
+			"UPDATE `%s` SET value = ?, expires_at = ? WHERE id = ? AND expires_at > ? AND value = ?", d.Table,
+		), new, expiresAt(now, ttl), key, now.Unix(), old)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return affected(result)
+}
+
+func (d *MySQLStore) GC(ctx context.Context) (int64, error) {
+	result, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		"DELETE FROM `%s` WHERE expires_at <= ?", d.Table,
+	), time.Now().Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func expiresAt(now time.Time, ttl time.Duration) int64 {
+	return now.Add(ttl + time.Second - 1).Unix()
+}
+
+func affected(result sql.Result) (bool, error) {
+	n, err := result.RowsAffected()
+	return n == 1, err
+}
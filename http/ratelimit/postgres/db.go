@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type PostgresStore struct {
+	DB    *sql.DB
+	Table string
+}
+
+func NewPostgresStore(db *sql.DB, table string) *PostgresStore {
+	return &PostgresStore{
+		DB:    db,
+		Table: table,
+	}
+}
+
+func (d *PostgresStore) Get(ctx context.Context, key string) (int64, error) {
+	row := d.DB.QueryRowContext(ctx, fmt.Sprintf(
+		`SELECT value FROM "%s" WHERE id = $1 AND expires_at > $2`, d.Table,
+	), key, time.Now().Unix())
+	if err := row.Err(); err != nil {
+		return 0, err
+	}
+
+	var value int64
+	err := row.Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+
+	return value, err
+}
+
+func (d *PostgresStore) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	now := time.Now()
+	_, err := d.DB.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			`INSERT INTO "%s" AS t (id, value, expires_at) VALUES ($1, 1, $2) ON CONFLICT (id) DO UPDATE SET value = CASE WHEN t.expires_at <= $3 THEN 1 ELSE t.value + 1 END, expires_at = CASE WHEN t.expires_at <= $3 THEN EXCLUDED.expires_at ELSE t.expires_at END`,
+			d.Table,
+		),
+		key, expiresAt(now, ttl), now.Unix(),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.Get(ctx, key)
+}
+
+func (d *PostgresStore) CompareAndSwap(ctx context.Context, key string, old int64, new int64, ttl time.Duration) (bool, error) {
+	now := time.Now()
+
+	var (
+		result sql.Result
+		err    error
+	)
+	if old == 0 {
+		result, err = d.DB.ExecContext(ctx, fmt.Sprintf(
+			`UPDATE "%s" SET value = $1, expires_at = $2 WHERE id = $3 AND expires_at <= $4`, d.Table,
+		), new, expiresAt(now, ttl), key, now.Unix())
+		if err != nil {
+			return false, err
+		}
+
+		if swapped, err := affected(result); swapped || err != nil {
+			return swapped, err
+		}
+
+		result, err = d.DB.ExecContext(ctx, fmt.Sprintf(
+			`INSERT INTO "%s" (id, value, expires_at) VALUES ($1, $2, $3) ON CONFLICT (id) DO NOTHING`, d.Table,
+		), key, new, expiresAt(now, ttl))
+	} else {
+		result, err = d.DB.ExecContext(ctx, fmt.Sprintf(
+			`UPDATE "%s" SET value = $1, expires_at = $2 WHERE id = $3 AND expires_at > $4 AND value = $5`, d.Table,
+		), new, expiresAt(now, ttl), key, now.Unix(), old)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return affected(result)
+}
+
+func (d *PostgresStore) GC(ctx context.Context) (int64, error) {
+	result, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		`DELETE FROM "%s" WHERE expires_at <= $1`, d.Table,
+	), time.Now().Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func expiresAt(now time.Time, ttl time.Duration) int64 {
+	return now.Add(ttl + time.Second - 1).Unix()
+}
+
+func affected(result sql.Result) (bool, error) {
+	n, err := result.RowsAffected()
+	return n == 1, err
+}
@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wolftotem4/golava-core/http/utils"
+)
+
+var (
+	ErrTooManyRequests   = errors.New("too many requests")
+	ErrLimiterNotDefined = errors.New("rate limiter not defined")
+)
+
+type namedLimiter struct {
+	limiter Limiter
+	key     KeyFunc
+}
+
+// Limiters holds the limiters by name, e.g. "api" or "uploads".
+type Limiters struct {
+	mu       sync.RWMutex
+	limiters map[string]namedLimiter
+}
+
+func NewLimiters() *Limiters {
+	return &Limiters{limiters: make(map[string]namedLimiter)}
+}
+
+func (l *Limiters) Define(name string, limiter Limiter, key KeyFunc) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limiters[name] = namedLimiter{limiter: limiter, key: key}
+}
+
+func (l *Limiters) Has(name string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	_, ok := l.limiters[name]
+	return ok
+}
+
+// Attempt counts the request against the named limiter.
+func (l *Limiters) Attempt(c *gin.Context, name string) (Result, error) {
+	l.mu.RLock()
+	named, ok := l.limiters[name]
+	l.mu.RUnlock()
+
+	if !ok {
+		return Result{}, fmt.Errorf("%w: %s", ErrLimiterNotDefined, name)
+	}
+
+	key := named.key(c)
+	if key == "" {
+		return Result{Allowed: true, Limit: -1}, nil
+	}
+
+	return named.limiter.Allow(c, name+"|"+key)
+}
+
+// Throttle rejects requests over the named limit with 429 Too Many Requests.
+// Wrap it with filter.Except to exclude paths.
+func Throttle(limiters *Limiters, name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, err := limiters.Attempt(c, name)
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		if result.Limit >= 0 {
+			c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+			c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		}
+
+		if !result.Allowed {
+			retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.Error(ErrTooManyRequests)
+
+			if utils.ExpectJson(c.GetHeader("Accept")) {
+				c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+					"message":     "Too Many Requests",
+					"retry_after": retryAfter,
+				})
+			} else {
+				c.Data(http.StatusTooManyRequests, "text/html; charset=utf-8", []byte("<!DOCTYPE html><title>429 Too Many Requests</title><h1>Too Many Requests</h1>"))
+				c.Abort()
+			}
+			return
+		}
+
+		c.Next()
+	}
+}
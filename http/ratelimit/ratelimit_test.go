@@ -0,0 +1,194 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func TestFixedWindow(t *testing.T) {
+	ctx := context.Background()
+	clock := &clock{now: time.Unix(1000, 0)}
+	limiter := &FixedWindow{Store: NewMemoryStore(), Limit: 2, Window: time.Minute, Now: clock.Now}
+
+	for i, expected := range []bool{true, true, false} {
+		result, err := limiter.Allow(ctx, "key")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Allowed != expected {
+			t.Fatalf("request %d: expected allowed to be %v", i, expected)
+		}
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+	result, err := limiter.Allow(ctx, "key")
+	if err != nil {
+		t.Fatal(err)
+	} else if !result.Allowed || result.Remaining != 1 {
+		t.Fatalf("expected a new window, got %+v", result)
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	ctx := context.Background()
+	clock := &clock{now: time.Unix(960, 0)}
+	limiter := &SlidingWindow{Store: NewMemoryStore(), Limit: 4, Window: time.Minute, Now: clock.Now}
+
+	for i := 0; i < 4; i++ {
+		result, err := limiter.Allow(ctx, "key")
+		if err != nil {
+			t.Fatal(err)
+		} else if !result.Allowed {
+			t.Fatalf("request %d should be allowed", i)
+		}
+	}
+
+	// a quarter into the next window, three quarters of the previous count still weigh in
+	clock.now = clock.now.Add(75 * time.Second)
+	result, err := limiter.Allow(ctx, "key")
+	if err != nil {
+		t.Fatal(err)
+	} else if !result.Allowed || result.Remaining != 0 {
+		t.Fatalf("expected the last request to be allowed, got %+v", result)
+	}
+
+	result, err = limiter.Allow(ctx, "key")
+	if err != nil {
+		t.Fatal(err)
+	} else if result.Allowed || result.RetryAfter <= 0 {
+		t.Fatalf("expected the request to be limited, got %+v", result)
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	ctx := context.Background()
+	clock := &clock{now: time.Unix(1000, 0)}
+	limiter := &TokenBucket{Store: NewMemoryStore(), Capacity: 3, Refill: time.Second, Now: clock.Now}
+
+	for i := 0; i < 3; i++ {
+		result, err := limiter.Allow(ctx, "key")
+		if err != nil {
+			t.Fatal(err)
+		} else if !result.Allowed || result.Remaining != 2-i {
+			t.Fatalf("request %d: unexpected result %+v", i, result)
+		}
+	}
+
+	result, err := limiter.Allow(ctx, "key")
+	if err != nil {
+		t.Fatal(err)
+	} else if result.Allowed || result.RetryAfter != time.Second {
+		t.Fatalf("expected the bucket to be empty, got %+v", result)
+	}
+
+	clock.now = clock.now.Add(time.Second)
+	result, err = limiter.Allow(ctx, "key")
+	if err != nil {
+		t.Fatal(err)
+	} else if !result.Allowed || result.Remaining != 0 {
+		t.Fatalf("expected a refilled token, got %+v", result)
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	limiters := NewLimiters()
+	limiters.Define("api", &FixedWindow{Store: NewMemoryStore(), Limit: 1, Window: time.Hour}, ByIP)
+
+	r := gin.New()
+	r.GET("/", Throttle(limiters, "api"), func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	request := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", "application/json")
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request()
+	if w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Fatalf("unexpected response %d %v", w.Code, w.Header())
+	}
+
+	w = request()
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" || w.Header().Get("Content-Type") != "application/json; charset=utf-8" {
+		t.Fatalf("unexpected headers %v", w.Header())
+	}
+}
+
+func TestSlidingWindowConcurrent(t *testing.T) {
+	ctx := context.Background()
+	clock := &clock{now: time.Unix(960, 0)}
+	limiter := &SlidingWindow{Store: NewMemoryStore(), Limit: 5, Window: time.Minute, Now: clock.Now}
+
+	var wg sync.WaitGroup
+	var allowed atomic.Int32
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := limiter.Allow(ctx, "key")
+			if err != nil {
+				t.Error(err)
+			} else if result.Allowed {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if allowed.Load() != 5 {
+		t.Errorf("expected 5 requests to be allowed but got %d", allowed.Load())
+	}
+}
+
+func TestWindowNotSet(t *testing.T) {
+	ctx := context.Background()
+
+	for _, limiter := range []Limiter{
+		&FixedWindow{Store: NewMemoryStore(), Limit: 1},
+		&SlidingWindow{Store: NewMemoryStore(), Limit: 1},
+	} {
+		if _, err := limiter.Allow(ctx, "key"); !errors.Is(err, ErrInvalidWindow) {
+			t.Errorf("%T: expected ErrInvalidWindow, got %v", limiter, err)
+		}
+	}
+}
+
+func TestTokenBucketNotSet(t *testing.T) {
+	ctx := context.Background()
+
+	for _, limiter := range []*TokenBucket{
+		{Store: NewMemoryStore()},
+		{Store: NewMemoryStore(), Capacity: 1},
+		{Store: NewMemoryStore(), Refill: time.Second},
+		{Store: NewMemoryStore(), Capacity: -1, Refill: time.Second},
+	} {
+		if _, err := limiter.Allow(ctx, "key"); !errors.Is(err, ErrInvalidBucket) {
+			t.Errorf("%+v: expected ErrInvalidBucket, got %v", *limiter, err)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package sqlserver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type SQLServerStore struct {
+	DB    *sql.DB
+	Table string
+}
+
+func NewSQLServerStore(db *sql.DB, table string) *SQLServerStore {
+	return &SQLServerStore{
+		DB:    db,
+		Table: table,
+	}
+}
+
+func (d *SQLServerStore) Get(ctx context.Context, key string) (int64, error) {
+	row := d.DB.QueryRowContext(ctx, fmt.Sprintf(
+		"SELECT value FROM [%s] WHERE id = @p1 AND expires_at > @p2", d.Table,
+	), key, time.Now().Unix())
+	if err := row.Err(); err != nil {
+		return 0, err
+	}
+
+	var value int64
+	err := row.Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+
+	return value, err
+}
+
+func (d *SQLServerStore) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	now := time.Now()
+	_, err := d.DB.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			`
+BEGIN tran
+	UPDATE [%s] WITH (serializable) SET value = CASE WHEN expires_at <= @p3 THEN 1 ELSE value + 1 END, expires_at = CASE WHEN expires_at <= @p3 THEN @p2 ELSE expires_at END WHERE id = @p1;
+	IF @@rowcount = 0
+	BEGIN
+		INSERT INTO [%[1]s] (id, value, expires_at) VALUES (@p1, 1, @p2);
+	END
+COMMIT tran`,
+			d.Table,
+		),
+		key, expiresAt(now, ttl), now.Unix(),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.Get(ctx, key)
+}
+
+func (d *SQLServerStore) CompareAndSwap(ctx context.Context, key string, old int64, new int64, ttl time.Duration) (bool, error) {
+	now := time.Now()
+
+	var (
+		result sql.Result
+		err    error
+	)
+	if old == 0 {
+		result, err = d.DB.ExecContext(ctx, fmt.Sprintf(
+			"UPDATE [%s] SET value = @p1, expires_at = @p2 WHERE id = @p3 AND expires_at <= @p4", d.Table,
+		), new, expiresAt(now, ttl), key, now.Unix())
+		if err != nil {
+			return false, err
+		}
+
+		if swapped, err := affected(result); swapped || err != nil {
+			return swapped, err
+		}
+
+		result, err = d.DB.ExecContext(ctx, fmt.Sprintf(
+			"INSERT INTO [%[1]s] (id, value, expires_at) SELECT @p1, @p2, @p3 WHERE NOT EXISTS (SELECT 1 FROM [%[1]s] WITH (updlock, holdlock) WHERE id = @p1)", d.Table,
+		), key, new, expiresAt(now, ttl))
+	} else {
+		result, err = d.DB.ExecContext(ctx, fmt.Sprintf(
+			"UPDATE [%s] SET value = @p1, expires_at = @p2 WHERE id = @p3 AND expires_at > @p4 AND value = @p5", d.Table,
+		), new, expiresAt(now, ttl), key, now.Unix(), old)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return affected(result)
+}
+
+func (d *SQLServerStore) GC(ctx context.Context) (int64, error) {
+	result, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+		"DELETE FROM [%s] WHERE expires_at <= @p1", d.Table,
+	), time.Now().Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func expiresAt(now time.Time, ttl time.Duration) int64 {
+	return now.Add(ttl + time.Second - 1).Unix()
+}
+
+func affected(result sql.Result) (bool, error) {
+	n, err := result.RowsAffected()
+	return n == 1, err
+}
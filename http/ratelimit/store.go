@@ -0,0 +1,119 @@
+package ratelimit
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+type Store interface {
+	// Get returns zero when the key is missing or expired.
+	Get(ctx context.Context, key string) (int64, error)
+
+	// Increment adds one to the counter, which expires after ttl when it is created.
+	Increment(ctx context.Context, key string, ttl time.Duration) (int64, error)
+
+	// CompareAndSwap replaces the value only if it is still old. An old value of
+	// zero stands for a missing or expired key.
+	CompareAndSwap(ctx context.Context, key string, old int64, new int64, ttl time.Duration) (bool, error)
+}
+
+const (
+	shardCount    = 64
+	pruneInterval = 1024
+)
+
+type memoryEntry struct {
+	value     int64
+	expiresAt time.Time
+}
+
+type memoryShard struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	writes  int
+}
+
+// MemoryStore keeps the counters of a single process in sharded maps.
+type MemoryStore struct {
+	shards [shardCount]*memoryShard
+}
+
+func NewMemoryStore() *MemoryStore {
+	store := &MemoryStore{}
+	for i := range store.shards {
+		store.shards[i] = &memoryShard{entries: make(map[string]memoryEntry)}
+	}
+	return store
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) (int64, error) {
+	shard := s.shard(key)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	entry, ok := shard.get(key, time.Now())
+	if !ok {
+		return 0, nil
+	}
+	return entry.value, nil
+}
+
+func (s *MemoryStore) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	shard := s.shard(key)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	now := time.Now()
+	entry, ok := shard.get(key, now)
+	if !ok {
+		entry = memoryEntry{expiresAt: now.Add(ttl)}
+	}
+
+	entry.value++
+	shard.set(key, entry, now)
+	return entry.value, nil
+}
+
+func (s *MemoryStore) CompareAndSwap(ctx context.Context, key string, old int64, new int64, ttl time.Duration) (bool, error) {
+	shard := s.shard(key)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	now := time.Now()
+	entry, _ := shard.get(key, now)
+	if entry.value != old {
+		return false, nil
+	}
+
+	shard.set(key, memoryEntry{value: new, expiresAt: now.Add(ttl)}, now)
+	return true, nil
+}
+
+func (s *MemoryStore) shard(key string) *memoryShard {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return s.shards[h.Sum32()%shardCount]
+}
+
+func (s *memoryShard) get(key string, now time.Time) (memoryEntry, bool) {
+	entry, ok := s.entries[key]
+	if !ok || !entry.expiresAt.After(now) {
+		return memoryEntry{}, false
+	}
+	return entry, true
+}
+
+func (s *memoryShard) set(key string, entry memoryEntry, now time.Time) {
+	s.entries[key] = entry
+
+	s.writes++
+	if s.writes%pruneInterval == 0 {
+		for key, entry := range s.entries {
+			if !entry.expiresAt.After(now) {
+				delete(s.entries, key)
+			}
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	ut "github.com/go-playground/universal-translator"
 	"github.com/wolftotem4/golava-core/auth"
 	"github.com/wolftotem4/golava-core/auth/generic"
+	"github.com/wolftotem4/golava-core/auth/manager"
 	"github.com/wolftotem4/golava-core/cookie"
 	"github.com/wolftotem4/golava-core/golava"
 	"github.com/wolftotem4/golava-core/lang"
@@ -21,6 +22,9 @@ type Instance struct {
 	Auth       auth.Guard
 	Redirector *routing.Redirector
 	Locale     string
+
+	// The guards of App.AuthManager resolved for the request.
+	Guards *manager.Guards
 }
 
 func NewInstance(app golava.GolavaApp) gin.HandlerFunc {
@@ -34,6 +38,10 @@ func NewInstance(app golava.GolavaApp) gin.HandlerFunc {
 			Auth: &generic.NullGuard{},
 		}
 
+		if authManager := app.Base().AuthManager; authManager != nil {
+			i.Guards = authManager.ForRequest(c)
+		}
+
 		c.Set("instance", i)
 
 		c.Next()
@@ -91,4 +99,8 @@ func (i *Instance) Dispose() {
 	i.App = nil
 	i.Session = nil
 	i.Auth = nil
+	if i.Guards != nil {
+		i.Guards.Dispose()
+		i.Guards = nil
+	}
 }
@@ -5,21 +5,33 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/wolftotem4/golava-core/instance"
+	"github.com/wolftotem4/golava-core/routing"
 )
 
-// prevent the middleware from being executed on the specified paths
+// prevent the middleware from being executed on the paths matching the patterns
+// See Matcher for the pattern syntax
 func Except(middleware gin.HandlerFunc, excepts ...string) gin.HandlerFunc {
-	for i := range excepts {
-		excepts[i] = cleanPath(excepts[i])
-	}
+	return ExceptMatcher(middleware, MustCompile(excepts...))
+}
 
-	return ExceptMatch(middleware, func(path string) bool {
-		for _, except := range excepts {
-			if path == except {
-				return true
-			}
+func ExceptMatcher(middleware gin.HandlerFunc, matcher *Matcher) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if matcher.Match(c) {
+			c.Next()
+			return
 		}
-		return false
+
+		middleware(c)
+	}
+}
+
+// ExceptMiddleware is Except for a routing.Middleware, which routing.Router.List
+// leaves out of the routes matching the patterns
+func ExceptMiddleware(middleware routing.Middleware, excepts ...string) routing.Middleware {
+	matcher := MustCompile(excepts...)
+
+	return describe(middleware, ExceptMatcher(middleware.Handler, matcher), func(router *routing.Router, route routing.RouteInfo) bool {
+		return matcher.matchRouteInfo(router, route)
 	})
 }
 
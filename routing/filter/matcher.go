@@ -0,0 +1,235 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wolftotem4/golava-core/instance"
+	"github.com/wolftotem4/golava-core/routing"
+)
+
+// Matcher matches requests against a compiled list of patterns
+//
+// A pattern is one of:
+//
+//	webhooks/stripe        exact path, relative to the router's base URL
+//	api/*                  glob, * matches within a path segment
+//	webhooks/**            glob, ** matches any number of segments
+//	regex:^api/v[0-9]+/    regular expression, matched against the relative path
+//	route:admin.*          route name, * matches any characters
+//
+// Any pattern may be qualified by HTTP methods, e.g. "POST webhooks/stripe" or "GET|HEAD api/*"
+type Matcher struct {
+	// keyed by method, "" applies to every method
+	exact  map[string]map[string]struct{}
+	paths  map[string]*regexp.Regexp
+	routes map[string]*regexp.Regexp
+}
+
+// Compile compiles the patterns into a Matcher
+// Globs and regular expressions sharing a method are merged into a single regular expression
+func Compile(patterns ...string) (*Matcher, error) {
+	m := &Matcher{
+		exact:  make(map[string]map[string]struct{}),
+		paths:  make(map[string]*regexp.Regexp),
+		routes: make(map[string]*regexp.Regexp),
+	}
+
+	paths := make(map[string][]string)
+	routes := make(map[string][]string)
+
+	for _, pattern := range patterns {
+		methods, pattern, err := splitMethods(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, method := range methods {
+			switch {
+			case strings.HasPrefix(pattern, "route:"):
+				routes[method] = append(routes[method], "^"+nameToRegexp(strings.TrimPrefix(pattern, "route:"))+"$")
+			case strings.HasPrefix(pattern, "regex:"):
+				expr := strings.TrimPrefix(pattern, "regex:")
+				if _, err := regexp.Compile(expr); err != nil {
+					return nil, fmt.Errorf("filter: invalid pattern %q: %w", pattern, err)
+				}
+				paths[method] = append(paths[method], expr)
+			case strings.ContainsAny(pattern, "*?"):
+				paths[method] = append(paths[method], "^"+globToRegexp(cleanPath(pattern))+"$")
+			default:
+				if m.exact[method] == nil {
+					m.exact[method] = make(map[string]struct{})
+				}
+				m.exact[method][cleanPath(pattern)] = struct{}{}
+			}
+		}
+	}
+
+	for method, exprs := range paths {
+		m.paths[method] = regexp.MustCompile(joinRegexp(exprs))
+	}
+	for method, exprs := range routes {
+		m.routes[method] = regexp.MustCompile(joinRegexp(exprs))
+	}
+
+	return m, nil
+}
+
+// MustCompile is like Compile but panics if a pattern is invalid
+func MustCompile(patterns ...string) *Matcher {
+	m, err := Compile(patterns...)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Match reports whether the request matches any pattern
+func (m *Matcher) Match(c *gin.Context) bool {
+	router := instance.MustGetInstance(c).App.Base().Router
+	method := c.Request.Method
+
+	path, isRelative := router.RelativePath(c.Request.URL.Path)
+	if isRelative && m.MatchPath(method, path) {
+		return true
+	}
+
+	if len(m.routes) == 0 || c.FullPath() == "" {
+		return false
+	}
+
+	name := router.RouteName(method, c.FullPath())
+	return name != "" && m.MatchRoute(method, name)
+}
+
+// matchRouteInfo is Match for a route listed by routing.Router.List, its path pattern standing for the request path
+func (m *Matcher) matchRouteInfo(router *routing.Router, route routing.RouteInfo) bool {
+	path, isRelative := router.RelativePath(route.Path)
+	if isRelative && m.MatchPath(route.Method, path) {
+		return true
+	}
+
+	return route.Name != "" && m.MatchRoute(route.Method, route.Name)
+}
+
+// describe wraps the filtered handler of the middleware, skipping the routes the filter lets through
+// on top of those the middleware skips itself
+func describe(middleware routing.Middleware, handler gin.HandlerFunc, skips func(router *routing.Router, route routing.RouteInfo) bool) routing.Middleware {
+	name := middleware.Name
+	if name == "" {
+		name = routing.FunctionName(middleware.Handler)
+	}
+
+	return routing.Middleware{
+		Name:    name,
+		Handler: handler,
+		Skips: func(router *routing.Router, route routing.RouteInfo) bool {
+			return skips(router, route) || (middleware.Skips != nil && middleware.Skips(router, route))
+		},
+	}
+}
+
+// MatchPath reports whether the method and the path, relative to the router's base URL, match any pattern
+func (m *Matcher) MatchPath(method string, path string) bool {
+	path = cleanPath(path)
+
+	for _, key := range [2]string{"", method} {
+		if _, ok := m.exact[key][path]; ok {
+			return true
+		}
+		if re := m.paths[key]; re != nil && re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchRoute reports whether the method and the route name match any pattern
+func (m *Matcher) MatchRoute(method string, name string) bool {
+	for _, key := range [2]string{"", method} {
+		if re := m.routes[key]; re != nil && re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitMethods(pattern string) ([]string, string, error) {
+	pattern = strings.TrimSpace(pattern)
+
+	methods, rest, found := strings.Cut(pattern, " ")
+	if !found || strings.ContainsAny(methods, "/:") {
+		return []string{""}, pattern, nil
+	}
+
+	rest = strings.TrimSpace(rest)
+	if rest == "" {
+		return nil, "", fmt.Errorf("filter: invalid pattern %q", pattern)
+	}
+
+	var result []string
+	for _, method := range strings.Split(methods, "|") {
+		if method == "" {
+			return nil, "", fmt.Errorf("filter: invalid pattern %q", pattern)
+		}
+		result = append(result, strings.ToUpper(method))
+	}
+	return result, rest, nil
+}
+
+func globToRegexp(glob string) string {
+	var b strings.Builder
+
+	segments := strings.Split(glob, "/")
+	last := len(segments) - 1
+	afterGlobstar := false
+
+	for i, segment := range segments {
+		if segment == "**" {
+			switch {
+			case i == 0 && i == last:
+				b.WriteString(".*")
+			case i == 0:
+				b.WriteString("(?:.*/)?")
+			case i == last:
+				b.WriteString("(?:/.*)?")
+			default:
+				b.WriteString("/(?:.*/)?")
+			}
+			afterGlobstar = true
+			continue
+		}
+
+		if i > 0 && !afterGlobstar {
+			b.WriteByte('/')
+		}
+		afterGlobstar = false
+
+		for _, r := range segment {
+			switch r {
+			case '*':
+				b.WriteString("[^/]*")
+			case '?':
+				b.WriteString("[^/]")
+			default:
+				b.WriteString(regexp.QuoteMeta(string(r)))
+			}
+		}
+	}
+
+	return b.String()
+}
+
+func nameToRegexp(name string) string {
+	parts := strings.Split(name, "*")
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+	return strings.Join(parts, ".*")
+}
+
+func joinRegexp(exprs []string) string {
+	return "(?:" + strings.Join(exprs, ")|(?:") + ")"
+}
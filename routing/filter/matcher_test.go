@@ -0,0 +1,118 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wolftotem4/golava-core/routing"
+)
+
+func TestMatcherMatchPath(t *testing.T) {
+	m := MustCompile(
+		"/login/",
+		"api/*",
+		"webhooks/**",
+		"**/export.csv",
+		"docs/**/edit",
+		"POST|put forms/submit",
+		`regex:^v[0-9]+/status$`,
+	)
+
+	tests := []struct {
+		method   string
+		path     string
+		expected bool
+	}{
+		{"GET", "login", true},
+		{"GET", "/login/", true},
+		{"GET", "login/x", false},
+		{"GET", "api/users", true},
+		{"GET", "api/users/1", false},
+		{"GET", "api", false},
+		{"GET", "webhooks", true},
+		{"POST", "webhooks/stripe/events", true},
+		{"GET", "webhooksx", false},
+		{"GET", "export.csv", true},
+		{"GET", "reports/2024/export.csv", true},
+		{"GET", "docs/edit", true},
+		{"GET", "docs/a/b/edit", true},
+		{"GET", "docs/a/b/editx", false},
+		{"POST", "forms/submit", true},
+		{"PUT", "forms/submit", true},
+		{"GET", "forms/submit", false},
+		{"GET", "v2/status", true},
+		{"GET", "vx/status", false},
+	}
+
+	for _, tt := range tests {
+		if actual := m.MatchPath(tt.method, tt.path); actual != tt.expected {
+			t.Errorf("%s %s: expected %v but got %v", tt.method, tt.path, tt.expected, actual)
+		}
+	}
+}
+
+func TestMatcherMatchRoute(t *testing.T) {
+	m := MustCompile("route:admin.*", "DELETE route:users.destroy")
+
+	tests := []struct {
+		method   string
+		name     string
+		expected bool
+	}{
+		{"GET", "admin.users.index", true},
+		{"GET", "administrator", false},
+		{"DELETE", "users.destroy", true},
+		{"GET", "users.destroy", false},
+	}
+
+	for _, tt := range tests {
+		if actual := m.MatchRoute(tt.method, tt.name); actual != tt.expected {
+			t.Errorf("%s %s: expected %v but got %v", tt.method, tt.name, tt.expected, actual)
+		}
+	}
+}
+
+func TestCompileInvalidPattern(t *testing.T) {
+	if _, err := Compile("regex:("); err == nil {
+		t.Error("expected an error for an invalid regular expression")
+	}
+}
+
+func verifyCsrfToken(c *gin.Context) {}
+
+func TestListFilteredMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router, err := routing.NewRouter("http://example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := func(c *gin.Context) {}
+	engine := gin.New()
+	routes := router.Routes(&engine.RouterGroup)
+	routes.UseMiddleware(
+		ExceptMiddleware(routing.Middleware{Handler: verifyCsrfToken}, "webhooks/**", "route:api.*"),
+		OnlyMiddleware(routing.Named("Throttle", handler), "POST login"),
+	)
+	routes.POST("/login", handler)
+	routes.POST("/webhooks/stripe", handler)
+	routes.POST("/api/users", handler).Name("api.users.store")
+
+	protected := map[string]bool{}
+	throttled := map[string]bool{}
+	for _, route := range router.List(engine, routing.RouteFilter{}) {
+		protected[route.Path] = route.HasMiddleware("verifyCsrfToken")
+		throttled[route.Path] = route.HasMiddleware("Throttle")
+	}
+
+	expected := map[string]bool{"/login": true, "/webhooks/stripe": false, "/api/users": false}
+	for path, want := range expected {
+		if protected[path] != want {
+			t.Errorf("%s: expected the CSRF middleware listed to be %v", path, want)
+		}
+		if throttled[path] != (path == "/login") {
+			t.Errorf("%s: expected the throttle middleware listed to be %v", path, path == "/login")
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package filter
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wolftotem4/golava-core/routing"
+)
+
+// execute the middleware only on the paths matching the patterns
+// See Matcher for the pattern syntax
+func Only(middleware gin.HandlerFunc, patterns ...string) gin.HandlerFunc {
+	return OnlyMatcher(middleware, MustCompile(patterns...))
+}
+
+func OnlyMatcher(middleware gin.HandlerFunc, matcher *Matcher) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !matcher.Match(c) {
+			c.Next()
+			return
+		}
+
+		middleware(c)
+	}
+}
+
+// OnlyMiddleware is Only for a routing.Middleware, which routing.Router.List
+// only shows on the routes matching the patterns
+func OnlyMiddleware(middleware routing.Middleware, patterns ...string) routing.Middleware {
+	matcher := MustCompile(patterns...)
+
+	return describe(middleware, OnlyMatcher(middleware.Handler, matcher), func(router *routing.Router, route routing.RouteInfo) bool {
+		return !matcher.matchRouteInfo(router, route)
+	})
+}
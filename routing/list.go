@@ -0,0 +1,131 @@
+package routing
+
+import (
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// anyMethod marks the routes registered with RouteGroup.Any
+const anyMethod = "ANY"
+
+// RouteInfo describes a registered route
+type RouteInfo struct {
+	Method     string   `json:"method"`
+	Path       string   `json:"path"`
+	Name       string   `json:"name,omitempty"`
+	Handler    string   `json:"handler"`
+	Middleware []string `json:"middleware"`
+}
+
+// HasMiddleware reports whether the route's middleware chain contains a function,
+// or a Middleware, whose name contains the given name, e.g. "VerifyCsrfToken"
+func (info RouteInfo) HasMiddleware(name string) bool {
+	for _, middleware := range info.Middleware {
+		if strings.Contains(middleware, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware is a handler listed by Router.List under a name of its own, for handlers
+// whose function name says nothing, such as the closures returned by middleware constructors
+type Middleware struct {
+	Name    string
+	Handler gin.HandlerFunc
+
+	// Skips reports whether the handler lets the route through without running,
+	// in which case the route is listed without it
+	Skips func(router *Router, route RouteInfo) bool
+}
+
+// Named describes the handler under the given name, see RouteGroup.UseMiddleware
+func Named(name string, handler gin.HandlerFunc) Middleware {
+	return Middleware{Name: name, Handler: handler}
+}
+
+// RouteFilter narrows the routes returned by Router.List
+// Empty fields match every route
+type RouteFilter struct {
+	// Path prefix, with or without the leading slash
+	Path string
+
+	// Name prefix
+	Name string
+
+	Method string
+}
+
+func (f RouteFilter) match(info RouteInfo) bool {
+	if f.Path != "" && !strings.HasPrefix(strings.TrimPrefix(info.Path, "/"), strings.TrimPrefix(f.Path, "/")) {
+		return false
+	}
+
+	if f.Name != "" && !strings.HasPrefix(info.Name, f.Name) {
+		return false
+	}
+
+	return f.Method == "" || strings.EqualFold(f.Method, info.Method)
+}
+
+// List returns the routes of the engine, sorted by path and method
+// Names and middleware chains are only known for routes registered through Routes
+func (r *Router) List(engine *gin.Engine, filter RouteFilter) []RouteInfo {
+	r.routes.mu.RLock()
+	defer r.routes.mu.RUnlock()
+
+	var routes []RouteInfo
+	for _, route := range engine.Routes() {
+		info := RouteInfo{
+			Method:     route.Method,
+			Path:       route.Path,
+			Handler:    route.Handler,
+			Middleware: []string{},
+		}
+
+		if record := r.findRecord(route.Method, route.Path); record != nil {
+			info.Name = record.name
+			for i, handler := range record.handlers[:len(record.handlers)-1] {
+				middleware, ok := record.middleware[i]
+				if !ok {
+					info.Middleware = append(info.Middleware, FunctionName(handler))
+				} else if middleware.Skips == nil || !middleware.Skips(r, info) {
+					info.Middleware = append(info.Middleware, middleware.Name)
+				}
+			}
+		}
+
+		if filter.match(info) {
+			routes = append(routes, info)
+		}
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+
+	return routes
+}
+
+func (r *Router) findRecord(method string, path string) *routeRecord {
+	// The last registration wins, as in gin
+	for i := len(r.routes.records) - 1; i >= 0; i-- {
+		record := r.routes.records[i]
+		if record.path == path && (record.method == method || record.method == anyMethod) && len(record.handlers) > 0 {
+			return record
+		}
+	}
+	return nil
+}
+
+// FunctionName returns the fully qualified name of the handler function
+func FunctionName(handler gin.HandlerFunc) string {
+	return runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name()
+}
@@ -0,0 +1,104 @@
+package routing
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func csrfMiddleware(c *gin.Context) {}
+
+func TestList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router, err := NewRouter("http://example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := func(c *gin.Context) {}
+	engine := gin.New()
+	routes := router.Routes(&engine.RouterGroup)
+	routes.GET("/", handler).Name("home")
+	admin := routes.Group("/admin", csrfMiddleware)
+	admin.POST("/users", handler).Name("admin.users.store")
+	engine.GET("/health", handler)
+
+	list := router.List(engine, RouteFilter{})
+	if len(list) != 3 {
+		t.Fatalf("expected 3 routes but got %d", len(list))
+	}
+
+	if list[0].Path != "/" || list[0].Name != "home" || len(list[0].Middleware) != 0 {
+		t.Errorf("unexpected route %+v", list[0])
+	}
+
+	if list[1].Path != "/admin/users" || list[1].Name != "admin.users.store" || !list[1].HasMiddleware("csrfMiddleware") {
+		t.Errorf("unexpected route %+v", list[1])
+	}
+
+	if list[2].Path != "/health" || list[2].Name != "" {
+		t.Errorf("unexpected route %+v", list[2])
+	}
+
+	if list := router.List(engine, RouteFilter{Path: "admin"}); len(list) != 1 || list[0].Path != "/admin/users" {
+		t.Errorf("path filter: unexpected routes %+v", list)
+	}
+
+	if list := router.List(engine, RouteFilter{Name: "home"}); len(list) != 1 || list[0].Name != "home" {
+		t.Errorf("name filter: unexpected routes %+v", list)
+	}
+
+	if list := router.List(engine, RouteFilter{Method: "post"}); len(list) != 1 || list[0].Method != "POST" {
+		t.Errorf("method filter: unexpected routes %+v", list)
+	}
+}
+
+func TestListNamedMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router, err := NewRouter("http://example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	constructor := func() gin.HandlerFunc {
+		return func(c *gin.Context) {}
+	}
+
+	handler := func(c *gin.Context) {}
+	engine := gin.New()
+	routes := router.Routes(&engine.RouterGroup)
+	anonymous := routes.Group("/anonymous", constructor())
+	anonymous.GET("", handler)
+
+	named := routes.Group("/named")
+	named.UseMiddleware(Named("StartSession", constructor()))
+	named.Use(csrfMiddleware)
+	named.UseMiddleware(Middleware{
+		Name:    "Throttle",
+		Handler: constructor(),
+		Skips:   func(router *Router, route RouteInfo) bool { return route.Name == "named.skipped" },
+	})
+	named.GET("", handler)
+	named.GET("/skipped", handler).Name("named.skipped")
+
+	list := router.List(engine, RouteFilter{})
+	if len(list) != 3 {
+		t.Fatalf("expected 3 routes but got %d", len(list))
+	}
+
+	if list[0].Path != "/anonymous" || len(list[0].Middleware) != 1 || list[0].HasMiddleware("StartSession") {
+		t.Errorf("unexpected route %+v", list[0])
+	}
+
+	expected := []string{"StartSession", FunctionName(csrfMiddleware), "Throttle"}
+	if list[1].Path != "/named" || !reflect.DeepEqual(list[1].Middleware, expected) {
+		t.Errorf("unexpected route %+v", list[1])
+	}
+
+	if list[2].Path != "/named/skipped" || !reflect.DeepEqual(list[2].Middleware, expected[:2]) {
+		t.Errorf("unexpected route %+v", list[2])
+	}
+}
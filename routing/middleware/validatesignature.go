@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wolftotem4/golava-core/instance"
+)
+
+// ValidateSignature rejects requests whose signed URL has been altered or has expired
+func ValidateSignature(c *gin.Context) {
+	i := instance.MustGetInstance(c)
+
+	err := i.App.Base().Router.ValidateSignature(c.Request.URL)
+	if err != nil {
+		c.AbortWithError(http.StatusForbidden, err)
+		return
+	}
+
+	c.Next()
+}
@@ -26,6 +26,17 @@ func (r *Redirector) Redirect(code int, path string) {
 	r.GIN.Redirect(code, url.String())
 }
 
+// Route performs an HTTP redirect to the named route with the given status code
+// An error is returned when the route is not found or a parameter is missing
+func (r *Redirector) Route(code int, name string, params map[string]any) error {
+	url, err := r.Router.Route(name, params)
+	if err != nil {
+		return err
+	}
+	r.GIN.Redirect(code, url.String())
+	return nil
+}
+
 // Intended redirects to the previously intended URL stored in session, or defaults if none exists
 // After redirecting, the intended URL is removed from the session
 func (r *Redirector) Intended(code int, defaults string) {
@@ -13,6 +13,12 @@ var ErrInvalidURL = errors.New("invalid URL provided")
 // Router provides URL routing functionality with a base URL
 type Router struct {
 	BaseURL *url.URL
+
+	// Key signs URLs generated by SignedURL and TemporarySignedURL, set to App.AppKey by App.Boot
+	// Signed URLs are rejected while it is empty
+	Key []byte
+
+	routes routeRegistry
 }
 
 // NewRouter creates a new Router instance with the given base URL
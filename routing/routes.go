@@ -0,0 +1,272 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	pathLib "path"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	// ErrRouteNotFound is returned when no route is registered under the given name
+	ErrRouteNotFound = errors.New("route not found")
+
+	// ErrMissingParameter is returned when a route parameter has no value
+	ErrMissingParameter = errors.New("missing route parameter")
+)
+
+type routeRegistry struct {
+	mu      sync.RWMutex
+	paths   map[string]string
+	records []*routeRecord
+}
+
+// routeRecord keeps what gin does not expose about a route: its name and handler chain
+type routeRecord struct {
+	method   string
+	path     string
+	name     string
+	handlers gin.HandlersChain
+
+	// middleware described by the route group, by index in handlers
+	middleware map[int]Middleware
+}
+
+// RouteGroup wraps a gin.RouterGroup so that the routes it registers can be named
+type RouteGroup struct {
+	router *Router
+	group  *gin.RouterGroup
+
+	// Middleware added with UseMiddleware, by index in the group's handler chain
+	// Shared with the sub groups, so it is copied before being modified
+	middleware map[int]Middleware
+}
+
+// Route is a registered route, which can be given a name for URL generation
+type Route struct {
+	router *Router
+	record *routeRecord
+	Method string
+	Path   string
+}
+
+// Routes wraps the engine or router group for named route registration
+//
+// Example:
+//
+//	routes := app.Router.Routes(&engine.RouterGroup)
+//	routes.GET("/users/:id", showUser).Name("users.show")
+func (r *Router) Routes(group *gin.RouterGroup) *RouteGroup {
+	return &RouteGroup{router: r, group: group}
+}
+
+// Name registers the path pattern under the given name
+func (r *Router) Name(name string, path string) {
+	r.routes.mu.Lock()
+	defer r.routes.mu.Unlock()
+
+	if r.routes.paths == nil {
+		r.routes.paths = make(map[string]string)
+	}
+	r.routes.paths[name] = path
+}
+
+// HasRoute reports whether a route is registered under the given name
+func (r *Router) HasRoute(name string) bool {
+	_, ok := r.routePath(name)
+	return ok
+}
+
+// RouteName returns the name of the route matching the method and the path pattern,
+// as reported by gin.Context.FullPath, or an empty string if the route is unnamed
+// Only routes registered through Routes are known, since names given by Router.Name carry no method
+func (r *Router) RouteName(method string, path string) string {
+	r.routes.mu.RLock()
+	defer r.routes.mu.RUnlock()
+
+	if record := r.findRecord(method, path); record != nil {
+		return record.name
+	}
+	return ""
+}
+
+// Route constructs the URL of the named route
+// Parameters matching :param and *wildcard segments are substituted, the others are appended as query string
+func (r *Router) Route(name string, params map[string]any) (*url.URL, error) {
+	pattern, ok := r.routePath(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRouteNotFound, name)
+	}
+
+	query := url.Values{}
+	for key, value := range params {
+		query.Set(key, fmt.Sprintf("%v", value))
+	}
+
+	segments := strings.Split(pattern, "/")
+	for i, segment := range segments {
+		if len(segment) < 2 || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+
+		key := segment[1:]
+		if !query.Has(key) {
+			return nil, fmt.Errorf("%w: %s for route %s", ErrMissingParameter, key, name)
+		}
+		value := query.Get(key)
+		query.Del(key)
+
+		if segment[0] == '*' {
+			// Wildcards may span several segments
+			parts := strings.Split(strings.TrimPrefix(value, "/"), "/")
+			for j, part := range parts {
+				parts[j] = url.PathEscape(part)
+			}
+			segments[i] = strings.Join(parts, "/")
+		} else {
+			segments[i] = url.PathEscape(value)
+		}
+	}
+
+	u, err := r.URL(strings.Join(segments, "/"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Copy, since the base URL itself is returned for an empty path
+	result := *u
+	if len(query) > 0 {
+		result.RawQuery = query.Encode()
+	}
+
+	return &result, nil
+}
+
+// RouteMustPanic is a convenience method that returns the route URL or panics on error
+func (r *Router) RouteMustPanic(name string, params map[string]any) *url.URL {
+	u, err := r.Route(name, params)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+func (r *Router) routePath(name string) (string, bool) {
+	r.routes.mu.RLock()
+	defer r.routes.mu.RUnlock()
+
+	path, ok := r.routes.paths[name]
+	return path, ok
+}
+
+// Group creates a sub group, like gin.RouterGroup.Group
+func (g *RouteGroup) Group(relativePath string, handlers ...gin.HandlerFunc) *RouteGroup {
+	return &RouteGroup{router: g.router, group: g.group.Group(relativePath, handlers...), middleware: g.middleware}
+}
+
+// Use adds middleware to the group, like gin.RouterGroup.Use
+func (g *RouteGroup) Use(middleware ...gin.HandlerFunc) *RouteGroup {
+	g.group.Use(middleware...)
+	return g
+}
+
+// UseMiddleware adds middleware to the group, listed by Router.List under their names
+//
+// Example:
+//
+//	routes.UseMiddleware(routing.Named("StartSession", middleware.StartSession(factory)))
+func (g *RouteGroup) UseMiddleware(middleware ...Middleware) *RouteGroup {
+	described := maps.Clone(g.middleware)
+	if described == nil {
+		described = make(map[int]Middleware, len(middleware))
+	}
+
+	for _, m := range middleware {
+		described[len(g.group.Handlers)] = m
+		g.group.Use(m.Handler)
+	}
+
+	g.middleware = described
+	return g
+}
+
+// RouterGroup returns the wrapped gin.RouterGroup
+func (g *RouteGroup) RouterGroup() *gin.RouterGroup {
+	return g.group
+}
+
+func (g *RouteGroup) Handle(method string, relativePath string, handlers ...gin.HandlerFunc) *Route {
+	g.group.Handle(method, relativePath, handlers...)
+	return g.route(method, relativePath, handlers)
+}
+
+func (g *RouteGroup) GET(relativePath string, handlers ...gin.HandlerFunc) *Route {
+	return g.Handle(http.MethodGet, relativePath, handlers...)
+}
+
+func (g *RouteGroup) POST(relativePath string, handlers ...gin.HandlerFunc) *Route {
+	return g.Handle(http.MethodPost, relativePath, handlers...)
+}
+
+func (g *RouteGroup) PUT(relativePath string, handlers ...gin.HandlerFunc) *Route {
+	return g.Handle(http.MethodPut, relativePath, handlers...)
+}
+
+func (g *RouteGroup) PATCH(relativePath string, handlers ...gin.HandlerFunc) *Route {
+	return g.Handle(http.MethodPatch, relativePath, handlers...)
+}
+
+func (g *RouteGroup) DELETE(relativePath string, handlers ...gin.HandlerFunc) *Route {
+	return g.Handle(http.MethodDelete, relativePath, handlers...)
+}
+
+func (g *RouteGroup) OPTIONS(relativePath string, handlers ...gin.HandlerFunc) *Route {
+	return g.Handle(http.MethodOptions, relativePath, handlers...)
+}
+
+func (g *RouteGroup) HEAD(relativePath string, handlers ...gin.HandlerFunc) *Route {
+	return g.Handle(http.MethodHead, relativePath, handlers...)
+}
+
+// Any registers the route for all HTTP methods, like gin.RouterGroup.Any
+func (g *RouteGroup) Any(relativePath string, handlers ...gin.HandlerFunc) *Route {
+	g.group.Any(relativePath, handlers...)
+	return g.route(anyMethod, relativePath, handlers)
+}
+
+func (g *RouteGroup) route(method string, relativePath string, handlers []gin.HandlerFunc) *Route {
+	path := pathLib.Join(g.group.BasePath(), relativePath)
+	// Keep the trailing slash, as gin does
+	if strings.HasSuffix(relativePath, "/") && !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+
+	chain := make(gin.HandlersChain, 0, len(g.group.Handlers)+len(handlers))
+	chain = append(chain, g.group.Handlers...)
+	chain = append(chain, handlers...)
+
+	record := &routeRecord{method: method, path: path, handlers: chain, middleware: g.middleware}
+
+	g.router.routes.mu.Lock()
+	g.router.routes.records = append(g.router.routes.records, record)
+	g.router.routes.mu.Unlock()
+
+	return &Route{router: g.router, record: record, Method: method, Path: path}
+}
+
+// Name registers the route under the given name for Router.Route
+func (rt *Route) Name(name string) *Route {
+	rt.router.Name(name, rt.Path)
+
+	rt.router.routes.mu.Lock()
+	rt.record.name = name
+	rt.router.routes.mu.Unlock()
+
+	return rt
+}
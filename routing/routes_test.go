@@ -0,0 +1,87 @@
+package routing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router, err := NewRouter("http://example.com/admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := func(c *gin.Context) {}
+	engine := gin.New()
+	routes := router.Routes(&engine.RouterGroup)
+	routes.GET("/", handler).Name("home")
+	users := routes.Group("/users")
+	users.GET("/:id", handler).Name("users.show")
+	routes.GET("/files/*path", handler).Name("files")
+
+	tests := []struct {
+		name     string
+		params   map[string]any
+		expected string
+	}{
+		{"home", nil, "http://example.com/admin"},
+		{"users.show", map[string]any{"id": 5}, "http://example.com/admin/users/5"},
+		{"users.show", map[string]any{"id": "a b", "tab": "posts"}, "http://example.com/admin/users/a%20b?tab=posts"},
+		{"files", map[string]any{"path": "docs/read me.txt"}, "http://example.com/admin/files/docs/read%20me.txt"},
+	}
+
+	for _, tt := range tests {
+		u, err := router.Route(tt.name, tt.params)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if u.String() != tt.expected {
+			t.Errorf("expected %s but got %s", tt.expected, u.String())
+		}
+	}
+
+	if _, err := router.Route("users.show", nil); !errors.Is(err, ErrMissingParameter) {
+		t.Errorf("expected ErrMissingParameter but got %v", err)
+	}
+
+	if _, err := router.Route("unknown", nil); !errors.Is(err, ErrRouteNotFound) {
+		t.Errorf("expected ErrRouteNotFound but got %v", err)
+	}
+}
+
+func TestRouteName(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router, err := NewRouter("http://example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := func(c *gin.Context) {}
+	engine := gin.New()
+	routes := router.Routes(&engine.RouterGroup)
+	routes.GET("/users/:id", handler)
+	routes.DELETE("/users/:id", handler).Name("users.destroy")
+	routes.PUT("/users/:id", handler).Name("users.update")
+
+	tests := []struct {
+		method   string
+		expected string
+	}{
+		{"GET", ""},
+		{"DELETE", "users.destroy"},
+		{"PUT", "users.update"},
+		{"POST", ""},
+	}
+
+	for _, test := range tests {
+		if name := router.RouteName(test.method, "/users/:id"); name != test.expected {
+			t.Errorf("%s: expected %q but got %q", test.method, test.expected, name)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package routing
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func newSigningRouter(t *testing.T) *Router {
+	router, err := NewRouter("http://example.com/admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	router.Key = []byte("secret")
+	return router
+}
+
+func TestSignedURL(t *testing.T) {
+	router := newSigningRouter(t)
+
+	u, err := router.SignedURL("unsubscribe", map[string]any{"user": 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if u.Path != "/admin/unsubscribe" || u.Query().Get("user") != "5" || u.Query().Get("signature") == "" {
+		t.Fatalf("unexpected signed URL %s", u)
+	}
+
+	if err := router.ValidateSignature(u); err != nil {
+		t.Errorf("expected valid signature, got %v", err)
+	}
+
+	// A proxy may strip the base path before the request reaches the application
+	stripped := *u
+	stripped.Path = "/unsubscribe"
+	if err := router.ValidateSignature(&stripped); err != nil {
+		t.Errorf("expected valid signature without base path, got %v", err)
+	}
+
+	tampered := *u
+	query := tampered.Query()
+	query.Set("user", "6")
+	tampered.RawQuery = query.Encode()
+	if err := router.ValidateSignature(&tampered); err != ErrInvalidSignature {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+
+	unsigned, _ := url.Parse("http://example.com/admin/unsubscribe?user=5")
+	if err := router.ValidateSignature(unsigned); err != ErrInvalidSignature {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestSignedURLDoesNotModifyBaseURL(t *testing.T) {
+	router := newSigningRouter(t)
+
+	if _, err := router.SignedURL("", map[string]any{"a": "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if router.BaseURL.RawQuery != "" {
+		t.Errorf("expected base URL to be unchanged but got %s", router.BaseURL)
+	}
+}
+
+func TestTemporarySignedURL(t *testing.T) {
+	router := newSigningRouter(t)
+
+	u, err := router.TemporarySignedURL("download", time.Hour, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := router.ValidateSignature(u); err != nil {
+		t.Errorf("expected valid signature, got %v", err)
+	}
+
+	u, err = router.TemporarySignedURL("download", -time.Second, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := router.ValidateSignature(u); err != ErrSignatureExpired {
+		t.Errorf("expected ErrSignatureExpired, got %v", err)
+	}
+}
+
+func TestSignedURLWithoutKey(t *testing.T) {
+	router := newSigningRouter(t)
+
+	u, err := router.SignedURL("unsubscribe", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router.Key = nil
+	if _, err := router.SignedURL("unsubscribe", nil); err != ErrMissingKey {
+		t.Errorf("expected ErrMissingKey, got %v", err)
+	}
+	if err := router.ValidateSignature(u); err != ErrMissingKey {
+		t.Errorf("expected ErrMissingKey, got %v", err)
+	}
+
+	// a signature computed with an empty key must not be accepted either
+	router.Key = []byte{}
+	forged := *u
+	query := forged.Query()
+	query.Del("signature")
+	query.Set("signature", router.signature(forged.Path, query))
+	forged.RawQuery = query.Encode()
+	if err := router.ValidateSignature(&forged); err != ErrMissingKey {
+		t.Errorf("expected ErrMissingKey, got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package session_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wolftotem4/golava-core/cookie"
+	"github.com/wolftotem4/golava-core/encryption"
+	"github.com/wolftotem4/golava-core/session"
+	"github.com/wolftotem4/golava-core/session/sessiontest"
+)
+
+var cookieURL, _ = url.Parse("http://example.com/")
+
+// cookieClient is a browser: every call of the handler is served by gin in a
+// request of its own, carrying the cookies the previous responses set.
+type cookieClient struct {
+	jar       http.CookieJar
+	encrypter encryption.IEncrypter
+}
+
+func newCookieClient(t *testing.T) *cookieClient {
+	gin.SetMode(gin.TestMode)
+
+	key, err := encryption.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &cookieClient{jar: jar, encrypter: encryption.NewEncrypter(key)}
+}
+
+// do runs fn against a cookie handler bound to a real request and response,
+// and returns the response.
+func (c *cookieClient) do(fn func(ctx context.Context, handler session.SessionHandler) error) (*http.Response, error) {
+	factory := &cookie.CookieFactory{
+		Manager: func() cookie.IEncryptableCookieManager {
+			return cookie.NewEncryptableCookieManager(&cookie.CookieManager{Path: "/"}, c.encrypter)
+		},
+	}
+
+	var err error
+	engine := gin.New()
+	engine.GET("/", func(ctx *gin.Context) {
+		handler := &session.CookieSessionHandler{
+			Cookie:     factory.Make(ctx.Request, ctx.Writer),
+			Expiration: time.Hour,
+		}
+		err = fn(ctx.Request.Context(), handler)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, cookieURL.String(), nil)
+	for _, cookie := range c.jar.Cookies(cookieURL) {
+		request.AddCookie(cookie)
+	}
+
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	c.jar.SetCookies(cookieURL, response.Cookies())
+	return response, err
+}
+
+func (c *cookieClient) Read(ctx context.Context, sessionId string) (payload []byte, err error) {
+	_, errDo := c.do(func(ctx context.Context, handler session.SessionHandler) error {
+		payload, err = handler.Read(ctx, sessionId)
+		return err
+	})
+	return payload, errDo
+}
+
+func (c *cookieClient) Write(ctx context.Context, sessionId string, data session.SessionData) error {
+	_, err := c.do(func(ctx context.Context, handler session.SessionHandler) error {
+		return handler.Write(ctx, sessionId, data)
+	})
+	return err
+}
+
+func (c *cookieClient) GC(ctx context.Context, lifetime time.Duration) (count int64, err error) {
+	_, errDo := c.do(func(ctx context.Context, handler session.SessionHandler) error {
+		count, err = handler.GC(ctx, lifetime)
+		return err
+	})
+	return count, errDo
+}
+
+func (c *cookieClient) Destroy(ctx context.Context, sessionId string) error {
+	_, err := c.do(func(ctx context.Context, handler session.SessionHandler) error {
+		return handler.Destroy(ctx, sessionId)
+	})
+	return err
+}
+
+func TestCookieSessionHandlerSuite(t *testing.T) {
+	// the payloads travel encrypted and base64 encoded, binary ones included
+	sessiontest.RunHandlerSuite(t, func(t *testing.T) session.SessionHandler {
+		return newCookieClient(t)
+	}, sessiontest.WithNativeExpiry())
+}
+
+func TestCookieSessionHandlerEncryptsPayload(t *testing.T) {
+	client := newCookieClient(t)
+	id := session.NewSessionId()
+
+	response, err := client.do(func(ctx context.Context, handler session.SessionHandler) error {
+		return handler.Write(ctx, id, session.SessionData{Payload: []byte("secret payload")})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cookies := response.Cookies()
+	if len(cookies) != 1 || cookies[0].Name != id {
+		t.Fatalf("expected the session cookie but got %v", cookies)
+	}
+	if strings.Contains(cookies[0].Value, "secret") || cookies[0].MaxAge != 3600 || !cookies[0].HttpOnly {
+		t.Errorf("unexpected session cookie %s", cookies[0])
+	}
+
+	// a cookie encrypted with another key reads as no session
+	other := newCookieClient(t)
+	other.jar = client.jar
+
+	payload, err := other.Read(context.Background(), id)
+	if err != nil || payload != nil {
+		t.Errorf("expected no payload but got %q, %v", payload, err)
+	}
+}
@@ -0,0 +1,51 @@
+package session
+
+import (
+	"encoding/base64"
+	"errors"
+
+	"github.com/wolftotem4/golava-core/encryption"
+)
+
+// FormatEncrypted marks the payloads encrypted by the Store's Encrypter.
+// The encrypted content is itself a payload, in the format of the Store's Serializer.
+const FormatEncrypted byte = 'e'
+
+// ErrPayloadEncrypted is returned when an encrypted payload is loaded by a Store without an Encrypter.
+var ErrPayloadEncrypted = errors.New("session: payload is encrypted but no encrypter is configured")
+
+func isEncryptedPayload(payload []byte) bool {
+	return len(payload) >= 2 && payload[0] == payloadHeader && payload[1] == FormatEncrypted
+}
+
+// encryptPayload encrypts the payload, base64 encoded for text payload columns.
+func encryptPayload(encrypter encryption.IEncrypter, payload []byte) ([]byte, error) {
+	ciphertext, err := encrypter.Encrypt(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, 2+base64.StdEncoding.EncodedLen(len(ciphertext)))
+	data[0], data[1] = payloadHeader, FormatEncrypted
+	base64.StdEncoding.Encode(data[2:], ciphertext)
+	return data, nil
+}
+
+// decryptPayload decrypts the payload, unencrypted payloads are returned as is.
+func decryptPayload(encrypter encryption.IEncrypter, payload []byte) ([]byte, error) {
+	if !isEncryptedPayload(payload) {
+		return payload, nil
+	}
+
+	if encrypter == nil {
+		return nil, ErrPayloadEncrypted
+	}
+
+	ciphertext := make([]byte, base64.StdEncoding.DecodedLen(len(payload)-2))
+	n, err := base64.StdEncoding.Decode(ciphertext, payload[2:])
+	if err != nil {
+		return nil, err
+	}
+
+	return encrypter.Decrypt(ciphertext[:n])
+}
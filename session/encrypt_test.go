@@ -0,0 +1,52 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/wolftotem4/golava-core/encryption"
+)
+
+func TestEncryptedPayload(t *testing.T) {
+	key, err := encryption.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	legacy, err := GobSerializer{}.Serialize(map[string]any{"_token": "legacy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := &memoryHandler{payload: legacy}
+	factory := &SessionFactory{Handler: handler, Serializer: JSONSerializer{}, Encrypter: encryption.NewEncrypter(key)}
+
+	// unencrypted rows are still read
+	store := factory.Make("id").Store
+	if err := store.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "legacy", store.Token())
+
+	if err := store.Save(context.Background(), ClientData{}); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "$e", string(handler.payload[:2]))
+	if strings.Contains(string(handler.payload), "legacy") {
+		t.Error("expected the payload to be encrypted")
+	}
+
+	store = factory.Make("id").Store
+	if err := store.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "legacy", store.Token())
+
+	factory.Encrypter = nil
+	if err := factory.Make("id").Store.Start(context.Background()); !errors.Is(err, ErrPayloadEncrypted) {
+		t.Errorf("expected ErrPayloadEncrypted but got %v", err)
+	}
+}
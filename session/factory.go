@@ -1,12 +1,26 @@
 package session
 
-import "time"
+import (
+	"time"
+
+	"github.com/wolftotem4/golava-core/encryption"
+)
 
 type SessionFactory struct {
 	Name     string
 	Lifetime time.Duration
 	HttpOnly bool
 	Handler  SessionHandler
+
+	// Serializer encodes the session payloads, defaults to GobSerializer.
+	Serializer Serializer
+
+	// Encrypter encrypts the session payloads at rest when set, typically App.Encryption.
+	Encrypter encryption.IEncrypter
+
+	// LockTimeout enables session blocking when positive and the handler is a LockingSessionHandler:
+	// requests on the same session wait up to LockTimeout for each other, from StartSession to SaveSession.
+	LockTimeout time.Duration
 }
 
 func (sm *SessionFactory) Make(sessionId string) *SessionManager {
@@ -15,11 +29,14 @@ func (sm *SessionFactory) Make(sessionId string) *SessionManager {
 	}
 
 	store := NewStore(sessionId, sm.Handler)
+	store.Serializer = sm.Serializer
+	store.Encrypter = sm.Encrypter
 
 	return &SessionManager{
-		Name:     sm.Name,
-		Store:    store,
-		Lifetime: sm.Lifetime,
-		HttpOnly: sm.HttpOnly,
+		Name:        sm.Name,
+		Store:       store,
+		Lifetime:    sm.Lifetime,
+		HttpOnly:    sm.HttpOnly,
+		LockTimeout: sm.LockTimeout,
 	}
 }
@@ -0,0 +1,204 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/wolftotem4/golava-core/session"
+)
+
+const filePrefix = "sess_"
+
+var ErrInvalidSessionId = errors.New("session: invalid session ID")
+
+// FileSessionHandler stores each session in its own file under Path.
+// Files are replaced atomically, and writers of the same session are serialized with a lock file.
+type FileSessionHandler struct {
+	Path string
+}
+
+type fileRecord struct {
+	UserID    any    `json:"user_id"`
+	IPAddress string `json:"ip_address"`
+	UserAgent string `json:"user_agent"`
+	Payload   []byte `json:"payload"`
+}
+
+func NewFileSessionHandler(path string) *FileSessionHandler {
+	return &FileSessionHandler{
+		Path: path,
+	}
+}
+
+func (h *FileSessionHandler) Read(ctx context.Context, sessionId string) ([]byte, error) {
+	if !validSessionId(sessionId) {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(h.filename(sessionId))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var record fileRecord
+	if err := json.Unmarshal(content, &record); err != nil {
+		return nil, err
+	}
+	return record.Payload, nil
+}
+
+func (h *FileSessionHandler) Write(ctx context.Context, sessionId string, data session.SessionData) error {
+	if !validSessionId(sessionId) {
+		return ErrInvalidSessionId
+	}
+
+	content, err := json.Marshal(fileRecord{
+		UserID:    data.UserID,
+		IPAddress: data.IPAddress,
+		UserAgent: data.UserAgent,
+		Payload:   data.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(h.Path, 0700); err != nil {
+		return err
+	}
+
+	unlock, err := lockFile(h.filename(sessionId) + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	tmp, err := os.CreateTemp(h.Path, filePrefix+sessionId+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), h.filename(sessionId))
+}
+
+// GC removes the sessions not written to since lifetime, along with stale lock and temporary files.
+func (h *FileSessionHandler) GC(ctx context.Context, lifetime time.Duration) (int64, error) {
+	entries, err := os.ReadDir(h.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	before := time.Now().Add(-lifetime)
+
+	var count int64
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, filePrefix) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return count, err
+		}
+
+		if info.ModTime().After(before) {
+			continue
+		}
+
+		// flock does not touch the lock files, keep them while their session lives
+		if sessionFile, ok := strings.CutSuffix(name, ".lock"); ok {
+			if _, err := os.Stat(filepath.Join(h.Path, sessionFile)); err == nil {
+				continue
+			}
+		}
+
+		err = os.Remove(filepath.Join(h.Path, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return count, err
+		}
+
+		if !strings.HasSuffix(name, ".lock") && !strings.HasSuffix(name, ".tmp") {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (h *FileSessionHandler) Destroy(ctx context.Context, sessionId string) error {
+	if !validSessionId(sessionId) {
+		return nil
+	}
+
+	// do not leave a lock file behind for a session that never existed
+	if _, err := os.Stat(h.filename(sessionId)); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	unlock, err := lockFile(h.filename(sessionId) + ".lock")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = os.Remove(h.filename(sessionId))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	// a writer still waiting on the removed lock file goes on with its atomic rename
+	err = os.Remove(h.filename(sessionId) + ".lock")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (h *FileSessionHandler) filename(sessionId string) string {
+	return filepath.Join(h.Path, filePrefix+sessionId)
+}
+
+// validSessionId rejects the IDs that could escape the directory.
+func validSessionId(sessionId string) bool {
+	if sessionId == "" || len(sessionId) > 128 {
+		return false
+	}
+
+	for _, c := range sessionId {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/wolftotem4/golava-core/session"
+	"github.com/wolftotem4/golava-core/session/sessiontest"
+)
+
+func TestFileSessionHandlerSuite(t *testing.T) {
+	sessiontest.RunHandlerSuite(t, func(t *testing.T) session.SessionHandler {
+		return NewFileSessionHandler(t.TempDir())
+	}, sessiontest.WithClientData(func(ctx context.Context, handler session.SessionHandler, sessionId string) (session.ClientData, error) {
+		content, err := os.ReadFile(handler.(*FileSessionHandler).filename(sessionId))
+		if err != nil {
+			return session.ClientData{}, err
+		}
+
+		var record fileRecord
+		err = json.Unmarshal(content, &record)
+		return session.ClientData{UserID: record.UserID, IPAddress: record.IPAddress, UserAgent: record.UserAgent}, err
+	}))
+}
+
+func TestFileSessionHandler(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	handler := NewFileSessionHandler(filepath.Join(dir, "sessions"))
+
+	if err := handler.Write(ctx, "a", session.SessionData{Payload: []byte("payload a")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := handler.Write(ctx, "b", session.SessionData{Payload: []byte("payload b")}); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := handler.Read(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "payload a", string(read))
+
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(handler.filename("a"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(handler.filename("a")+".lock", old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := handler.GC(ctx, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, int64(1), count)
+
+	entries, err := os.ReadDir(handler.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"sess_b", "sess_b.lock"}, names)
+}
+
+func TestFileSessionHandlerInvalidId(t *testing.T) {
+	ctx := context.Background()
+	handler := NewFileSessionHandler(t.TempDir())
+
+	if err := handler.Write(ctx, "../escape", session.SessionData{}); err != ErrInvalidSessionId {
+		t.Errorf("expected ErrInvalidSessionId but got %v", err)
+	}
+
+	read, err := handler.Read(ctx, "../escape")
+	if err != nil || read != nil {
+		t.Errorf("expected no payload but got %q, %v", read, err)
+	}
+}
+
+func TestFileSessionHandlerDestroyLeavesNoFiles(t *testing.T) {
+	ctx := context.Background()
+	handler := NewFileSessionHandler(t.TempDir())
+
+	// destroying a session that does not exist creates nothing
+	if err := handler.Destroy(ctx, "missing"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := handler.Write(ctx, "a", session.SessionData{Payload: []byte("payload a")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := handler.Destroy(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(handler.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string(nil), names)
+}
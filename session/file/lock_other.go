@@ -0,0 +1,15 @@
+//go:build !unix
+
+package file
+
+import "sync"
+
+var locks sync.Map
+
+// lockFile serializes the writers of this process only, file replacement stays atomic across processes.
+func lockFile(name string) (func(), error) {
+	value, _ := locks.LoadOrStore(name, &sync.Mutex{})
+	mu := value.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock, nil
+}
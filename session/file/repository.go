@@ -0,0 +1,123 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wolftotem4/golava-core/session"
+)
+
+// ListByUser reads every session file, it is meant for development rather than large directories.
+func (h *FileSessionHandler) ListByUser(ctx context.Context, userID any) ([]session.SessionInfo, error) {
+	id := session.FormatUserID(userID)
+	if id == "" {
+		return nil, nil
+	}
+
+	var infos []session.SessionInfo
+	err := h.eachSession(func(sessionId string, record fileRecord, modTime time.Time) error {
+		if session.FormatUserID(record.UserID) == id {
+			infos = append(infos, session.SessionInfo{
+				ID: sessionId,
+				ClientData: session.ClientData{
+					UserID:    record.UserID,
+					IPAddress: record.IPAddress,
+					UserAgent: record.UserAgent,
+				},
+				LastActivity: modTime,
+			})
+		}
+		return nil
+	})
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].LastActivity.After(infos[j].LastActivity)
+	})
+
+	return infos, err
+}
+
+func (h *FileSessionHandler) DestroyByUser(ctx context.Context, userID any, exceptID string) (int64, error) {
+	id := session.FormatUserID(userID)
+	if id == "" {
+		return 0, nil
+	}
+
+	var count int64
+	err := h.eachSession(func(sessionId string, record fileRecord, modTime time.Time) error {
+		if sessionId == exceptID || session.FormatUserID(record.UserID) != id {
+			return nil
+		}
+
+		if err := h.Destroy(ctx, sessionId); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+
+	return count, err
+}
+
+// Touch updates the modification time of the session file, which GC relies on.
+func (h *FileSessionHandler) Touch(ctx context.Context, sessionId string) error {
+	if !validSessionId(sessionId) {
+		return nil
+	}
+
+	now := time.Now()
+	err := os.Chtimes(h.filename(sessionId), now, now)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (h *FileSessionHandler) eachSession(fn func(sessionId string, record fileRecord, modTime time.Time) error) error {
+	entries, err := os.ReadDir(h.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		sessionId, ok := strings.CutPrefix(name, filePrefix)
+		if entry.IsDir() || !ok || strings.Contains(sessionId, ".") {
+			continue
+		}
+
+		info, err := entry.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		content, err := os.ReadFile(filepath.Join(h.Path, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		var record fileRecord
+		if err := json.Unmarshal(content, &record); err != nil {
+			continue
+		}
+
+		if err := fn(sessionId, record, info.ModTime()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package session
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+const FormatJSON byte = 'j'
+
+// JSONSerializer encodes the attributes as JSON, readable by services written in other languages.
+type JSONSerializer struct{}
+
+func (JSONSerializer) Format() byte {
+	return FormatJSON
+}
+
+func (JSONSerializer) Serialize(attributes map[string]any) ([]byte, error) {
+	portable, err := toPortable(attributes)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(portable)
+}
+
+func (JSONSerializer) Unserialize(data []byte) (map[string]any, error) {
+	var attributes map[string]any
+
+	// numbers are kept as json.Number until their type is known, so that large integers stay exact
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&attributes); err != nil {
+		return nil, err
+	}
+
+	value, err := fromPortable(attributes, func(value any, target any) error {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(raw, target)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return numbersToFloat(value).(map[string]any), nil
+}
+
+// numbersToFloat converts the remaining json.Number values to float64, as encoding/json does by default.
+func numbersToFloat(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for key, item := range v {
+			v[key] = numbersToFloat(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = numbersToFloat(item)
+		}
+	}
+	return value
+}
@@ -0,0 +1,79 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrLockTimeout is returned when the session lock cannot be acquired in time.
+var ErrLockTimeout = errors.New("session: timed out waiting for the session lock")
+
+// LockingSessionHandler is implemented by the handlers able to lock a session for the duration of a request,
+// so that concurrent requests on the same session do not overwrite each other's changes.
+type LockingSessionHandler interface {
+	SessionHandler
+
+	// Lock waits up to timeout for the lock of the session, and returns the function releasing it.
+	Lock(ctx context.Context, sessionId string, timeout time.Duration) (func() error, error)
+}
+
+// LockMap holds per-key locks within a single process.
+// It does not protect against other processes, e.g. several servers sharing a database.
+type LockMap struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+type keyLock struct {
+	ch   chan struct{}
+	refs int
+}
+
+// Lock waits up to timeout for the lock of the key, and returns the function releasing it.
+func (m *LockMap) Lock(ctx context.Context, key string, timeout time.Duration) (func() error, error) {
+	m.mu.Lock()
+	if m.locks == nil {
+		m.locks = make(map[string]*keyLock)
+	}
+	l, ok := m.locks[key]
+	if !ok {
+		l = &keyLock{ch: make(chan struct{}, 1)}
+		m.locks[key] = l
+	}
+	l.refs++
+	m.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case l.ch <- struct{}{}:
+	case <-timer.C:
+		m.unref(key, l)
+		return nil, ErrLockTimeout
+	case <-ctx.Done():
+		m.unref(key, l)
+		return nil, ctx.Err()
+	}
+
+	var once sync.Once
+	return func() error {
+		once.Do(func() {
+			<-l.ch
+			m.unref(key, l)
+		})
+		return nil
+	}, nil
+}
+
+func (m *LockMap) unref(key string, l *keyLock) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	l.refs--
+	if l.refs == 0 {
+		delete(m.locks, key)
+	}
+}
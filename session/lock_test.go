@@ -0,0 +1,69 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type lockingHandler struct {
+	memoryHandler
+	locks LockMap
+}
+
+func (h *lockingHandler) Lock(ctx context.Context, sessionId string, timeout time.Duration) (func() error, error) {
+	return h.locks.Lock(ctx, sessionId, timeout)
+}
+
+func TestStoreLock(t *testing.T) {
+	ctx := context.Background()
+	handler := &lockingHandler{}
+
+	first := NewStore("id", handler)
+	if err := first.Lock(ctx, time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	second := NewStore("id", handler)
+	if err := second.Lock(ctx, 10*time.Millisecond); !errors.Is(err, ErrLockTimeout) {
+		t.Fatalf("expected ErrLockTimeout but got %v", err)
+	}
+
+	if err := first.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	if err := first.Unlock(); err != nil {
+		t.Errorf("expected a second Unlock to do nothing but got %v", err)
+	}
+
+	if err := second.Lock(ctx, 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	second.Unlock()
+
+	if len(handler.locks.locks) != 0 {
+		t.Errorf("expected the released locks to be removed but got %d", len(handler.locks.locks))
+	}
+
+	// handlers without locking are left alone
+	if err := NewStore("id", &memoryHandler{}).Lock(ctx, time.Second); err != nil {
+		t.Errorf("expected no error but got %v", err)
+	}
+}
+
+func TestLockMapContext(t *testing.T) {
+	var locks LockMap
+
+	unlock, err := locks.Lock(context.Background(), "key", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := locks.Lock(ctx, "key", time.Second); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled but got %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// Queryer runs statements, on a *sql.DB pool or on a single *sql.Conn.
+type Queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// LockedConns holds the connections of the locked sessions, for the SQL handlers whose
+// locks belong to a connection. Running the statements of a locked session on the
+// connection holding its lock means a locked request needs no second connection,
+// which could otherwise never come when SetMaxOpenConns is reached by lock holders.
+// Statements not tied to one session, GC and ListByUser, still take a connection from the pool.
+type LockedConns struct {
+	mu    sync.Mutex
+	conns map[string]*sql.Conn
+}
+
+// Add records the connection holding the lock of the session.
+func (c *LockedConns) Add(sessionId string, conn *sql.Conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conns == nil {
+		c.conns = make(map[string]*sql.Conn)
+	}
+	c.conns[sessionId] = conn
+}
+
+// Remove forgets the connection of the session, before its lock is released.
+func (c *LockedConns) Remove(sessionId string, conn *sql.Conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conns[sessionId] == conn {
+		delete(c.conns, sessionId)
+	}
+}
+
+// Queryer returns the connection holding the lock of the session, or the pool when
+// the session is not locked. A session given a new ID while locked is written through the pool.
+func (c *LockedConns) Queryer(db *sql.DB, sessionId string) Queryer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if conn, ok := c.conns[sessionId]; ok {
+		return conn
+	}
+	return db
+}
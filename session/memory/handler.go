@@ -0,0 +1,150 @@
+package memory
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/wolftotem4/golava-core/session"
+)
+
+const shardCount = 32
+
+type memoryEntry struct {
+	data         session.SessionData
+	lastActivity time.Time
+}
+
+type memoryShard struct {
+	mu      sync.RWMutex
+	entries map[string]memoryEntry
+}
+
+// MemorySessionHandler keeps the sessions of a single process in sharded maps.
+// Sessions expire after Lifetime of inactivity, and are evicted by a janitor goroutine until Close is called.
+type MemorySessionHandler struct {
+	Lifetime time.Duration
+
+	shards [shardCount]*memoryShard
+	locks  session.LockMap
+	stop   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewMemorySessionHandler creates the handler, and starts a janitor evicting the expired sessions
+// every cleanupInterval, unless it is zero.
+func NewMemorySessionHandler(lifetime time.Duration, cleanupInterval time.Duration) *MemorySessionHandler {
+	h := &MemorySessionHandler{
+		Lifetime: lifetime,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for i := range h.shards {
+		h.shards[i] = &memoryShard{entries: make(map[string]memoryEntry)}
+	}
+
+	if cleanupInterval > 0 {
+		go h.janitor(cleanupInterval)
+	} else {
+		close(h.done)
+	}
+
+	return h
+}
+
+func (h *MemorySessionHandler) Read(ctx context.Context, sessionId string) ([]byte, error) {
+	shard := h.shard(sessionId)
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+
+	entry, ok := shard.entries[sessionId]
+	if !ok || h.expired(entry, time.Now()) {
+		return nil, nil
+	}
+
+	return append([]byte(nil), entry.data.Payload...), nil
+}
+
+func (h *MemorySessionHandler) Write(ctx context.Context, sessionId string, data session.SessionData) error {
+	data.Payload = append([]byte(nil), data.Payload...)
+
+	shard := h.shard(sessionId)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	shard.entries[sessionId] = memoryEntry{data: data, lastActivity: time.Now()}
+	return nil
+}
+
+func (h *MemorySessionHandler) GC(ctx context.Context, lifetime time.Duration) (int64, error) {
+	return h.evict(time.Now().Add(-lifetime)), nil
+}
+
+func (h *MemorySessionHandler) Destroy(ctx context.Context, sessionId string) error {
+	shard := h.shard(sessionId)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	delete(shard.entries, sessionId)
+	return nil
+}
+
+// Close stops the janitor and waits for it to return.
+func (h *MemorySessionHandler) Close() error {
+	h.once.Do(func() {
+		close(h.stop)
+	})
+	<-h.done
+	return nil
+}
+
+func (h *MemorySessionHandler) janitor(interval time.Duration) {
+	defer close(h.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if h.Lifetime > 0 {
+				h.evict(time.Now().Add(-h.Lifetime))
+			}
+		case <-h.stop:
+			return
+		}
+	}
+}
+
+// evict removes the sessions inactive since the given time.
+func (h *MemorySessionHandler) evict(before time.Time) int64 {
+	var count int64
+	for _, shard := range h.shards {
+		shard.mu.Lock()
+		for id, entry := range shard.entries {
+			if !entry.lastActivity.After(before) {
+				delete(shard.entries, id)
+				count++
+			}
+		}
+		shard.mu.Unlock()
+	}
+	return count
+}
+
+func (h *MemorySessionHandler) expired(entry memoryEntry, now time.Time) bool {
+	return h.Lifetime > 0 && !entry.lastActivity.Add(h.Lifetime).After(now)
+}
+
+func (h *MemorySessionHandler) shard(sessionId string) *memoryShard {
+	hash := fnv.New32a()
+	hash.Write([]byte(sessionId))
+	return h.shards[hash.Sum32()%shardCount]
+}
+
+// Lock waits up to timeout for the lock of the session, within this process.
+func (h *MemorySessionHandler) Lock(ctx context.Context, sessionId string, timeout time.Duration) (func() error, error) {
+	return h.locks.Lock(ctx, sessionId, timeout)
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/wolftotem4/golava-core/session"
+	"github.com/wolftotem4/golava-core/session/sessiontest"
+)
+
+func TestMemorySessionHandlerSuite(t *testing.T) {
+	sessiontest.RunHandlerSuite(t, func(t *testing.T) session.SessionHandler {
+		handler := NewMemorySessionHandler(time.Hour, time.Minute)
+		t.Cleanup(func() { handler.Close() })
+		return handler
+	}, sessiontest.WithClientData(func(ctx context.Context, handler session.SessionHandler, sessionId string) (session.ClientData, error) {
+		shard := handler.(*MemorySessionHandler).shard(sessionId)
+		shard.mu.RLock()
+		defer shard.mu.RUnlock()
+		return shard.entries[sessionId].data.ClientData, nil
+	}))
+}
+
+func TestMemorySessionHandler(t *testing.T) {
+	ctx := context.Background()
+	handler := NewMemorySessionHandler(50*time.Millisecond, 10*time.Millisecond)
+	defer handler.Close()
+
+	payload := []byte("payload")
+	if err := handler.Write(ctx, "a", session.SessionData{Payload: payload}); err != nil {
+		t.Fatal(err)
+	}
+	payload[0] = 'P'
+
+	read, err := handler.Read(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "payload", string(read))
+
+	time.Sleep(100 * time.Millisecond)
+
+	read, err = handler.Read(ctx, "a")
+	if err != nil || read != nil {
+		t.Errorf("expected the session to expire but got %q, %v", read, err)
+	}
+
+	// evicted by the janitor, so that GC has nothing left to remove
+	count, err := handler.GC(ctx, 0)
+	if err != nil || count != 0 {
+		t.Errorf("expected nothing to collect but got %d, %v", count, err)
+	}
+}
+
+func TestMemorySessionHandlerClose(t *testing.T) {
+	handler := NewMemorySessionHandler(time.Hour, time.Millisecond)
+	handler.Close()
+	handler.Close()
+
+	NewMemorySessionHandler(time.Hour, 0).Close()
+}
@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/wolftotem4/golava-core/session"
+)
+
+func (h *MemorySessionHandler) ListByUser(ctx context.Context, userID any) ([]session.SessionInfo, error) {
+	id := session.FormatUserID(userID)
+	now := time.Now()
+
+	var infos []session.SessionInfo
+	for _, shard := range h.shards {
+		shard.mu.RLock()
+		for sessionId, entry := range shard.entries {
+			if id != "" && session.FormatUserID(entry.data.UserID) == id && !h.expired(entry, now) {
+				infos = append(infos, session.SessionInfo{
+					ID:           sessionId,
+					ClientData:   entry.data.ClientData,
+					LastActivity: entry.lastActivity,
+				})
+			}
+		}
+		shard.mu.RUnlock()
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].LastActivity.After(infos[j].LastActivity)
+	})
+
+	return infos, nil
+}
+
+func (h *MemorySessionHandler) DestroyByUser(ctx context.Context, userID any, exceptID string) (int64, error) {
+	id := session.FormatUserID(userID)
+	if id == "" {
+		return 0, nil
+	}
+
+	var count int64
+	for _, shard := range h.shards {
+		shard.mu.Lock()
+		for sessionId, entry := range shard.entries {
+			if sessionId != exceptID && session.FormatUserID(entry.data.UserID) == id {
+				delete(shard.entries, sessionId)
+				count++
+			}
+		}
+		shard.mu.Unlock()
+	}
+
+	return count, nil
+}
+
+func (h *MemorySessionHandler) Touch(ctx context.Context, sessionId string) error {
+	shard := h.shard(sessionId)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	if entry, ok := shard.entries[sessionId]; ok {
+		entry.lastActivity = time.Now()
+		shard.entries[sessionId] = entry
+	}
+	return nil
+}
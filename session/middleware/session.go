@@ -2,7 +2,9 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"math/rand"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/wolftotem4/golava-core/cookie"
@@ -16,6 +18,14 @@ func StartSession(factory *session.SessionFactory) gin.HandlerFunc {
 		i.Session = factory.Make(session.NewSessionId())
 		i.Redirector.Session = i.Session
 
+		// collected before locking, so that a SQL handler holding the lock needs no second connection
+		err := collectGarbage(c, i.Session)
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
 		var sessionId string
 
 		migrateId, _ := i.Cookie.Encryption().Get(i.Session.GetMigrateName())
@@ -27,17 +37,26 @@ func StartSession(factory *session.SessionFactory) gin.HandlerFunc {
 
 		if sessionId != "" {
 			i.Session.Store.ID = sessionId
-		}
 
-		err := i.Session.Store.Start(c)
-		if err != nil {
-			c.Error(err)
-			c.Abort()
-			return
+			// released by SaveSession once the session is written
+			if i.Session.LockTimeout > 0 {
+				err := i.Session.Store.Lock(c, i.Session.LockTimeout)
+				if errors.Is(err, session.ErrLockTimeout) {
+					dropSession(i)
+					c.AbortWithError(http.StatusLocked, err)
+					return
+				} else if err != nil {
+					dropSession(i)
+					c.Error(err)
+					c.Abort()
+					return
+				}
+			}
 		}
 
-		err = collectGarbage(c, i.Session)
+		err = i.Session.Store.Start(c)
 		if err != nil {
+			dropSession(i)
 			c.Error(err)
 			c.Abort()
 			return
@@ -58,6 +77,14 @@ func StartSession(factory *session.SessionFactory) gin.HandlerFunc {
 	}
 }
 
+// dropSession discards a session that could not be started, so that SaveSession does not write
+// its empty attributes over the stored session.
+func dropSession(i *instance.Instance) {
+	i.Session.Store.Unlock()
+	i.Session = nil
+	i.Redirector.Session = nil
+}
+
 func collectGarbage(ctx context.Context, session *session.SessionManager) error {
 	hitLottery := rand.Intn(100) == 0
 	if hitLottery {
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wolftotem4/golava-core/auth/generic"
+	"github.com/wolftotem4/golava-core/cookie"
+	"github.com/wolftotem4/golava-core/encryption"
+	"github.com/wolftotem4/golava-core/instance"
+	"github.com/wolftotem4/golava-core/routing"
+	"github.com/wolftotem4/golava-core/session"
+	"github.com/wolftotem4/golava-core/session/memory"
+)
+
+func setupLockedSessions(t *testing.T) (*gin.Engine, *memory.MemorySessionHandler) {
+	gin.SetMode(gin.TestMode)
+
+	key, err := encryption.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypter := encryption.NewEncrypter(key)
+
+	handler := memory.NewMemorySessionHandler(time.Hour, 0)
+	t.Cleanup(func() { handler.Close() })
+
+	factory := &session.SessionFactory{
+		Name:        "session",
+		Lifetime:    time.Hour,
+		Handler:     handler,
+		LockTimeout: 20 * time.Millisecond,
+	}
+
+	r := gin.New()
+	r.Use(gin.CustomRecovery(func(c *gin.Context, err any) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	r.Use(func(c *gin.Context) {
+		manager := cookie.NewEncryptableCookieManager(&cookie.CookieManager{Path: "/"}, encrypter)
+		manager.SetRequest(c.Request)
+		manager.SetResponseWriter(c.Writer)
+
+		c.Set("instance", &instance.Instance{
+			Cookie:     manager,
+			Redirector: &routing.Redirector{GIN: c},
+			Auth:       &generic.NullGuard{},
+		})
+		c.Next()
+	})
+	r.Use(SaveSession, StartSession(factory))
+
+	r.GET("/put", func(c *gin.Context) {
+		instance.MustGetInstance(c).Session.Store.Put("name", "value")
+	})
+	r.GET("/get", func(c *gin.Context) {
+		value, _ := instance.MustGetInstance(c).Session.Store.Get("name")
+		c.String(http.StatusOK, "%v", value)
+	})
+	r.GET("/id", func(c *gin.Context) {
+		c.String(http.StatusOK, instance.MustGetInstance(c).Session.Store.ID)
+	})
+	r.GET("/panic", func(c *gin.Context) {
+		panic("handler failed")
+	})
+
+	return r, handler
+}
+
+func serve(r *gin.Engine, path string, cookies []*http.Cookie) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestStartSessionLockTimeout(t *testing.T) {
+	r, handler := setupLockedSessions(t)
+
+	w := serve(r, "/put", nil)
+	cookies := w.Result().Cookies()
+	if len(cookies) == 0 {
+		t.Fatal("expected a session cookie")
+	}
+
+	id := serve(r, "/id", cookies).Body.String()
+
+	unlock, err := handler.Lock(context.Background(), id, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w = serve(r, "/get", cookies)
+	if w.Code != http.StatusLocked {
+		t.Errorf("expected status %d but got %d", http.StatusLocked, w.Code)
+	}
+
+	unlock()
+
+	// the request that timed out must not have overwritten the session
+	w = serve(r, "/get", cookies)
+	if w.Code != http.StatusOK || w.Body.String() != "value" {
+		t.Errorf("expected the session to be kept but got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestSaveSessionUnlocksOnPanic(t *testing.T) {
+	r, handler := setupLockedSessions(t)
+
+	cookies := serve(r, "/put", nil).Result().Cookies()
+	id := serve(r, "/id", cookies).Body.String()
+
+	w := serve(r, "/panic", cookies)
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d but got %d", http.StatusInternalServerError, w.Code)
+	}
+
+	unlock, err := handler.Lock(context.Background(), id, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("expected the session lock to be released but got %v", err)
+	}
+	unlock()
+
+	w = serve(r, "/get", cookies)
+	if w.Code != http.StatusOK || w.Body.String() != "value" {
+		t.Errorf("expected the session to be kept but got %d %q", w.Code, w.Body.String())
+	}
+}
@@ -12,6 +12,18 @@ import (
 func SaveSession(c *gin.Context) {
 	instance := instance.MustGetInstance(c)
 
+	// released even when a handler panics, otherwise the session stays locked
+	defer func() {
+		if instance.Session == nil {
+			return
+		}
+
+		err := instance.Session.Store.Unlock()
+		if err != nil {
+			slog.ErrorContext(c, fmt.Sprintf("Release session lock error %s", err.Error()))
+		}
+	}()
+
 	c.Next()
 
 	if instance.Session != nil {
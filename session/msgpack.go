@@ -0,0 +1,66 @@
+package session
+
+import (
+	"encoding/base64"
+	"reflect"
+
+	"github.com/ugorji/go/codec"
+)
+
+const FormatMsgpack byte = 'm'
+
+// MsgpackSerializer encodes the attributes with MessagePack and base64, for text payload columns.
+type MsgpackSerializer struct{}
+
+func (MsgpackSerializer) Format() byte {
+	return FormatMsgpack
+}
+
+func (MsgpackSerializer) Serialize(attributes map[string]any) ([]byte, error) {
+	portable, err := toPortable(attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw []byte
+	if err := codec.NewEncoderBytes(&raw, msgpackHandle()).Encode(portable); err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, base64.StdEncoding.EncodedLen(len(raw)))
+	base64.StdEncoding.Encode(data, raw)
+	return data, nil
+}
+
+func (MsgpackSerializer) Unserialize(data []byte) (map[string]any, error) {
+	raw := make([]byte, base64.StdEncoding.DecodedLen(len(data)))
+	n, err := base64.StdEncoding.Decode(raw, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var attributes map[string]any
+	if err := codec.NewDecoderBytes(raw[:n], msgpackHandle()).Decode(&attributes); err != nil {
+		return nil, err
+	}
+
+	value, err := fromPortable(attributes, func(value any, target any) error {
+		var buf []byte
+		if err := codec.NewEncoderBytes(&buf, msgpackHandle()).Encode(value); err != nil {
+			return err
+		}
+		return codec.NewDecoderBytes(buf, msgpackHandle()).Decode(target)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return value.(map[string]any), nil
+}
+
+func msgpackHandle() *codec.MsgpackHandle {
+	h := &codec.MsgpackHandle{WriteExt: true}
+	h.MapType = reflect.TypeOf(map[string]any(nil))
+	h.RawToString = true
+	return h
+}
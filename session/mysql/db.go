@@ -13,6 +13,8 @@ import (
 type MySQLSessionHandler struct {
 	DB    *sql.DB
 	Table string
+
+	conns session.LockedConns
 }
 
 func NewMySQLSessionHandler(db *sql.DB, table string) *MySQLSessionHandler {
@@ -23,7 +25,7 @@ func NewMySQLSessionHandler(db *sql.DB, table string) *MySQLSessionHandler {
 }
 
 func (d *MySQLSessionHandler) Read(ctx context.Context, sessionId string) ([]byte, error) {
-	row := d.DB.QueryRowContext(ctx, fmt.Sprintf(
+	row := d.conns.Queryer(d.DB, sessionId).QueryRowContext(ctx, fmt.Sprintf(
 		"SELECT payload FROM `%s` WHERE id = ?", d.Table,
 	), sessionId)
 	if err := row.Err(); err != nil {
@@ -41,7 +43,7 @@ func (d *MySQLSessionHandler) Read(ctx context.Context, sessionId string) ([]byt
 
 func (d *MySQLSessionHandler) Write(ctx context.Context, sessionId string, data session.SessionData) error {
 	now := time.Now().Unix()
-	_, err := d.DB.ExecContext(
+	_, err := d.conns.Queryer(d.DB, sessionId).ExecContext(
 		ctx,
 		fmt.Sprintf(
 			"INSERT INTO `%s` (id, user_id, ip_address, user_agent, payload, last_activity) VALUES (?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE user_id = ?, ip_address = ?, user_agent = ?, payload = ?, last_activity = ?",
@@ -64,7 +66,7 @@ func (d *MySQLSessionHandler) GC(ctx context.Context, lifetime time.Duration) (i
 }
 
 func (d *MySQLSessionHandler) Destroy(ctx context.Context, sessionId string) error {
-	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+	_, err := d.conns.Queryer(d.DB, sessionId).ExecContext(ctx, fmt.Sprintf(
 		"DELETE FROM `%s` WHERE id = ?", d.Table,
 	), sessionId)
 	return err
@@ -0,0 +1,72 @@
+package mysql
+
+import (
+	"context"
+	"crypto/sha1"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"math"
+	"time"
+
+	"github.com/wolftotem4/golava-core/session"
+)
+
+// Lock waits up to timeout for a named lock (GET_LOCK) on the session.
+// GET_LOCK counts in whole seconds, so the timeout is rounded up to the next second.
+// The lock belongs to a connection, which is taken from the pool until the lock is released,
+// and Read, Write, Destroy and Touch of the session run on it meanwhile.
+func (d *MySQLSessionHandler) Lock(ctx context.Context, sessionId string, timeout time.Duration) (func() error, error) {
+	conn, err := d.DB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name := lockName(d.Table, sessionId)
+
+	var acquired sql.NullInt64
+	err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", name, lockSeconds(timeout)).Scan(&acquired)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if acquired.Int64 != 1 {
+		conn.Close()
+		return nil, session.ErrLockTimeout
+	}
+
+	d.conns.Add(sessionId, conn)
+
+	return func() error {
+		d.conns.Remove(sessionId, conn)
+
+		_, err := conn.ExecContext(context.WithoutCancel(ctx), "DO RELEASE_LOCK(?)", name)
+		if err != nil {
+			// a connection still holding the lock must not go back to the pool
+			conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+		return err
+	}, nil
+}
+
+// lockSeconds converts the timeout to the whole seconds GET_LOCK takes, rounding up:
+// a sub-second timeout waits up to a full second rather than not at all.
+// A timeout of zero or less does not wait, where a negative GET_LOCK timeout would wait forever.
+func lockSeconds(timeout time.Duration) int64 {
+	if timeout <= 0 {
+		return 0
+	}
+	return int64(math.Ceil(timeout.Seconds()))
+}
+
+// lockName fits the lock name in the 64 characters allowed by MySQL.
+func lockName(table string, sessionId string) string {
+	name := table + ":" + sessionId
+	if len(name) <= 64 {
+		return name
+	}
+
+	sum := sha1.Sum([]byte(name))
+	return "session:" + hex.EncodeToString(sum[:])
+}
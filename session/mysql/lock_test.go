@@ -0,0 +1,22 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockSeconds(t *testing.T) {
+	cases := map[time.Duration]int64{
+		-time.Second:            0,
+		0:                       0,
+		50 * time.Millisecond:   1,
+		time.Second:             1,
+		1500 * time.Millisecond: 2,
+	}
+
+	for timeout, expected := range cases {
+		if actual := lockSeconds(timeout); actual != expected {
+			t.Errorf("lockSeconds(%s): expected %d, got %d", timeout, expected, actual)
+		}
+	}
+}
@@ -13,6 +13,8 @@ import (
 type PostgresSessionHandler struct {
 	DB    *sql.DB
 	Table string
+
+	conns session.LockedConns
 }
 
 func NewPostgresSessionHandler(db *sql.DB, table string) *PostgresSessionHandler {
@@ -23,7 +25,7 @@ func NewPostgresSessionHandler(db *sql.DB, table string) *PostgresSessionHandler
 }
 
 func (d *PostgresSessionHandler) Read(ctx context.Context, sessionId string) ([]byte, error) {
-	row := d.DB.QueryRowContext(ctx, fmt.Sprintf(
+	row := d.conns.Queryer(d.DB, sessionId).QueryRowContext(ctx, fmt.Sprintf(
 		`SELECT payload FROM "%s" WHERE id = $1`, d.Table,
 	), sessionId)
 	if err := row.Err(); err != nil {
@@ -41,7 +43,7 @@ func (d *PostgresSessionHandler) Read(ctx context.Context, sessionId string) ([]
 
 func (d *PostgresSessionHandler) Write(ctx context.Context, sessionId string, data session.SessionData) error {
 	now := time.Now().Unix()
-	_, err := d.DB.ExecContext(
+	_, err := d.conns.Queryer(d.DB, sessionId).ExecContext(
 		ctx,
 		fmt.Sprintf(
 			`INSERT INTO "%s" (id, user_id, ip_address, user_agent, payload, last_activity) VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (id) DO UPDATE SET user_id = $2, ip_address = $3, user_agent = $4, payload = $5, last_activity = $6;`,
@@ -64,7 +66,7 @@ func (d *PostgresSessionHandler) GC(ctx context.Context, lifetime time.Duration)
 }
 
 func (d *PostgresSessionHandler) Destroy(ctx context.Context, sessionId string) error {
-	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+	_, err := d.conns.Queryer(d.DB, sessionId).ExecContext(ctx, fmt.Sprintf(
 		`DELETE FROM "%s" WHERE id = $1`, d.Table,
 	), sessionId)
 	return err
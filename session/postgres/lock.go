@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"hash/fnv"
+	"time"
+
+	"github.com/wolftotem4/golava-core/session"
+)
+
+// lockRetryInterval is the delay between attempts to take an advisory lock.
+const lockRetryInterval = 20 * time.Millisecond
+
+// Lock waits up to timeout for an advisory lock on the session.
+// The lock belongs to a connection, which is taken from the pool until the lock is released,
+// and Read, Write, Destroy and Touch of the session run on it meanwhile.
+func (d *PostgresSessionHandler) Lock(ctx context.Context, sessionId string, timeout time.Duration) (func() error, error) {
+	conn, err := d.DB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key := lockKey(d.Table, sessionId)
+	deadline := time.Now().Add(timeout)
+
+	for {
+		var acquired bool
+		err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		if acquired {
+			break
+		}
+
+		if !time.Now().Add(lockRetryInterval).Before(deadline) {
+			conn.Close()
+			return nil, session.ErrLockTimeout
+		}
+
+		select {
+		case <-time.After(lockRetryInterval):
+		case <-ctx.Done():
+			conn.Close()
+			return nil, ctx.Err()
+		}
+	}
+
+	d.conns.Add(sessionId, conn)
+
+	return func() error {
+		d.conns.Remove(sessionId, conn)
+
+		_, err := conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", key)
+		if err != nil {
+			// a connection still holding the lock must not go back to the pool
+			conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+		return err
+	}, nil
+}
+
+// lockKey derives the 64-bit key of the advisory lock.
+func lockKey(table string, sessionId string) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(table + ":" + sessionId))
+	return int64(hash.Sum64())
+}
@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wolftotem4/golava-core/session"
+)
+
+var columnSpecs = []session.ColumnSpec{
+	{Name: "id", Types: []string{"character varying", "character", "text"}},
+	{Name: "user_id", Types: []string{"character varying", "character", "text", "bigint", "integer", "uuid"}, Nullable: true},
+	{Name: "ip_address", Types: []string{"character varying", "character", "text", "inet"}},
+	{Name: "user_agent", Types: []string{"text", "character varying"}},
+	{Name: "payload", Types: []string{"text", "bytea"}},
+	{Name: "last_activity", Types: []string{"integer", "bigint"}},
+}
+
+// columnDefinitions are used to add the missing columns, the primary key aside.
+var columnDefinitions = map[string]string{
+	"user_id":       "VARCHAR(255) NULL",
+	"ip_address":    "VARCHAR(45) NULL",
+	"user_agent":    "TEXT NULL",
+	"payload":       "TEXT NOT NULL DEFAULT ''",
+	"last_activity": "BIGINT NOT NULL DEFAULT 0",
+}
+
+var indexedColumns = []string{"user_id", "last_activity"}
+
+// Schema returns the statements creating the session table and its indexes.
+func Schema(table string) []string {
+	return []string{
+		fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS "%s" (`+"\n"+
+				"  id VARCHAR(255) NOT NULL PRIMARY KEY,\n"+
+				"  user_id VARCHAR(255) NULL,\n"+
+				"  ip_address VARCHAR(45) NULL,\n"+
+				"  user_agent TEXT NULL,\n"+
+				"  payload TEXT NOT NULL,\n"+
+				"  last_activity BIGINT NOT NULL\n"+
+				")",
+			table,
+		),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS "%[1]s_user_id_index" ON "%[1]s" (user_id)`, table),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS "%[1]s_last_activity_index" ON "%[1]s" (last_activity)`, table),
+	}
+}
+
+func (d *PostgresSessionHandler) Schema() []string {
+	return Schema(d.Table)
+}
+
+func (d *PostgresSessionHandler) CreateTable(ctx context.Context) error {
+	for _, statement := range d.Schema() {
+		if _, err := d.DB.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *PostgresSessionHandler) Migrate(ctx context.Context) error {
+	columns, err := d.describe(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(columns) == 0 {
+		if err := d.CreateTable(ctx); err != nil {
+			return err
+		}
+		return d.CheckSchema(ctx)
+	}
+
+	for _, spec := range columnSpecs {
+		definition, ok := columnDefinitions[spec.Name]
+		if _, exists := columns[spec.Name]; exists || !ok {
+			continue
+		}
+
+		_, err := d.DB.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE "%s" ADD COLUMN %s %s`, d.Table, spec.Name, definition))
+		if err != nil {
+			return err
+		}
+	}
+
+	indexed, err := d.indexedColumns(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, column := range indexedColumns {
+		if indexed[column] {
+			continue
+		}
+
+		_, err := d.DB.ExecContext(ctx, fmt.Sprintf(`CREATE INDEX "%[1]s_%[2]s_index" ON "%[1]s" (%[2]s)`, d.Table, column))
+		if err != nil {
+			return err
+		}
+	}
+
+	return d.CheckSchema(ctx)
+}
+
+func (d *PostgresSessionHandler) CheckSchema(ctx context.Context) error {
+	columns, err := d.describe(ctx)
+	if err != nil {
+		return err
+	}
+
+	indexed, err := d.indexedColumns(ctx)
+	if err != nil {
+		return err
+	}
+
+	return session.CompareSchema(d.Table, columnSpecs, columns, indexedColumns, indexed)
+}
+
+func (d *PostgresSessionHandler) describe(ctx context.Context) (map[string]session.ColumnInfo, error) {
+	rows, err := d.DB.QueryContext(ctx,
+		"SELECT column_name, data_type, is_nullable FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = $1",
+		d.Table,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make(map[string]session.ColumnInfo)
+	for rows.Next() {
+		var name, dataType, nullable string
+		if err := rows.Scan(&name, &dataType, &nullable); err != nil {
+			return nil, err
+		}
+		columns[name] = session.ColumnInfo{Type: dataType, Nullable: nullable == "YES"}
+	}
+
+	return columns, rows.Err()
+}
+
+// indexedColumns returns the columns leading an index.
+func (d *PostgresSessionHandler) indexedColumns(ctx context.Context) (map[string]bool, error) {
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT a.attname FROM pg_index i
+		JOIN pg_class t ON t.oid = i.indrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = i.indkey[0]
+		WHERE t.relname = $1 AND n.nspname = current_schema()`,
+		d.Table,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	indexed := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		indexed[name] = true
+	}
+
+	return indexed, rows.Err()
+}
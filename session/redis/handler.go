@@ -0,0 +1,199 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/wolftotem4/golava-core/session"
+)
+
+// maxTxAttempts bounds the retries of a transaction aborted by concurrent changes of the session.
+const maxTxAttempts = 16
+
+// RedisSessionHandler stores each session in a hash expiring with the session lifetime,
+// and indexes the session IDs of every user in a set.
+type RedisSessionHandler struct {
+	Client Client
+
+	// Prefix of the keys, defaults to "session:".
+	Prefix string
+
+	// Lifetime of the keys, typically the SessionFactory's Lifetime, defaults to two hours.
+	Lifetime time.Duration
+}
+
+func NewRedisSessionHandler(client Client, prefix string, lifetime time.Duration) *RedisSessionHandler {
+	return &RedisSessionHandler{
+		Client:   client,
+		Prefix:   prefix,
+		Lifetime: lifetime,
+	}
+}
+
+func (h *RedisSessionHandler) Read(ctx context.Context, sessionId string) ([]byte, error) {
+	reply, err := h.Client.Do(ctx, "HGET", h.key(sessionId), "payload")
+	if err != nil || reply == nil {
+		return nil, err
+	}
+
+	payload, ok := reply.([]byte)
+	if !ok {
+		return nil, errProtocol
+	}
+	return payload, nil
+}
+
+func (h *RedisSessionHandler) Write(ctx context.Context, sessionId string, data session.SessionData) error {
+	key := h.key(sessionId)
+	ttl := h.ttl()
+	userID := session.FormatUserID(data.UserID)
+	now := time.Now().Unix()
+
+	return h.watch(ctx, sessionId, func(previous string) [][]any {
+		commands := [][]any{
+			{
+				"HSET", key,
+				"payload", data.Payload,
+				"user_id", userID,
+				"ip_address", data.IPAddress,
+				"user_agent", data.UserAgent,
+				"last_activity", now,
+			},
+			{"EXPIRE", key, ttl},
+		}
+
+		if previous != "" && previous != userID {
+			commands = append(commands, []any{"SREM", h.userKey(previous), sessionId})
+		}
+
+		if userID != "" {
+			commands = append(commands,
+				[]any{"SADD", h.userKey(userID), sessionId},
+				[]any{"EXPIRE", h.userKey(userID), ttl},
+			)
+		}
+
+		return commands
+	})
+}
+
+// GC does nothing, the keys expire on their own.
+func (h *RedisSessionHandler) GC(ctx context.Context, lifetime time.Duration) (int64, error) {
+	return 0, nil
+}
+
+func (h *RedisSessionHandler) Destroy(ctx context.Context, sessionId string) error {
+	_, err := h.destroy(ctx, sessionId, "")
+	return err
+}
+
+// destroy removes the session, unless userID is set and the session belongs to another user.
+func (h *RedisSessionHandler) destroy(ctx context.Context, sessionId string, userID string) (bool, error) {
+	var destroyed bool
+	err := h.watch(ctx, sessionId, func(previous string) [][]any {
+		destroyed = userID == "" || previous == userID
+		if !destroyed {
+			return nil
+		}
+
+		commands := [][]any{{"DEL", h.key(sessionId)}}
+		if previous != "" {
+			commands = append(commands, []any{"SREM", h.userKey(previous), sessionId})
+		}
+		return commands
+	})
+	return destroyed && err == nil, err
+}
+
+// watch reads the user of the session under WATCH, then runs the commands built from it in a transaction,
+// retried when the session changes in between, so that the user index always follows the session.
+func (h *RedisSessionHandler) watch(ctx context.Context, sessionId string, build func(userID string) [][]any) error {
+	for attempt := 0; attempt < maxTxAttempts; attempt++ {
+		err := h.watchOnce(ctx, sessionId, build)
+		if !errors.Is(err, ErrTxAborted) {
+			return err
+		}
+	}
+	return ErrTxAborted
+}
+
+func (h *RedisSessionHandler) watchOnce(ctx context.Context, sessionId string, build func(userID string) [][]any) error {
+	conn, err := h.Client.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	key := h.key(sessionId)
+	if _, err := conn.Do(ctx, "WATCH", key); err != nil {
+		return err
+	}
+
+	userID, err := conn.Do(ctx, "HGET", key, "user_id")
+	if err != nil {
+		return err
+	}
+
+	commands := build(String(userID))
+	if len(commands) == 0 {
+		return nil
+	}
+
+	_, err = conn.Tx(ctx, commands...)
+	return err
+}
+
+// SessionIDsByUser returns the IDs of the live sessions of the user.
+// IDs of expired sessions are removed from the index along the way.
+func (h *RedisSessionHandler) SessionIDsByUser(ctx context.Context, userID any) ([]string, error) {
+	index := h.userKey(session.FormatUserID(userID))
+
+	reply, err := h.Client.Do(ctx, "SMEMBERS", index)
+	if err != nil {
+		return nil, err
+	}
+
+	members, _ := reply.([]any)
+
+	var ids []string
+	for _, member := range members {
+		id := String(member)
+
+		exists, err := h.Client.Do(ctx, "EXISTS", h.key(id))
+		if err != nil {
+			return nil, err
+		}
+
+		if n, _ := exists.(int64); n > 0 {
+			ids = append(ids, id)
+		} else if _, err := h.Client.Do(ctx, "SREM", index, id); err != nil {
+			return nil, err
+		}
+	}
+
+	return ids, nil
+}
+
+func (h *RedisSessionHandler) key(sessionId string) string {
+	return h.prefix() + sessionId
+}
+
+func (h *RedisSessionHandler) userKey(userID string) string {
+	return h.prefix() + "user:" + userID
+}
+
+func (h *RedisSessionHandler) prefix() string {
+	if h.Prefix != "" {
+		return h.Prefix
+	}
+	return "session:"
+}
+
+func (h *RedisSessionHandler) ttl() int64 {
+	lifetime := h.Lifetime
+	if lifetime <= 0 {
+		lifetime = 2 * time.Hour
+	}
+	return max(int64(lifetime/time.Second), 1)
+}
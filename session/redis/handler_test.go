@@ -0,0 +1,229 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/wolftotem4/golava-core/session"
+	"github.com/wolftotem4/golava-core/session/redis/redistest"
+	"github.com/wolftotem4/golava-core/session/sessiontest"
+)
+
+func newTestHandler(t *testing.T) (*RedisSessionHandler, *redistest.Server) {
+	server, err := redistest.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.RequirePass("secret")
+
+	pool := NewPool(server.Addr())
+	pool.Password = "secret"
+	pool.MaxActive = 2
+
+	t.Cleanup(func() {
+		pool.Close()
+		server.Close()
+	})
+
+	return NewRedisSessionHandler(pool, "app:session:", time.Hour), server
+}
+
+func TestRedisSessionHandlerSuite(t *testing.T) {
+	sessiontest.RunHandlerSuite(t, func(t *testing.T) session.SessionHandler {
+		handler, _ := newTestHandler(t)
+		return handler
+	}, sessiontest.WithNativeExpiry(), sessiontest.WithClientData(func(ctx context.Context, handler session.SessionHandler, sessionId string) (session.ClientData, error) {
+		h := handler.(*RedisSessionHandler)
+		reply, err := h.Client.Do(ctx, "HGETALL", h.key(sessionId))
+		if err != nil {
+			return session.ClientData{}, err
+		}
+
+		fields, _ := reply.([]any)
+		hash := make(map[string]string, len(fields)/2)
+		for i := 0; i+1 < len(fields); i += 2 {
+			hash[String(fields[i])] = String(fields[i+1])
+		}
+		return session.ClientData{UserID: hash["user_id"], IPAddress: hash["ip_address"], UserAgent: hash["user_agent"]}, nil
+	}))
+}
+
+func TestRedisSessionHandler(t *testing.T) {
+	ctx := context.Background()
+	handler, server := newTestHandler(t)
+
+	payload, err := handler.Read(ctx, "missing")
+	if err != nil || payload != nil {
+		t.Fatalf("expected no payload but got %q, %v", payload, err)
+	}
+
+	err = handler.Write(ctx, "a", session.SessionData{
+		ClientData: session.ClientData{UserID: 1, IPAddress: "127.0.0.1", UserAgent: "test"},
+		Payload:    []byte("payload a"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = handler.Write(ctx, "b", session.SessionData{ClientData: session.ClientData{UserID: 1}, Payload: []byte("payload b")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err = handler.Read(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "payload a", string(payload))
+	assert.Equal(t, []string{"app:session:a", "app:session:b", "app:session:user:1"}, server.Keys())
+
+	if ttl := server.TTL("app:session:a"); ttl <= 59*time.Minute || ttl > time.Hour {
+		t.Errorf("unexpected TTL %s", ttl)
+	}
+
+	ids, err := handler.SessionIDsByUser(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"a", "b"}, ids)
+
+	// logging out moves the session out of the user's index
+	err = handler.Write(ctx, "b", session.SessionData{Payload: []byte("payload b")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, err = handler.SessionIDsByUser(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"a"}, ids)
+
+	if err := handler.Destroy(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"app:session:b"}, server.Keys())
+
+	server.FastForward(time.Hour)
+	payload, err = handler.Read(ctx, "b")
+	if err != nil || payload != nil {
+		t.Errorf("expected the session to expire but got %q, %v", payload, err)
+	}
+}
+
+func TestPoolErrors(t *testing.T) {
+	ctx := context.Background()
+	handler, _ := newTestHandler(t)
+	pool := handler.Client.(*Pool)
+
+	_, err := pool.Do(ctx, "UNKNOWN")
+	var replyErr Error
+	if !errors.As(err, &replyErr) {
+		t.Errorf("expected an error reply but got %v", err)
+	}
+
+	// the connection is still usable after an error reply
+	reply, err := pool.Do(ctx, "PING")
+	if err != nil || String(reply) != "PONG" {
+		t.Errorf("expected PONG but got %v, %v", reply, err)
+	}
+
+	_, err = pool.Tx(ctx, []any{"SET", "k", "v"}, []any{"UNKNOWN"})
+	if !errors.As(err, &replyErr) {
+		t.Errorf("expected an error reply but got %v", err)
+	}
+	if reply, _ := pool.Do(ctx, "GET", "k"); reply != nil {
+		t.Errorf("expected the transaction to be discarded but got %q", reply)
+	}
+
+	pool.Close()
+	if _, err := pool.Do(ctx, "PING"); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("expected ErrPoolClosed but got %v", err)
+	}
+}
+
+func TestPoolWatch(t *testing.T) {
+	ctx := context.Background()
+	handler, _ := newTestHandler(t)
+	pool := handler.Client
+
+	conn, err := pool.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Do(ctx, "WATCH", "k"); err != nil {
+		t.Fatal(err)
+	}
+
+	// modified by another connection before EXEC
+	if _, err := pool.Do(ctx, "SET", "k", "other"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = conn.Tx(ctx, []any{"SET", "k", "mine"})
+	if !errors.Is(err, ErrTxAborted) {
+		t.Errorf("expected ErrTxAborted but got %v", err)
+	}
+	conn.Close()
+
+	if reply, _ := pool.Do(ctx, "GET", "k"); String(reply) != "other" {
+		t.Errorf("expected the transaction to be aborted but got %q", reply)
+	}
+
+	// keys left watched are unwatched when the connection returns to the pool
+	conn, err = pool.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Do(ctx, "WATCH", "k")
+	conn.Close()
+
+	pool.Do(ctx, "SET", "k", "changed")
+	if _, err := pool.Tx(ctx, []any{"SET", "k", "v"}); err != nil {
+		t.Errorf("expected the transaction to run but got %v", err)
+	}
+}
+
+func TestRedisSessionHandlerConcurrentUsers(t *testing.T) {
+	ctx := context.Background()
+	handler, server := newTestHandler(t)
+
+	// logins and logouts racing on the same session
+	var wg sync.WaitGroup
+	errs := make(chan error, 40)
+	for i := 0; i < 40; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- handler.Write(ctx, "a", session.SessionData{ClientData: session.ClientData{UserID: i % 2}, Payload: []byte("payload")})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	infos, err := handler.ListByUser(ctx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	others, err := handler.ListByUser(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(infos)+len(others) != 1 {
+		t.Errorf("expected the session to be indexed under its user only, keys %v", server.Keys())
+	}
+	for _, info := range append(infos, others...) {
+		if info.ID != "a" {
+			t.Errorf("unexpected session %+v", info)
+		}
+	}
+}
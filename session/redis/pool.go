@@ -0,0 +1,388 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Client sends commands to a Redis server.
+// Pool implements it; other Redis libraries can be adapted to it.
+type Client interface {
+	Do(ctx context.Context, args ...any) (any, error)
+
+	// Tx runs the commands in a MULTI/EXEC transaction and returns their replies.
+	Tx(ctx context.Context, commands ...[]any) ([]any, error)
+
+	// Conn reserves a connection for the commands which must share one, such as WATCH and the transaction it guards.
+	// The connection must be closed once done.
+	Conn(ctx context.Context) (Conn, error)
+}
+
+// Conn is a connection reserved by Client.Conn.
+type Conn interface {
+	Do(ctx context.Context, args ...any) (any, error)
+
+	// Tx runs the commands in a MULTI/EXEC transaction, failing with ErrTxAborted when a watched key was modified.
+	Tx(ctx context.Context, commands ...[]any) ([]any, error)
+
+	Close() error
+}
+
+var ErrPoolClosed = errors.New("redis: pool is closed")
+
+// ErrTxAborted is returned when a key watched with WATCH was modified before EXEC.
+var ErrTxAborted = errors.New("redis: transaction aborted, a watched key was modified")
+
+var errConnClosed = errors.New("redis: connection is closed")
+
+// Pool is a pool of RESP connections.
+type Pool struct {
+	Addr     string
+	Password string
+	DB       int
+
+	// Maximum number of idle connections, defaults to 8.
+	MaxIdle int
+
+	// Maximum number of connections, unlimited when zero.
+	MaxActive int
+
+	// Connections idle for longer are closed instead of reused, defaults to 5 minutes.
+	IdleTimeout time.Duration
+
+	DialTimeout time.Duration
+
+	// Dial overrides the TCP connection to Addr, e.g. for TLS.
+	Dial func(ctx context.Context) (net.Conn, error)
+
+	mu     sync.Mutex
+	idle   []*conn
+	active chan struct{}
+	closed bool
+}
+
+type conn struct {
+	net.Conn
+	r        *bufio.Reader
+	w        *bufio.Writer
+	lastUsed time.Time
+}
+
+func NewPool(addr string) *Pool {
+	return &Pool{Addr: addr}
+}
+
+func (p *Pool) Do(ctx context.Context, args ...any) (any, error) {
+	c, err := p.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := c.do(ctx, args)
+	p.put(c, err)
+	if err != nil {
+		return nil, err
+	}
+
+	if e, ok := reply.(Error); ok {
+		return nil, e
+	}
+	return reply, nil
+}
+
+func (p *Pool) Tx(ctx context.Context, commands ...[]any) ([]any, error) {
+	c, err := p.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	replies, err := c.tx(ctx, commands)
+	p.put(c, err)
+	return replies, err
+}
+
+// Conn reserves a connection of the pool, returned to it by Close.
+func (p *Pool) Conn(ctx context.Context) (Conn, error) {
+	c, err := p.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &poolConn{pool: p, conn: c}, nil
+}
+
+// Close closes the idle connections, connections in use are closed when released.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	for _, c := range p.idle {
+		c.Close()
+	}
+	p.idle = nil
+	return nil
+}
+
+func (p *Pool) get(ctx context.Context) (*conn, error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, ErrPoolClosed
+	}
+	if p.MaxActive > 0 && p.active == nil {
+		p.active = make(chan struct{}, p.MaxActive)
+	}
+	active := p.active
+	p.mu.Unlock()
+
+	if active != nil {
+		select {
+		case active <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	p.mu.Lock()
+	for len(p.idle) > 0 {
+		c := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+		if time.Since(c.lastUsed) < p.idleTimeout() {
+			p.mu.Unlock()
+			return c, nil
+		}
+		c.Close()
+	}
+	p.mu.Unlock()
+
+	c, err := p.dial(ctx)
+	if err != nil {
+		p.release()
+		return nil, err
+	}
+	return c, nil
+}
+
+// put returns the connection to the pool, unless the error left it unusable.
+func (p *Pool) put(c *conn, err error) {
+	defer p.release()
+
+	var replyErr Error
+	if err != nil && !errors.As(err, &replyErr) && !errors.Is(err, ErrTxAborted) {
+		c.Close()
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed || len(p.idle) >= p.maxIdle() {
+		c.Close()
+		return
+	}
+
+	c.lastUsed = time.Now()
+	p.idle = append(p.idle, c)
+}
+
+func (p *Pool) release() {
+	if p.active != nil {
+		<-p.active
+	}
+}
+
+func (p *Pool) dial(ctx context.Context) (*conn, error) {
+	var (
+		netConn net.Conn
+		err     error
+	)
+
+	if p.Dial != nil {
+		netConn, err = p.Dial(ctx)
+	} else {
+		dialer := net.Dialer{Timeout: p.DialTimeout}
+		netConn, err = dialer.DialContext(ctx, "tcp", p.Addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := &conn{Conn: netConn, r: bufio.NewReader(netConn), w: bufio.NewWriter(netConn)}
+
+	if p.Password != "" {
+		if err := c.expectOK(ctx, "AUTH", p.Password); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+
+	if p.DB != 0 {
+		if err := c.expectOK(ctx, "SELECT", p.DB); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+func (p *Pool) maxIdle() int {
+	if p.MaxIdle > 0 {
+		return p.MaxIdle
+	}
+	return 8
+}
+
+func (p *Pool) idleTimeout() time.Duration {
+	if p.IdleTimeout > 0 {
+		return p.IdleTimeout
+	}
+	return 5 * time.Minute
+}
+
+func (c *conn) do(ctx context.Context, args []any) (any, error) {
+	c.setDeadline(ctx)
+
+	if err := writeCommand(c.w, args); err != nil {
+		return nil, err
+	}
+	if err := c.w.Flush(); err != nil {
+		return nil, err
+	}
+
+	return readReply(c.r)
+}
+
+func (c *conn) tx(ctx context.Context, commands [][]any) ([]any, error) {
+	c.setDeadline(ctx)
+
+	// pipeline the whole transaction
+	writeCommand(c.w, []any{"MULTI"})
+	for _, args := range commands {
+		writeCommand(c.w, args)
+	}
+	writeCommand(c.w, []any{"EXEC"})
+	if err := c.w.Flush(); err != nil {
+		return nil, err
+	}
+
+	var queueErr error
+	for i := 0; i < len(commands)+1; i++ {
+		reply, err := readReply(c.r)
+		if err != nil {
+			return nil, err
+		}
+		if e, ok := reply.(Error); ok && queueErr == nil {
+			queueErr = e
+		}
+	}
+
+	reply, err := readReply(c.r)
+	if err != nil {
+		return nil, err
+	}
+	if queueErr != nil {
+		return nil, queueErr
+	}
+
+	switch v := reply.(type) {
+	case nil:
+		return nil, ErrTxAborted
+	case Error:
+		return nil, v
+	case []any:
+		for _, r := range v {
+			if e, ok := r.(Error); ok {
+				return v, e
+			}
+		}
+		return v, nil
+	}
+	return nil, errProtocol
+}
+
+// poolConn is a connection reserved by Pool.Conn.
+type poolConn struct {
+	pool     *Pool
+	conn     *conn
+	err      error
+	watching bool
+	closed   bool
+}
+
+func (pc *poolConn) Do(ctx context.Context, args ...any) (any, error) {
+	if pc.closed {
+		return nil, errConnClosed
+	}
+
+	reply, err := pc.conn.do(ctx, args)
+	if err != nil {
+		pc.err = err
+		return nil, err
+	}
+
+	if len(args) > 0 {
+		switch name, _ := args[0].(string); strings.ToUpper(name) {
+		case "WATCH":
+			pc.watching = true
+		case "UNWATCH", "EXEC", "DISCARD":
+			pc.watching = false
+		}
+	}
+
+	if e, ok := reply.(Error); ok {
+		return nil, e
+	}
+	return reply, nil
+}
+
+func (pc *poolConn) Tx(ctx context.Context, commands ...[]any) ([]any, error) {
+	if pc.closed {
+		return nil, errConnClosed
+	}
+
+	replies, err := pc.conn.tx(ctx, commands)
+	pc.watching = false
+	if err != nil {
+		pc.err = err
+	}
+	return replies, err
+}
+
+// Close returns the connection to the pool, unwatching the keys left watched.
+func (pc *poolConn) Close() error {
+	if pc.closed {
+		return nil
+	}
+	pc.closed = true
+
+	if pc.watching {
+		if _, err := pc.conn.do(context.Background(), []any{"UNWATCH"}); err != nil {
+			pc.err = err
+		}
+	}
+
+	pc.pool.put(pc.conn, pc.err)
+	return nil
+}
+
+func (c *conn) expectOK(ctx context.Context, args ...any) error {
+	reply, err := c.do(ctx, args)
+	if err != nil {
+		return err
+	}
+	if e, ok := reply.(Error); ok {
+		return e
+	}
+	return nil
+}
+
+func (c *conn) setDeadline(ctx context.Context) {
+	deadline, _ := ctx.Deadline()
+	c.SetDeadline(deadline)
+}
@@ -0,0 +1,669 @@
+// Package redistest provides an in-process stand-in for a Redis server,
+// implementing the subset of commands used by the session handlers.
+package redistest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	// []byte, map[string][]byte (hash) or map[string]struct{} (set).
+	value     any
+	expiresAt time.Time
+}
+
+// Server is a RESP server keeping its data in memory.
+type Server struct {
+	listener net.Listener
+	mu       sync.Mutex
+	data     map[string]*entry
+	versions map[string]uint64
+	offset   time.Duration
+	password string
+	conns    map[net.Conn]struct{}
+	wg       sync.WaitGroup
+}
+
+// NewServer starts a server listening on a random local port.
+func NewServer() (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		listener: listener,
+		data:     make(map[string]*entry),
+		versions: make(map[string]uint64),
+		conns:    make(map[net.Conn]struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.serve()
+
+	return s, nil
+}
+
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Close stops the server and closes its connections.
+func (s *Server) Close() error {
+	err := s.listener.Close()
+
+	s.mu.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+// RequirePass makes the connections authenticate with AUTH before sending commands.
+func (s *Server) RequirePass(password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.password = password
+}
+
+// FastForward moves the server's clock, to expire keys without waiting.
+func (s *Server) FastForward(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.offset += d
+}
+
+// Keys returns the live keys, sorted.
+func (s *Server) Keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var keys []string
+	for key := range s.data {
+		if s.lookup(key) != nil {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// TTL returns the remaining time to live of the key, zero if it has none or does not exist.
+func (s *Server) TTL(key string) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := s.lookup(key)
+	if e == nil || e.expiresAt.IsZero() {
+		return 0
+	}
+	return e.expiresAt.Sub(s.now())
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go s.handle(conn)
+	}
+}
+
+type session struct {
+	authenticated bool
+	queue         [][]string
+	multi         bool
+	failed        bool
+
+	// Versions of the keys watched with WATCH.
+	watched map[string]uint64
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	s.mu.Lock()
+	sess := &session{authenticated: s.password == ""}
+	s.mu.Unlock()
+
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+
+		s.dispatch(w, sess, args)
+		if w.Flush() != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) dispatch(w *bufio.Writer, sess *session, args []string) {
+	if len(args) == 0 {
+		writeError(w, "ERR empty command")
+		return
+	}
+
+	name := strings.ToUpper(args[0])
+
+	if name == "AUTH" {
+		s.mu.Lock()
+		password := s.password
+		s.mu.Unlock()
+
+		if len(args) == 2 && args[1] == password {
+			sess.authenticated = true
+			writeSimple(w, "OK")
+		} else {
+			writeError(w, "WRONGPASS invalid password")
+		}
+		return
+	}
+
+	if !sess.authenticated {
+		writeError(w, "NOAUTH Authentication required.")
+		return
+	}
+
+	switch name {
+	case "WATCH":
+		if sess.multi {
+			writeError(w, "ERR WATCH inside MULTI is not allowed")
+			return
+		}
+		if len(args) < 2 {
+			writeError(w, string(errArgs))
+			return
+		}
+		if sess.watched == nil {
+			sess.watched = make(map[string]uint64)
+		}
+		s.mu.Lock()
+		for _, key := range args[1:] {
+			if _, ok := sess.watched[key]; !ok {
+				sess.watched[key] = s.versions[key]
+			}
+		}
+		s.mu.Unlock()
+		writeSimple(w, "OK")
+		return
+	case "UNWATCH":
+		sess.watched = nil
+		writeSimple(w, "OK")
+		return
+	case "MULTI":
+		sess.multi, sess.failed, sess.queue = true, false, nil
+		writeSimple(w, "OK")
+		return
+	case "DISCARD":
+		sess.multi, sess.queue, sess.watched = false, nil, nil
+		writeSimple(w, "OK")
+		return
+	case "EXEC":
+		if !sess.multi {
+			writeError(w, "ERR EXEC without MULTI")
+			return
+		}
+		queue, failed, watched := sess.queue, sess.failed, sess.watched
+		sess.multi, sess.queue, sess.watched = false, nil, nil
+		if failed {
+			writeError(w, "EXECABORT Transaction discarded because of previous errors.")
+			return
+		}
+
+		s.mu.Lock()
+		for key, version := range watched {
+			if s.versions[key] != version {
+				s.mu.Unlock()
+				writeReply(w, nilArray{})
+				return
+			}
+		}
+		replies := make([]any, len(queue))
+		for i, args := range queue {
+			replies[i] = s.execute(args)
+		}
+		s.mu.Unlock()
+
+		writeReply(w, replies)
+		return
+	}
+
+	if sess.multi {
+		if _, ok := commands[name]; !ok {
+			sess.failed = true
+			writeError(w, fmt.Sprintf("ERR unknown command '%s'", args[0]))
+			return
+		}
+		sess.queue = append(sess.queue, args)
+		writeSimple(w, "QUEUED")
+		return
+	}
+
+	s.mu.Lock()
+	reply := s.execute(args)
+	s.mu.Unlock()
+
+	writeReply(w, reply)
+}
+
+type replyError string
+
+// nilArray is the reply of an aborted transaction.
+type nilArray struct{}
+
+type simpleString string
+
+var commands = map[string]func(s *Server, args []string) any{
+	"PING": func(s *Server, args []string) any {
+		return simpleString("PONG")
+	},
+	"SELECT": func(s *Server, args []string) any {
+		return simpleString("OK")
+	},
+	"FLUSHALL": func(s *Server, args []string) any {
+		s.data = make(map[string]*entry)
+		return simpleString("OK")
+	},
+	"GET": func(s *Server, args []string) any {
+		if len(args) != 2 {
+			return errArgs
+		}
+		e := s.lookup(args[1])
+		if e == nil {
+			return nil
+		}
+		value, ok := e.value.([]byte)
+		if !ok {
+			return errWrongType
+		}
+		return value
+	},
+	"SET": func(s *Server, args []string) any {
+		if len(args) < 3 {
+			return errArgs
+		}
+
+		var (
+			expiresAt time.Time
+			nx        bool
+		)
+		for i := 3; i < len(args); i++ {
+			switch strings.ToUpper(args[i]) {
+			case "NX":
+				nx = true
+			case "EX", "PX":
+				if i+1 >= len(args) {
+					return errArgs
+				}
+				n, err := strconv.ParseInt(args[i+1], 10, 64)
+				if err != nil || n <= 0 {
+					return replyError("ERR invalid expire time")
+				}
+				unit := time.Second
+				if strings.ToUpper(args[i]) == "PX" {
+					unit = time.Millisecond
+				}
+				expiresAt = s.now().Add(time.Duration(n) * unit)
+				i++
+			default:
+				return replyError("ERR syntax error")
+			}
+		}
+
+		if nx && s.lookup(args[1]) != nil {
+			return nil
+		}
+
+		s.data[args[1]] = &entry{value: []byte(args[2]), expiresAt: expiresAt}
+		return simpleString("OK")
+	},
+	"DEL": func(s *Server, args []string) any {
+		var n int64
+		for _, key := range args[1:] {
+			if s.lookup(key) != nil {
+				delete(s.data, key)
+				n++
+			}
+		}
+		return n
+	},
+	"EXISTS": func(s *Server, args []string) any {
+		var n int64
+		for _, key := range args[1:] {
+			if s.lookup(key) != nil {
+				n++
+			}
+		}
+		return n
+	},
+	"EXPIRE": func(s *Server, args []string) any {
+		if len(args) != 3 {
+			return errArgs
+		}
+		seconds, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil {
+			return replyError("ERR value is not an integer or out of range")
+		}
+		e := s.lookup(args[1])
+		if e == nil {
+			return int64(0)
+		}
+		if seconds <= 0 {
+			delete(s.data, args[1])
+			return int64(1)
+		}
+		e.expiresAt = s.now().Add(time.Duration(seconds) * time.Second)
+		return int64(1)
+	},
+	"TTL": func(s *Server, args []string) any {
+		if len(args) != 2 {
+			return errArgs
+		}
+		e := s.lookup(args[1])
+		if e == nil {
+			return int64(-2)
+		}
+		if e.expiresAt.IsZero() {
+			return int64(-1)
+		}
+		return int64(e.expiresAt.Sub(s.now()).Round(time.Second) / time.Second)
+	},
+	"HSET": func(s *Server, args []string) any {
+		if len(args) < 4 || len(args)%2 != 0 {
+			return errArgs
+		}
+		hash, err := s.hash(args[1], true)
+		if err != nil {
+			return err
+		}
+		var n int64
+		for i := 2; i < len(args); i += 2 {
+			if _, ok := hash[args[i]]; !ok {
+				n++
+			}
+			hash[args[i]] = []byte(args[i+1])
+		}
+		return n
+	},
+	"HGET": func(s *Server, args []string) any {
+		if len(args) != 3 {
+			return errArgs
+		}
+		hash, err := s.hash(args[1], false)
+		if err != nil {
+			return err
+		}
+		value, ok := hash[args[2]]
+		if !ok {
+			return nil
+		}
+		return value
+	},
+	"HGETALL": func(s *Server, args []string) any {
+		if len(args) != 2 {
+			return errArgs
+		}
+		hash, err := s.hash(args[1], false)
+		if err != nil {
+			return err
+		}
+		fields := make([]string, 0, len(hash))
+		for field := range hash {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		reply := make([]any, 0, 2*len(hash))
+		for _, field := range fields {
+			reply = append(reply, []byte(field), hash[field])
+		}
+		return reply
+	},
+	"SADD": func(s *Server, args []string) any {
+		if len(args) < 3 {
+			return errArgs
+		}
+		set, err := s.set(args[1], true)
+		if err != nil {
+			return err
+		}
+		var n int64
+		for _, member := range args[2:] {
+			if _, ok := set[member]; !ok {
+				set[member] = struct{}{}
+				n++
+			}
+		}
+		return n
+	},
+	"SREM": func(s *Server, args []string) any {
+		if len(args) < 3 {
+			return errArgs
+		}
+		set, err := s.set(args[1], false)
+		if err != nil {
+			return err
+		}
+		var n int64
+		for _, member := range args[2:] {
+			if _, ok := set[member]; ok {
+				delete(set, member)
+				n++
+			}
+		}
+		if len(set) == 0 {
+			delete(s.data, args[1])
+		}
+		return n
+	},
+	"SMEMBERS": func(s *Server, args []string) any {
+		if len(args) != 2 {
+			return errArgs
+		}
+		set, err := s.set(args[1], false)
+		if err != nil {
+			return err
+		}
+		members := make([]string, 0, len(set))
+		for member := range set {
+			members = append(members, member)
+		}
+		sort.Strings(members)
+		reply := make([]any, len(members))
+		for i, member := range members {
+			reply[i] = []byte(member)
+		}
+		return reply
+	},
+}
+
+const (
+	errArgs      = replyError("ERR wrong number of arguments")
+	errWrongType = replyError("WRONGTYPE Operation against a key holding the wrong kind of value")
+)
+
+// writes lists the commands modifying their keys, which abort the transactions watching them.
+var writes = map[string]bool{"SET": true, "DEL": true, "EXPIRE": true, "HSET": true, "SADD": true, "SREM": true}
+
+// execute runs the command, the server's lock must be held.
+func (s *Server) execute(args []string) any {
+	name := strings.ToUpper(args[0])
+	command, ok := commands[name]
+	if !ok {
+		return replyError(fmt.Sprintf("ERR unknown command '%s'", args[0]))
+	}
+
+	if name == "FLUSHALL" {
+		for key := range s.data {
+			s.versions[key]++
+		}
+	} else if writes[name] && len(args) > 1 {
+		keys := args[1:2]
+		if name == "DEL" {
+			keys = args[1:]
+		}
+		for _, key := range keys {
+			s.versions[key]++
+		}
+	}
+
+	return command(s, args)
+}
+
+func (s *Server) now() time.Time {
+	return time.Now().Add(s.offset)
+}
+
+// lookup returns the live entry of the key, removing it if expired.
+func (s *Server) lookup(key string) *entry {
+	e, ok := s.data[key]
+	if !ok {
+		return nil
+	}
+	if !e.expiresAt.IsZero() && !s.now().Before(e.expiresAt) {
+		delete(s.data, key)
+		return nil
+	}
+	return e
+}
+
+func (s *Server) hash(key string, create bool) (map[string][]byte, any) {
+	e := s.lookup(key)
+	if e == nil {
+		if !create {
+			return nil, nil
+		}
+		e = &entry{value: make(map[string][]byte)}
+		s.data[key] = e
+	}
+	hash, ok := e.value.(map[string][]byte)
+	if !ok {
+		return nil, errWrongType
+	}
+	return hash, nil
+}
+
+func (s *Server) set(key string, create bool) (map[string]struct{}, any) {
+	e := s.lookup(key)
+	if e == nil {
+		if !create {
+			return nil, nil
+		}
+		e = &entry{value: make(map[string]struct{})}
+		s.data[key] = e
+	}
+	set, ok := e.value.(map[string]struct{})
+	if !ok {
+		return nil, errWrongType
+	}
+	return set, nil
+}
+
+var errProtocol = errors.New("redistest: invalid command")
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 || line[0] != '*' {
+		return nil, errProtocol
+	}
+
+	n, err := strconv.Atoi(line[1:])
+	if err != nil {
+		return nil, errProtocol
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		line, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(line) == 0 || line[0] != '$' {
+			return nil, errProtocol
+		}
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, errProtocol
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+
+	return args, nil
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"), nil
+}
+
+func writeSimple(w *bufio.Writer, s string) {
+	w.WriteString("+" + s + "\r\n")
+}
+
+func writeError(w *bufio.Writer, s string) {
+	w.WriteString("-" + s + "\r\n")
+}
+
+func writeReply(w *bufio.Writer, reply any) {
+	switch v := reply.(type) {
+	case nil:
+		w.WriteString("$-1\r\n")
+	case nilArray:
+		w.WriteString("*-1\r\n")
+	case simpleString:
+		writeSimple(w, string(v))
+	case replyError:
+		writeError(w, string(v))
+	case int64:
+		w.WriteString(":" + strconv.FormatInt(v, 10) + "\r\n")
+	case []byte:
+		w.WriteString("$" + strconv.Itoa(len(v)) + "\r\n")
+		w.Write(v)
+		w.WriteString("\r\n")
+	case []any:
+		w.WriteString("*" + strconv.Itoa(len(v)) + "\r\n")
+		for _, item := range v {
+			writeReply(w, item)
+		}
+	}
+}
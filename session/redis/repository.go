@@ -0,0 +1,96 @@
+package redis
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/wolftotem4/golava-core/session"
+)
+
+func (h *RedisSessionHandler) ListByUser(ctx context.Context, userID any) ([]session.SessionInfo, error) {
+	ids, err := h.SessionIDsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var infos []session.SessionInfo
+	for _, id := range ids {
+		reply, err := h.Client.Do(ctx, "HGETALL", h.key(id))
+		if err != nil {
+			return nil, err
+		}
+
+		fields, _ := reply.([]any)
+		if len(fields) == 0 {
+			continue
+		}
+
+		hash := make(map[string]string, len(fields)/2)
+		for i := 0; i+1 < len(fields); i += 2 {
+			hash[String(fields[i])] = String(fields[i+1])
+		}
+
+		lastActivity, _ := strconv.ParseInt(hash["last_activity"], 10, 64)
+		infos = append(infos, session.SessionInfo{
+			ID: id,
+			ClientData: session.ClientData{
+				UserID:    hash["user_id"],
+				IPAddress: hash["ip_address"],
+				UserAgent: hash["user_agent"],
+			},
+			LastActivity: time.Unix(lastActivity, 0),
+		})
+	}
+
+	sort.SliceStable(infos, func(i, j int) bool {
+		return infos[i].LastActivity.After(infos[j].LastActivity)
+	})
+
+	return infos, nil
+}
+
+func (h *RedisSessionHandler) DestroyByUser(ctx context.Context, userID any, exceptID string) (int64, error) {
+	ids, err := h.SessionIDsByUser(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	for _, id := range ids {
+		if id == exceptID {
+			continue
+		}
+
+		// the session may have changed hands since it was listed
+		destroyed, err := h.destroy(ctx, id, session.FormatUserID(userID))
+		if err != nil {
+			return count, err
+		}
+		if destroyed {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// Touch records the activity and extends the expiry of the session.
+func (h *RedisSessionHandler) Touch(ctx context.Context, sessionId string) error {
+	key := h.key(sessionId)
+
+	exists, err := h.Client.Do(ctx, "EXISTS", key)
+	if err != nil {
+		return err
+	}
+	if n, _ := exists.(int64); n == 0 {
+		return nil
+	}
+
+	_, err = h.Client.Tx(ctx,
+		[]any{"HSET", key, "last_activity", time.Now().Unix()},
+		[]any{"EXPIRE", key, h.ttl()},
+	)
+	return err
+}
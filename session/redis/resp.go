@@ -0,0 +1,132 @@
+package redis
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Error is an error reply of the server.
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+var errProtocol = errors.New("redis: invalid reply")
+
+// writeCommand writes the command as a RESP array of bulk strings.
+func writeCommand(w *bufio.Writer, args []any) error {
+	w.WriteByte('*')
+	w.WriteString(strconv.Itoa(len(args)))
+	w.WriteString("\r\n")
+
+	for _, arg := range args {
+		var value []byte
+		switch v := arg.(type) {
+		case []byte:
+			value = v
+		case string:
+			value = []byte(v)
+		case int:
+			value = strconv.AppendInt(nil, int64(v), 10)
+		case int64:
+			value = strconv.AppendInt(nil, v, 10)
+		default:
+			value = []byte(fmt.Sprint(v))
+		}
+
+		w.WriteByte('$')
+		w.WriteString(strconv.Itoa(len(value)))
+		w.WriteString("\r\n")
+		w.Write(value)
+		if _, err := w.WriteString("\r\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readReply reads a reply: string for simple strings, int64 for integers, []byte for bulk strings,
+// []any for arrays and Error for error replies. Null replies are nil.
+// The returned error is only set when the connection can no longer be used.
+func readReply(r *bufio.Reader) (any, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, errProtocol
+	}
+
+	switch line[0] {
+	case '+':
+		return string(line[1:]), nil
+	case '-':
+		return Error(line[1:]), nil
+	case ':':
+		n, err := strconv.ParseInt(string(line[1:]), 10, 64)
+		if err != nil {
+			return nil, errProtocol
+		}
+		return n, nil
+	case '$':
+		n, err := strconv.Atoi(string(line[1:]))
+		if err != nil {
+			return nil, errProtocol
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	case '*':
+		n, err := strconv.Atoi(string(line[1:]))
+		if err != nil {
+			return nil, errProtocol
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		values := make([]any, n)
+		for i := range values {
+			values[i], err = readReply(r)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	}
+
+	return nil, errProtocol
+}
+
+func readLine(r *bufio.Reader) ([]byte, error) {
+	line, err := r.ReadSlice('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return nil, errProtocol
+	}
+	return line[:len(line)-2], nil
+}
+
+// String converts a bulk or simple string reply, nil replies are empty strings.
+func String(reply any) string {
+	switch v := reply.(type) {
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	}
+	return ""
+}
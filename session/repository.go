@@ -0,0 +1,64 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// SessionInfo describes a stored session, e.g. for a "your devices" page.
+type SessionInfo struct {
+	ID string
+	ClientData
+	LastActivity time.Time
+}
+
+// SessionRepository is implemented by the handlers able to look up the sessions of a user.
+type SessionRepository interface {
+	SessionHandler
+
+	// ListByUser returns the sessions of the user, the most recently active first.
+	ListByUser(ctx context.Context, userID any) ([]SessionInfo, error)
+
+	// DestroyByUser destroys the sessions of the user but exceptID, and returns how many were destroyed.
+	DestroyByUser(ctx context.Context, userID any, exceptID string) (int64, error)
+
+	// Touch records activity on the session without rewriting its payload.
+	Touch(ctx context.Context, sessionId string) error
+}
+
+// ScanSessionInfos reads rows of id, user_id, ip_address, user_agent and last_activity.
+func ScanSessionInfos(rows *sql.Rows) ([]SessionInfo, error) {
+	var infos []SessionInfo
+	for rows.Next() {
+		var (
+			info                         SessionInfo
+			userID, ipAddress, userAgent sql.NullString
+			lastActivity                 int64
+		)
+
+		if err := rows.Scan(&info.ID, &userID, &ipAddress, &userAgent, &lastActivity); err != nil {
+			return nil, err
+		}
+
+		if userID.Valid {
+			info.UserID = userID.String
+		}
+		info.IPAddress = ipAddress.String
+		info.UserAgent = userAgent.String
+		info.LastActivity = time.Unix(lastActivity, 0)
+
+		infos = append(infos, info)
+	}
+
+	return infos, rows.Err()
+}
+
+// FormatUserID formats the user ID as stored by the handlers keeping it as a string, empty for guests.
+func FormatUserID(userID any) string {
+	if userID == nil {
+		return ""
+	}
+	return fmt.Sprint(userID)
+}
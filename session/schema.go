@@ -0,0 +1,107 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// TableMigrator is implemented by the handlers storing the sessions in a SQL table
+// with the columns id, user_id, ip_address, user_agent, payload and last_activity.
+type TableMigrator interface {
+	// Schema returns the statements creating the table and its indexes.
+	Schema() []string
+
+	// CreateTable creates the table and its indexes, unless they exist.
+	CreateTable(ctx context.Context) error
+
+	// Migrate creates the table, or adds the missing columns and indexes of an existing one,
+	// then checks the schema.
+	Migrate(ctx context.Context) error
+
+	// CheckSchema reports the differences between the table and the expected schema as a *SchemaError.
+	CheckSchema(ctx context.Context) error
+}
+
+// CheckSchema checks the table of the handler, if it has one.
+// Call it at startup to find out about mismatches before the first request fails.
+func CheckSchema(ctx context.Context, handler SessionHandler) error {
+	migrator, ok := handler.(TableMigrator)
+	if !ok {
+		return nil
+	}
+	return migrator.CheckSchema(ctx)
+}
+
+// SchemaError lists the differences between a session table and the expected schema.
+type SchemaError struct {
+	Table    string
+	Problems []string
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("session: table %s does not match the schema: %s", e.Table, strings.Join(e.Problems, "; "))
+}
+
+// ColumnSpec is the expectation on a column.
+type ColumnSpec struct {
+	Name string
+
+	// Types accepted, as reported by the database in lower case.
+	Types []string
+
+	Nullable bool
+}
+
+// ColumnInfo describes an existing column.
+type ColumnInfo struct {
+	Type     string
+	Nullable bool
+}
+
+// CompareSchema compares the existing columns with the expected ones, and checks that the
+// columns to index lead an index. It returns nil when they match, or a *SchemaError.
+func CompareSchema(table string, expected []ColumnSpec, columns map[string]ColumnInfo, indexed []string, indexedColumns map[string]bool) error {
+	if len(columns) == 0 {
+		return &SchemaError{Table: table, Problems: []string{"table does not exist"}}
+	}
+
+	var problems []string
+	for _, spec := range expected {
+		info, ok := columns[spec.Name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("column %s is missing", spec.Name))
+			continue
+		}
+
+		if !matchType(info.Type, spec.Types) {
+			problems = append(problems, fmt.Sprintf("column %s has type %s, expected %s", spec.Name, info.Type, strings.Join(spec.Types, " or ")))
+		}
+
+		if spec.Nullable && !info.Nullable {
+			problems = append(problems, fmt.Sprintf("column %s must be nullable", spec.Name))
+		}
+	}
+
+	for _, column := range indexed {
+		if _, ok := columns[column]; ok && !indexedColumns[column] {
+			problems = append(problems, fmt.Sprintf("column %s is not indexed", column))
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return &SchemaError{Table: table, Problems: problems}
+}
+
+func matchType(actual string, types []string) bool {
+	actual = strings.ToLower(strings.TrimSpace(actual))
+	for _, t := range types {
+		if actual == t || strings.HasPrefix(actual, t+"(") {
+			return true
+		}
+	}
+	return false
+}
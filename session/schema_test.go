@@ -0,0 +1,52 @@
+package session
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+)
+
+func TestCompareSchema(t *testing.T) {
+	specs := []ColumnSpec{
+		{Name: "id", Types: []string{"varchar"}},
+		{Name: "user_id", Types: []string{"varchar", "bigint"}, Nullable: true},
+		{Name: "payload", Types: []string{"text"}},
+		{Name: "last_activity", Types: []string{"bigint"}},
+	}
+	indexed := []string{"user_id", "last_activity"}
+
+	columns := map[string]ColumnInfo{
+		"id":            {Type: "VARCHAR(255)"},
+		"user_id":       {Type: "bigint", Nullable: true},
+		"payload":       {Type: "text"},
+		"last_activity": {Type: "bigint"},
+	}
+	err := CompareSchema("sessions", specs, columns, indexed, map[string]bool{"id": true, "user_id": true, "last_activity": true})
+	if err != nil {
+		t.Errorf("expected the schema to match but got %v", err)
+	}
+
+	columns = map[string]ColumnInfo{
+		"id":            {Type: "varchar"},
+		"user_id":       {Type: "bigint"},
+		"last_activity": {Type: "datetime"},
+	}
+	err = CompareSchema("sessions", specs, columns, indexed, map[string]bool{"user_id": true})
+
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("expected a SchemaError but got %v", err)
+	}
+	assert.Equal(t, []string{
+		"column user_id must be nullable",
+		"column payload is missing",
+		"column last_activity has type datetime, expected bigint",
+		"column last_activity is not indexed",
+	}, schemaErr.Problems)
+
+	err = CompareSchema("sessions", specs, nil, indexed, nil)
+	if !errors.As(err, &schemaErr) || schemaErr.Problems[0] != "table does not exist" {
+		t.Errorf("expected the table to be missing but got %v", err)
+	}
+}
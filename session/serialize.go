@@ -4,18 +4,84 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/gob"
+	"fmt"
+	"time"
 )
 
 func init() {
 	gob.Register(map[string]interface{}{})
 	gob.Register(map[string]string{})
+	gob.Register([]interface{}{})
+	gob.Register(time.Time{})
 }
 
-func marshal(v interface{}) ([]byte, error) {
+// payloadHeader prefixes the payloads of the serializers other than gob, followed by their Format byte.
+// Headerless payloads are base64 encoded gob, the format written before serializers were pluggable.
+const payloadHeader = '$'
+
+// Serializer encodes the session attributes into the payload handed to the SessionHandler.
+type Serializer interface {
+	// Format identifies the payloads written by the serializer, 0 for headerless gob payloads.
+	Format() byte
+	Serialize(attributes map[string]any) ([]byte, error)
+	Unserialize(data []byte) (map[string]any, error)
+}
+
+var serializers = map[byte]Serializer{
+	FormatGob:     GobSerializer{},
+	FormatJSON:    JSONSerializer{},
+	FormatMsgpack: MsgpackSerializer{},
+}
+
+// encodePayload serializes the attributes and prefixes the payload with the format header.
+func encodePayload(serializer Serializer, attributes map[string]any) ([]byte, error) {
+	body, err := serializer.Serialize(attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	if serializer.Format() == FormatGob {
+		return body, nil
+	}
+
+	return append([]byte{payloadHeader, serializer.Format()}, body...), nil
+}
+
+// decodePayload unserializes the payload with the serializer named by its header,
+// so that sessions written in another format keep loading after a switch.
+func decodePayload(serializer Serializer, payload []byte) (map[string]any, error) {
+	format := FormatGob
+	if len(payload) >= 2 && payload[0] == payloadHeader {
+		format = payload[1]
+		payload = payload[2:]
+	}
+
+	if serializer != nil && serializer.Format() == format {
+		return serializer.Unserialize(payload)
+	}
+
+	if s, ok := serializers[format]; ok {
+		return s.Unserialize(payload)
+	}
+
+	return nil, fmt.Errorf("session: unknown payload format %q", format)
+}
+
+const FormatGob byte = 0
+
+// GobSerializer encodes the attributes with gob and base64.
+// Types other than the basic ones must be registered with gob.Register.
+type GobSerializer struct{}
+
+func (GobSerializer) Format() byte {
+	return FormatGob
+}
+
+func (GobSerializer) Serialize(attributes map[string]any) ([]byte, error) {
 	var buf bytes.Buffer
 	var base64Encoder = base64.NewEncoder(base64.StdEncoding, &buf)
 	enc := gob.NewEncoder(base64Encoder)
-	err := enc.Encode(v)
+	err := enc.Encode(attributes)
 	if err != nil {
 		return nil, err
 	}
@@ -23,13 +89,14 @@ func marshal(v interface{}) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-func unmarshal(data []byte, v interface{}) error {
+func (GobSerializer) Unserialize(data []byte) (map[string]any, error) {
+	var attributes map[string]any
 	var base64Decoder = base64.NewDecoder(base64.StdEncoding, bytes.NewReader(data))
 	dec := gob.NewDecoder(base64Decoder)
-	err := dec.Decode(v)
+	err := dec.Decode(&attributes)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return attributes, nil
 }
@@ -0,0 +1,112 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+)
+
+type serializedUser struct {
+	ID   int64
+	Name string
+}
+
+func init() {
+	RegisterType(serializedUser{})
+}
+
+type memoryHandler struct {
+	payload []byte
+}
+
+func (h *memoryHandler) Read(ctx context.Context, sessionId string) ([]byte, error) {
+	return h.payload, nil
+}
+
+func (h *memoryHandler) Write(ctx context.Context, sessionId string, data SessionData) error {
+	h.payload = data.Payload
+	return nil
+}
+
+func (h *memoryHandler) GC(ctx context.Context, lifetime time.Duration) (int64, error) {
+	return 0, nil
+}
+
+func (h *memoryHandler) Destroy(ctx context.Context, sessionId string) error {
+	h.payload = nil
+	return nil
+}
+
+func TestSerializers(t *testing.T) {
+	expiresAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	attributes := map[string]any{
+		"_token":     "token",
+		"user_id":    int64(1) << 60,
+		"count":      3,
+		"ratio":      0.5,
+		"remember":   true,
+		"flash":      []string{"a", "b"},
+		"user":       serializedUser{ID: 7, Name: "John"},
+		"expires_at": expiresAt,
+		"pending": map[string]any{
+			"id":         uint32(5),
+			"expires_at": int64(1700000000),
+			"tags":       []any{"x", 1.5},
+		},
+	}
+
+	for _, serializer := range []Serializer{GobSerializer{}, JSONSerializer{}, MsgpackSerializer{}} {
+		payload, err := encodePayload(serializer, attributes)
+		if err != nil {
+			t.Fatalf("%T: %v", serializer, err)
+		}
+
+		// the payloads are read back without knowing the serializer
+		decoded, err := decodePayload(nil, payload)
+		if err != nil {
+			t.Fatalf("%T: %v", serializer, err)
+		}
+
+		assert.Equal(t, attributes, decoded)
+	}
+}
+
+func TestSerializerUnregisteredType(t *testing.T) {
+	type unregistered struct{}
+
+	_, err := JSONSerializer{}.Serialize(map[string]any{"value": unregistered{}})
+	if err == nil {
+		t.Error("expected an error for an unregistered type")
+	}
+}
+
+func TestStoreReadsLegacyPayload(t *testing.T) {
+	legacy, err := GobSerializer{}.Serialize(map[string]any{"_token": "legacy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := &memoryHandler{payload: legacy}
+	factory := &SessionFactory{Handler: handler, Serializer: JSONSerializer{}}
+
+	store := factory.Make("id").Store
+	if err := store.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "legacy", store.Token())
+
+	store.Flash("status", "saved")
+	if err := store.Save(context.Background(), ClientData{}); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "$j", string(handler.payload[:2]))
+
+	store = factory.Make("id").Store
+	if err := store.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "legacy", store.Token())
+	assert.Equal(t, []string{"status"}, store.getStringSlice("_flash.old"))
+}
@@ -9,6 +9,9 @@ type SessionManager struct {
 	Store    *Store
 	Lifetime time.Duration
 	HttpOnly bool
+
+	// LockTimeout enables session blocking, see SessionFactory.
+	LockTimeout time.Duration
 }
 
 func (sm *SessionManager) GetMigrateName() string {
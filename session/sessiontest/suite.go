@@ -0,0 +1,414 @@
+// Package sessiontest checks that session.SessionHandler implementations behave alike.
+//
+//	func TestHandler(t *testing.T) {
+//		sessiontest.RunHandlerSuite(t, func(t *testing.T) session.SessionHandler {
+//			return NewMyHandler(...)
+//		})
+//	}
+package sessiontest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wolftotem4/golava-core/session"
+)
+
+// HandlerFactory returns a handler holding no session.
+type HandlerFactory func(t *testing.T) session.SessionHandler
+
+// ClientDataReader reads back the client data stored along the session.
+type ClientDataReader func(ctx context.Context, handler session.SessionHandler, sessionId string) (session.ClientData, error)
+
+type Option func(s *suite)
+
+// WithNativeExpiry skips the GC removal checks, for handlers whose sessions expire on their own.
+func WithNativeExpiry() Option {
+	return func(s *suite) {
+		s.nativeExpiry = true
+	}
+}
+
+// WithClientData checks that the client data is persisted, with the reader.
+func WithClientData(reader ClientDataReader) Option {
+	return func(s *suite) {
+		s.clientData = reader
+	}
+}
+
+type suite struct {
+	factory      HandlerFactory
+	nativeExpiry bool
+	clientData   ClientDataReader
+}
+
+// RunHandlerSuite runs the conformance tests against fresh handlers of the factory.
+func RunHandlerSuite(t *testing.T, factory HandlerFactory, options ...Option) {
+	s := &suite{factory: factory}
+	for _, option := range options {
+		option(s)
+	}
+
+	t.Run("ReadMissing", s.testReadMissing)
+	t.Run("WriteAndOverwrite", s.testWriteAndOverwrite)
+	t.Run("ClientData", s.testClientData)
+	t.Run("Destroy", s.testDestroy)
+	t.Run("GC", s.testGC)
+	t.Run("ConcurrentWrites", s.testConcurrentWrites)
+	t.Run("Repository", s.testRepository)
+	t.Run("Lock", s.testLock)
+}
+
+func (s *suite) testReadMissing(t *testing.T) {
+	handler := s.factory(t)
+
+	payload, err := handler.Read(context.Background(), session.NewSessionId())
+	if err != nil {
+		t.Fatalf("Read of a missing session returned an error: %v", err)
+	}
+	if payload != nil {
+		t.Errorf("Read of a missing session returned %q, expected nil", payload)
+	}
+}
+
+func (s *suite) testWriteAndOverwrite(t *testing.T) {
+	ctx := context.Background()
+	handler := s.factory(t)
+	id := session.NewSessionId()
+	other := session.NewSessionId()
+
+	mustWrite(t, handler, id, session.SessionData{Payload: []byte("first")})
+	mustWrite(t, handler, other, session.SessionData{Payload: []byte("other")})
+	expectPayload(t, handler, id, "first")
+
+	mustWrite(t, handler, id, session.SessionData{Payload: []byte("second")})
+	expectPayload(t, handler, id, "second")
+	expectPayload(t, handler, other, "other")
+
+	// payloads are opaque, binary and empty ones included
+	binary := []byte{0, 1, 2, 0xff, '\n'}
+	mustWrite(t, handler, id, session.SessionData{Payload: binary})
+	expectPayload(t, handler, id, string(binary))
+
+	err := handler.Write(ctx, id, session.SessionData{Payload: []byte{}})
+	if err != nil {
+		t.Fatalf("Write of an empty payload failed: %v", err)
+	}
+	payload, err := handler.Read(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payload) != 0 {
+		t.Errorf("expected an empty payload but got %q", payload)
+	}
+}
+
+func (s *suite) testClientData(t *testing.T) {
+	ctx := context.Background()
+	handler := s.factory(t)
+	id := session.NewSessionId()
+
+	data := session.ClientData{UserID: 42, IPAddress: "192.0.2.1", UserAgent: "Mozilla/5.0 (sessiontest)"}
+	mustWrite(t, handler, id, session.SessionData{ClientData: data, Payload: []byte("payload")})
+	expectPayload(t, handler, id, "payload")
+	s.expectClientData(t, ctx, handler, id, data)
+
+	// logging out clears the user
+	data = session.ClientData{IPAddress: "192.0.2.2", UserAgent: "curl/8.0"}
+	mustWrite(t, handler, id, session.SessionData{ClientData: data, Payload: []byte("payload")})
+	s.expectClientData(t, ctx, handler, id, data)
+
+	data = session.ClientData{UserID: "uuid-user", IPAddress: "2001:db8::1"}
+	mustWrite(t, handler, id, session.SessionData{ClientData: data, Payload: []byte("payload")})
+	s.expectClientData(t, ctx, handler, id, data)
+}
+
+func (s *suite) expectClientData(t *testing.T, ctx context.Context, handler session.SessionHandler, id string, expected session.ClientData) {
+	t.Helper()
+
+	if s.clientData == nil {
+		return
+	}
+
+	actual, err := s.clientData(ctx, handler, id)
+	if err != nil {
+		t.Fatalf("reading the client data failed: %v", err)
+	}
+
+	// user IDs may come back as another type, e.g. a string or a float64
+	if session.FormatUserID(actual.UserID) != session.FormatUserID(expected.UserID) ||
+		actual.IPAddress != expected.IPAddress || actual.UserAgent != expected.UserAgent {
+		t.Errorf("expected client data %+v but got %+v", expected, actual)
+	}
+}
+
+func (s *suite) testDestroy(t *testing.T) {
+	ctx := context.Background()
+	handler := s.factory(t)
+	id := session.NewSessionId()
+	other := session.NewSessionId()
+
+	mustWrite(t, handler, id, session.SessionData{Payload: []byte("payload")})
+	mustWrite(t, handler, other, session.SessionData{Payload: []byte("other")})
+
+	if err := handler.Destroy(ctx, id); err != nil {
+		t.Fatalf("Destroy failed: %v", err)
+	}
+	expectMissing(t, handler, id)
+	expectPayload(t, handler, other, "other")
+
+	if err := handler.Destroy(ctx, session.NewSessionId()); err != nil {
+		t.Errorf("Destroy of a missing session returned an error: %v", err)
+	}
+
+	// a destroyed session can be written again
+	mustWrite(t, handler, id, session.SessionData{Payload: []byte("again")})
+	expectPayload(t, handler, id, "again")
+}
+
+func (s *suite) testGC(t *testing.T) {
+	ctx := context.Background()
+	handler := s.factory(t)
+	id := session.NewSessionId()
+
+	mustWrite(t, handler, id, session.SessionData{Payload: []byte("payload")})
+
+	count, err := handler.GC(ctx, time.Hour)
+	if err != nil {
+		t.Fatalf("GC failed: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("GC removed %d active sessions", count)
+	}
+	expectPayload(t, handler, id, "payload")
+
+	if s.nativeExpiry {
+		return
+	}
+
+	// a session inactive for exactly the lifetime is collected
+	count, err = handler.GC(ctx, 0)
+	if err != nil {
+		t.Fatalf("GC failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected GC to remove 1 session but it removed %d", count)
+	}
+	expectMissing(t, handler, id)
+}
+
+func (s *suite) testConcurrentWrites(t *testing.T) {
+	ctx := context.Background()
+	handler := s.factory(t)
+	shared := session.NewSessionId()
+
+	const writers, writes = 8, 10
+
+	ids := make([]string, writers)
+	for i := range ids {
+		ids[i] = session.NewSessionId()
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*writes*2)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < writes; j++ {
+				payload := []byte(fmt.Sprintf("writer-%d-%d", i, j))
+				if err := handler.Write(ctx, shared, session.SessionData{Payload: payload}); err != nil {
+					errs <- err
+				}
+				if err := handler.Write(ctx, ids[i], session.SessionData{Payload: payload}); err != nil {
+					errs <- err
+				}
+				if _, err := handler.Read(ctx, shared); err != nil {
+					errs <- err
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("concurrent access failed: %v", err)
+	}
+
+	for i, id := range ids {
+		expectPayload(t, handler, id, fmt.Sprintf("writer-%d-%d", i, writes-1))
+	}
+
+	// the shared session holds one complete write, never a mix
+	payload, err := handler.Read(ctx, shared)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var matched bool
+	for i := 0; i < writers && !matched; i++ {
+		for j := 0; j < writes; j++ {
+			if string(payload) == fmt.Sprintf("writer-%d-%d", i, j) {
+				matched = true
+				break
+			}
+		}
+	}
+	if !matched {
+		t.Errorf("the shared session holds %q, which no writer wrote", payload)
+	}
+}
+
+// testRepository checks the handlers implementing session.SessionRepository.
+func (s *suite) testRepository(t *testing.T) {
+	ctx := context.Background()
+	handler := s.factory(t)
+
+	repository, ok := handler.(session.SessionRepository)
+	if !ok {
+		t.Skip("the handler does not implement session.SessionRepository")
+	}
+
+	current, other, foreign, guest := session.NewSessionId(), session.NewSessionId(), session.NewSessionId(), session.NewSessionId()
+	mustWrite(t, handler, current, session.SessionData{ClientData: session.ClientData{UserID: 7, IPAddress: "192.0.2.1", UserAgent: "desktop"}, Payload: []byte("current")})
+	mustWrite(t, handler, other, session.SessionData{ClientData: session.ClientData{UserID: 7, IPAddress: "192.0.2.2", UserAgent: "phone"}, Payload: []byte("other")})
+	mustWrite(t, handler, foreign, session.SessionData{ClientData: session.ClientData{UserID: 8}, Payload: []byte("foreign")})
+	mustWrite(t, handler, guest, session.SessionData{Payload: []byte("guest")})
+
+	infos, err := repository.ListByUser(ctx, 7)
+	if err != nil {
+		t.Fatalf("ListByUser failed: %v", err)
+	}
+	if len(infos) != 2 {
+		t.Fatalf("expected 2 sessions but got %+v", infos)
+	}
+	for _, info := range infos {
+		expected := map[string]string{current: "desktop", other: "phone"}[info.ID]
+		if expected == "" || info.UserAgent != expected || session.FormatUserID(info.UserID) != "7" {
+			t.Errorf("unexpected session %+v", info)
+		}
+		if time.Since(info.LastActivity) > time.Minute {
+			t.Errorf("unexpected last activity %s", info.LastActivity)
+		}
+	}
+
+	if err := repository.Touch(ctx, current); err != nil {
+		t.Errorf("Touch failed: %v", err)
+	}
+	expectPayload(t, handler, current, "current")
+
+	if err := repository.Touch(ctx, session.NewSessionId()); err != nil {
+		t.Errorf("Touch of a missing session returned an error: %v", err)
+	}
+	expectMissing(t, handler, session.NewSessionId())
+
+	count, err := repository.DestroyByUser(ctx, 7, current)
+	if err != nil {
+		t.Fatalf("DestroyByUser failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected DestroyByUser to destroy 1 session but it destroyed %d", count)
+	}
+	expectPayload(t, handler, current, "current")
+	expectMissing(t, handler, other)
+	expectPayload(t, handler, foreign, "foreign")
+	expectPayload(t, handler, guest, "guest")
+
+	infos, err = repository.ListByUser(ctx, 7)
+	if err != nil {
+		t.Fatalf("ListByUser failed: %v", err)
+	}
+	if len(infos) != 1 || infos[0].ID != current {
+		t.Errorf("expected only the current session but got %+v", infos)
+	}
+}
+
+// testLock checks the handlers implementing session.LockingSessionHandler.
+func (s *suite) testLock(t *testing.T) {
+	ctx := context.Background()
+	handler := s.factory(t)
+
+	locker, ok := handler.(session.LockingSessionHandler)
+	if !ok {
+		t.Skip("the handler does not implement session.LockingSessionHandler")
+	}
+
+	id, other := session.NewSessionId(), session.NewSessionId()
+
+	unlock, err := locker.Lock(ctx, id, time.Second)
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+
+	// other sessions are not blocked
+	unlockOther, err := locker.Lock(ctx, other, time.Second)
+	if err != nil {
+		t.Fatalf("Lock of another session failed: %v", err)
+	}
+	if err := unlockOther(); err != nil {
+		t.Errorf("releasing the lock failed: %v", err)
+	}
+
+	if _, err := locker.Lock(ctx, id, 50*time.Millisecond); !errors.Is(err, session.ErrLockTimeout) {
+		t.Fatalf("expected ErrLockTimeout while the session is locked but got %v", err)
+	}
+
+	acquired := make(chan error, 1)
+	go func() {
+		unlock, err := locker.Lock(ctx, id, 5*time.Second)
+		if err == nil {
+			err = unlock()
+		}
+		acquired <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	if err := unlock(); err != nil {
+		t.Fatalf("releasing the lock failed: %v", err)
+	}
+
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Errorf("waiting for the lock failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the lock was not handed over once released")
+	}
+}
+
+func mustWrite(t *testing.T, handler session.SessionHandler, id string, data session.SessionData) {
+	t.Helper()
+
+	if err := handler.Write(context.Background(), id, data); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+}
+
+func expectPayload(t *testing.T, handler session.SessionHandler, id string, expected string) {
+	t.Helper()
+
+	payload, err := handler.Read(context.Background(), id)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if string(payload) != expected {
+		t.Errorf("expected payload %q but got %q", expected, payload)
+	}
+}
+
+func expectMissing(t *testing.T, handler session.SessionHandler, id string) {
+	t.Helper()
+
+	payload, err := handler.Read(context.Background(), id)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if payload != nil {
+		t.Errorf("expected no payload but got %q", payload)
+	}
+}
@@ -13,6 +13,8 @@ import (
 type SqliteSessionHandler struct {
 	DB    *sql.DB
 	Table string
+
+	locks session.LockMap
 }
 
 func NewSqliteSessionHandler(db *sql.DB, table string) *SqliteSessionHandler {
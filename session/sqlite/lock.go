@@ -0,0 +1,13 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+)
+
+// Lock waits up to timeout for the lock of the session.
+// SQLite has no row or advisory locks to hold across statements, so the lock is a session.LockMap
+// and only covers this process: separate processes sharing the database file do not wait for each other.
+func (d *SqliteSessionHandler) Lock(ctx context.Context, sessionId string, timeout time.Duration) (func() error, error) {
+	return d.locks.Lock(ctx, sessionId, timeout)
+}
@@ -0,0 +1,162 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wolftotem4/golava-core/session"
+)
+
+// SQLite only keeps the declared types, which decide the type affinity of the columns.
+var columnSpecs = []session.ColumnSpec{
+	{Name: "id", Types: []string{"text", "varchar", "character", "char", "nvarchar"}},
+	{Name: "user_id", Types: []string{"text", "varchar", "character", "char", "nvarchar", "integer", "int", "bigint"}, Nullable: true},
+	{Name: "ip_address", Types: []string{"text", "varchar", "character", "char", "nvarchar"}},
+	{Name: "user_agent", Types: []string{"text", "varchar", "character", "char", "nvarchar", "clob"}},
+	{Name: "payload", Types: []string{"text", "clob", "blob"}},
+	{Name: "last_activity", Types: []string{"integer", "int", "bigint"}},
+}
+
+// columnDefinitions are used to add the missing columns, the primary key aside.
+var columnDefinitions = map[string]string{
+	"user_id":       "TEXT NULL",
+	"ip_address":    "TEXT NULL",
+	"user_agent":    "TEXT NULL",
+	"payload":       "TEXT NOT NULL DEFAULT ''",
+	"last_activity": "INTEGER NOT NULL DEFAULT 0",
+}
+
+var indexedColumns = []string{"user_id", "last_activity"}
+
+// Schema returns the statements creating the session table and its indexes.
+func Schema(table string) []string {
+	return []string{
+		fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS "%s" (`+"\n"+
+				"  id TEXT NOT NULL PRIMARY KEY,\n"+
+				"  user_id TEXT NULL,\n"+
+				"  ip_address TEXT NULL,\n"+
+				"  user_agent TEXT NULL,\n"+
+				"  payload TEXT NOT NULL,\n"+
+				"  last_activity INTEGER NOT NULL\n"+
+				")",
+			table,
+		),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS "%[1]s_user_id_index" ON "%[1]s" (user_id)`, table),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS "%[1]s_last_activity_index" ON "%[1]s" (last_activity)`, table),
+	}
+}
+
+func (d *SqliteSessionHandler) Schema() []string {
+	return Schema(d.Table)
+}
+
+func (d *SqliteSessionHandler) CreateTable(ctx context.Context) error {
+	for _, statement := range d.Schema() {
+		if _, err := d.DB.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *SqliteSessionHandler) Migrate(ctx context.Context) error {
+	columns, err := d.describe(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(columns) == 0 {
+		if err := d.CreateTable(ctx); err != nil {
+			return err
+		}
+		return d.CheckSchema(ctx)
+	}
+
+	for _, spec := range columnSpecs {
+		definition, ok := columnDefinitions[spec.Name]
+		if _, exists := columns[spec.Name]; exists || !ok {
+			continue
+		}
+
+		_, err := d.DB.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE "%s" ADD COLUMN %s %s`, d.Table, spec.Name, definition))
+		if err != nil {
+			return err
+		}
+	}
+
+	indexed, err := d.indexedColumns(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, column := range indexedColumns {
+		if indexed[column] {
+			continue
+		}
+
+		_, err := d.DB.ExecContext(ctx, fmt.Sprintf(`CREATE INDEX "%[1]s_%[2]s_index" ON "%[1]s" (%[2]s)`, d.Table, column))
+		if err != nil {
+			return err
+		}
+	}
+
+	return d.CheckSchema(ctx)
+}
+
+func (d *SqliteSessionHandler) CheckSchema(ctx context.Context) error {
+	columns, err := d.describe(ctx)
+	if err != nil {
+		return err
+	}
+
+	indexed, err := d.indexedColumns(ctx)
+	if err != nil {
+		return err
+	}
+
+	return session.CompareSchema(d.Table, columnSpecs, columns, indexedColumns, indexed)
+}
+
+func (d *SqliteSessionHandler) describe(ctx context.Context) (map[string]session.ColumnInfo, error) {
+	rows, err := d.DB.QueryContext(ctx, "SELECT name, type, \"notnull\" FROM pragma_table_info($1)", d.Table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make(map[string]session.ColumnInfo)
+	for rows.Next() {
+		var name, dataType string
+		var notNull bool
+		if err := rows.Scan(&name, &dataType, &notNull); err != nil {
+			return nil, err
+		}
+		columns[name] = session.ColumnInfo{Type: dataType, Nullable: !notNull}
+	}
+
+	return columns, rows.Err()
+}
+
+// indexedColumns returns the columns leading an index.
+func (d *SqliteSessionHandler) indexedColumns(ctx context.Context) (map[string]bool, error) {
+	rows, err := d.DB.QueryContext(ctx,
+		"SELECT ii.name FROM pragma_index_list($1) AS il, pragma_index_info(il.name) AS ii WHERE ii.seqno = 0",
+		d.Table,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	indexed := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		indexed[name] = true
+	}
+
+	return indexed, rows.Err()
+}
@@ -0,0 +1,126 @@
+package sqlitetest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/wolftotem4/golava-core/session"
+	"github.com/wolftotem4/golava-core/session/sessiontest"
+	"github.com/wolftotem4/golava-core/session/sqlite"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	// a file rather than :memory:, so that every pooled connection sees the same database
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "sessions.db")+"?_busy_timeout=5000&_journal_mode=WAL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestSqliteSessionHandlerSuite(t *testing.T) {
+	sessiontest.RunHandlerSuite(t, func(t *testing.T) session.SessionHandler {
+		handler := sqlite.NewSqliteSessionHandler(openDB(t), "sessions")
+		if err := handler.CreateTable(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		return handler
+	}, sessiontest.WithClientData(func(ctx context.Context, handler session.SessionHandler, sessionId string) (session.ClientData, error) {
+		var userID, ipAddress, userAgent sql.NullString
+		err := handler.(*sqlite.SqliteSessionHandler).DB.QueryRowContext(ctx,
+			`SELECT user_id, ip_address, user_agent FROM "sessions" WHERE id = $1`, sessionId,
+		).Scan(&userID, &ipAddress, &userAgent)
+
+		data := session.ClientData{IPAddress: ipAddress.String, UserAgent: userAgent.String}
+		if userID.Valid {
+			data.UserID = userID.String
+		}
+		return data, err
+	}))
+}
+
+func TestSqliteSessionHandlerMigrate(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	handler := sqlite.NewSqliteSessionHandler(db, "sessions")
+
+	// the table as it was created before client data was stored
+	_, err := db.ExecContext(ctx, `CREATE TABLE "sessions" (id TEXT PRIMARY KEY, payload BLOB NOT NULL, last_activity INTEGER NOT NULL)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := handler.CheckSchema(ctx); err == nil {
+		t.Fatal("expected the outdated table to be reported")
+	}
+
+	if err := handler.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := handler.CheckSchema(ctx); err != nil {
+		t.Fatalf("expected the migrated table to match but got %v", err)
+	}
+
+	// migrating is idempotent
+	if err := handler.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	err = handler.Write(ctx, "id", session.SessionData{ClientData: session.ClientData{UserID: 5}, Payload: []byte("payload")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := handler.ListByUser(ctx, 5)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(sessions) != 1 || sessions[0].ID != "id" {
+		t.Errorf("expected the written session to be listed but got %s", fmt.Sprint(sessions))
+	}
+}
+
+func TestLockedConnsRunOnTheLockedConnection(t *testing.T) {
+	db := openDB(t)
+	db.SetMaxOpenConns(1)
+
+	handler := sqlite.NewSqliteSessionHandler(db, "sessions")
+	if err := handler.CreateTable(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the only connection of the pool is held, as by a lock
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var conns session.LockedConns
+	conns.Add("locked", conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var count int
+	err = conns.Queryer(db, "locked").QueryRowContext(ctx, `SELECT COUNT(*) FROM "sessions"`).Scan(&count)
+	if err != nil {
+		t.Fatalf("the locked session waited for the pool: %v", err)
+	}
+
+	// another connection's lock of the session does not release this one
+	conns.Remove("locked", nil)
+	if conns.Queryer(db, "locked") != conn {
+		t.Fatal("expected the locked connection to be kept")
+	}
+
+	conns.Remove("locked", conn)
+	if conns.Queryer(db, "locked") != db {
+		t.Fatal("expected the pool once the lock is released")
+	}
+}
@@ -0,0 +1,5 @@
+// Package sqlitetest runs the sqlite session handler against a real database.
+//
+// It is a module of its own so that the cgo sqlite driver it needs stays out
+// of the golava-core module.
+package sqlitetest
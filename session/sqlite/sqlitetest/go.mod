@@ -0,0 +1,15 @@
+module github.com/wolftotem4/golava-core/session/sqlite/sqlitetest
+
+go 1.23.4
+
+require (
+	github.com/mattn/go-sqlite3 v1.14.24
+	github.com/wolftotem4/golava-core v0.0.0
+)
+
+require (
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/ugorji/go/codec v1.2.12 // indirect
+)
+
+replace github.com/wolftotem4/golava-core => ../../..
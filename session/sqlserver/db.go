@@ -13,6 +13,8 @@ import (
 type SQLServerSessionHandler struct {
 	DB    *sql.DB
 	Table string
+
+	conns session.LockedConns
 }
 
 func NewSQLServerSessionHandler(db *sql.DB, table string) *SQLServerSessionHandler {
@@ -23,7 +25,7 @@ func NewSQLServerSessionHandler(db *sql.DB, table string) *SQLServerSessionHandl
 }
 
 func (d *SQLServerSessionHandler) Read(ctx context.Context, sessionId string) ([]byte, error) {
-	row := d.DB.QueryRowContext(ctx, fmt.Sprintf(
+	row := d.conns.Queryer(d.DB, sessionId).QueryRowContext(ctx, fmt.Sprintf(
 		"SELECT payload FROM [%s] WHERE id = @p1", d.Table,
 	), sessionId)
 	if err := row.Err(); err != nil {
@@ -41,7 +43,7 @@ func (d *SQLServerSessionHandler) Read(ctx context.Context, sessionId string) ([
 
 func (d *SQLServerSessionHandler) Write(ctx context.Context, sessionId string, data session.SessionData) error {
 	now := time.Now().Unix()
-	_, err := d.DB.ExecContext(
+	_, err := d.conns.Queryer(d.DB, sessionId).ExecContext(
 		ctx,
 		fmt.Sprintf(`
 BEGIN tran
@@ -70,7 +72,7 @@ func (d *SQLServerSessionHandler) GC(ctx context.Context, lifetime time.Duration
 }
 
 func (d *SQLServerSessionHandler) Destroy(ctx context.Context, sessionId string) error {
-	_, err := d.DB.ExecContext(ctx, fmt.Sprintf(
+	_, err := d.conns.Queryer(d.DB, sessionId).ExecContext(ctx, fmt.Sprintf(
 		"DELETE FROM [%s] WHERE id = @p1", d.Table,
 	), sessionId)
 	return err
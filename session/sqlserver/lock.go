@@ -0,0 +1,59 @@
+package sqlserver
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/wolftotem4/golava-core/session"
+)
+
+// Lock waits up to timeout for an application lock (sp_getapplock) on the session.
+// The lock belongs to a connection, which is taken from the pool until the lock is released,
+// and Read, Write, Destroy and Touch of the session run on it meanwhile.
+func (d *SQLServerSessionHandler) Lock(ctx context.Context, sessionId string, timeout time.Duration) (func() error, error) {
+	conn, err := d.DB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := d.Table + ":" + sessionId
+
+	var result int
+	err = conn.QueryRowContext(ctx, `
+	DECLARE @result int;
+	EXEC @result = sp_getapplock @Resource = @p1, @LockMode = 'Exclusive', @LockOwner = 'Session', @LockTimeout = @p2;
+	SELECT @result;`,
+		resource, timeout.Milliseconds(),
+	).Scan(&result)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// 0 and 1 are granted, -1 is a timeout, the others are errors
+	if result == -1 {
+		conn.Close()
+		return nil, session.ErrLockTimeout
+	} else if result < 0 {
+		conn.Close()
+		return nil, fmt.Errorf("session: sp_getapplock failed with %d", result)
+	}
+
+	d.conns.Add(sessionId, conn)
+
+	return func() error {
+		d.conns.Remove(sessionId, conn)
+
+		_, err := conn.ExecContext(context.WithoutCancel(ctx),
+			"EXEC sp_releaseapplock @Resource = @p1, @LockOwner = 'Session'", resource,
+		)
+		if err != nil {
+			// a connection still holding the lock must not go back to the pool
+			conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+		return err
+	}, nil
+}
@@ -0,0 +1,41 @@
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/wolftotem4/golava-core/session"
+)
+
+func (d *SQLServerSessionHandler) ListByUser(ctx context.Context, userID any) ([]session.SessionInfo, error) {
+	rows, err := d.DB.QueryContext(ctx, fmt.Sprintf(
+		"SELECT id, user_id, ip_address, user_agent, last_activity FROM [%s] WHERE user_id = @p1 ORDER BY last_activity DESC", d.Table,
+	), userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return session.ScanSessionInfos(rows)
+}
+
+// DestroyByUser runs on the connection of exceptID when it is locked, which is the
+// session of the request calling it.
+func (d *SQLServerSessionHandler) DestroyByUser(ctx context.Context, userID any, exceptID string) (int64, error) {
+	result, err := d.conns.Queryer(d.DB, exceptID).ExecContext(ctx, fmt.Sprintf(
+		"DELETE FROM [%s] WHERE user_id = @p1 AND id <> @p2", d.Table,
+	), userID, exceptID)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (d *SQLServerSessionHandler) Touch(ctx context.Context, sessionId string) error {
+	_, err := d.conns.Queryer(d.DB, sessionId).ExecContext(ctx, fmt.Sprintf(
+		"UPDATE [%s] SET last_activity = @p1 WHERE id = @p2", d.Table,
+	), time.Now().Unix(), sessionId)
+	return err
+}
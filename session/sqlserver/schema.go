@@ -0,0 +1,175 @@
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wolftotem4/golava-core/session"
+)
+
+var columnSpecs = []session.ColumnSpec{
+	{Name: "id", Types: []string{"nvarchar", "varchar", "nchar", "char"}},
+	{Name: "user_id", Types: []string{"nvarchar", "varchar", "nchar", "char", "bigint", "int", "uniqueidentifier"}, Nullable: true},
+	{Name: "ip_address", Types: []string{"nvarchar", "varchar"}},
+	{Name: "user_agent", Types: []string{"nvarchar", "varchar"}},
+	{Name: "payload", Types: []string{"nvarchar", "varchar", "varbinary"}},
+	{Name: "last_activity", Types: []string{"int", "bigint"}},
+}
+
+// columnDefinitions are used to add the missing columns, the primary key aside.
+var columnDefinitions = map[string]string{
+	"user_id":       "NVARCHAR(255) NULL",
+	"ip_address":    "NVARCHAR(45) NULL",
+	"user_agent":    "NVARCHAR(MAX) NULL",
+	"payload":       "NVARCHAR(MAX) NOT NULL DEFAULT ''",
+	"last_activity": "BIGINT NOT NULL DEFAULT 0",
+}
+
+var indexedColumns = []string{"user_id", "last_activity"}
+
+// Schema returns the statements creating the session table and its indexes.
+func Schema(table string) []string {
+	return []string{
+		fmt.Sprintf(
+			"IF OBJECT_ID(N'[%[1]s]', N'U') IS NULL\n"+
+				"CREATE TABLE [%[1]s] (\n"+
+				"  id NVARCHAR(255) NOT NULL PRIMARY KEY,\n"+
+				"  user_id NVARCHAR(255) NULL,\n"+
+				"  ip_address NVARCHAR(45) NULL,\n"+
+				"  user_agent NVARCHAR(MAX) NULL,\n"+
+				"  payload NVARCHAR(MAX) NOT NULL,\n"+
+				"  last_activity BIGINT NOT NULL\n"+
+				")",
+			table,
+		),
+		createIndex(table, "user_id"),
+		createIndex(table, "last_activity"),
+	}
+}
+
+func createIndex(table string, column string) string {
+	return fmt.Sprintf(
+		"IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'%[1]s_%[2]s_index' AND object_id = OBJECT_ID(N'[%[1]s]'))\n"+
+			"CREATE INDEX [%[1]s_%[2]s_index] ON [%[1]s] (%[2]s)",
+		table, column,
+	)
+}
+
+func (d *SQLServerSessionHandler) Schema() []string {
+	return Schema(d.Table)
+}
+
+func (d *SQLServerSessionHandler) CreateTable(ctx context.Context) error {
+	for _, statement := range d.Schema() {
+		if _, err := d.DB.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *SQLServerSessionHandler) Migrate(ctx context.Context) error {
+	columns, err := d.describe(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(columns) == 0 {
+		if err := d.CreateTable(ctx); err != nil {
+			return err
+		}
+		return d.CheckSchema(ctx)
+	}
+
+	for _, spec := range columnSpecs {
+		definition, ok := columnDefinitions[spec.Name]
+		if _, exists := columns[spec.Name]; exists || !ok {
+			continue
+		}
+
+		_, err := d.DB.ExecContext(ctx, fmt.Sprintf("ALTER TABLE [%s] ADD %s %s", d.Table, spec.Name, definition))
+		if err != nil {
+			return err
+		}
+	}
+
+	indexed, err := d.indexedColumns(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, column := range indexedColumns {
+		if indexed[column] {
+			continue
+		}
+
+		_, err := d.DB.ExecContext(ctx, createIndex(d.Table, column))
+		if err != nil {
+			return err
+		}
+	}
+
+	return d.CheckSchema(ctx)
+}
+
+func (d *SQLServerSessionHandler) CheckSchema(ctx context.Context) error {
+	columns, err := d.describe(ctx)
+	if err != nil {
+		return err
+	}
+
+	indexed, err := d.indexedColumns(ctx)
+	if err != nil {
+		return err
+	}
+
+	return session.CompareSchema(d.Table, columnSpecs, columns, indexedColumns, indexed)
+}
+
+func (d *SQLServerSessionHandler) describe(ctx context.Context) (map[string]session.ColumnInfo, error) {
+	rows, err := d.DB.QueryContext(ctx,
+		"SELECT COLUMN_NAME, DATA_TYPE, IS_NULLABLE FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = SCHEMA_NAME() AND TABLE_NAME = @p1",
+		d.Table,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make(map[string]session.ColumnInfo)
+	for rows.Next() {
+		var name, dataType, nullable string
+		if err := rows.Scan(&name, &dataType, &nullable); err != nil {
+			return nil, err
+		}
+		columns[name] = session.ColumnInfo{Type: dataType, Nullable: nullable == "YES"}
+	}
+
+	return columns, rows.Err()
+}
+
+// indexedColumns returns the columns leading an index.
+func (d *SQLServerSessionHandler) indexedColumns(ctx context.Context) (map[string]bool, error) {
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT c.name FROM sys.indexes i
+		JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id AND ic.key_ordinal = 1
+		JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+		WHERE i.object_id = OBJECT_ID(@p1)`,
+		"["+d.Table+"]",
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	indexed := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		indexed[name] = true
+	}
+
+	return indexed, rows.Err()
+}
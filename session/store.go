@@ -2,7 +2,9 @@ package session
 
 import (
 	"context"
+	"time"
 
+	"github.com/wolftotem4/golava-core/encryption"
 	"github.com/wolftotem4/golava-core/util"
 )
 
@@ -10,6 +12,16 @@ type Store struct {
 	ID         string
 	Handler    SessionHandler
 	Attributes map[string]interface{}
+
+	// Serializer encodes the saved payloads, defaults to GobSerializer.
+	// Payloads are loaded with the serializer they were written with.
+	Serializer Serializer
+
+	// Encrypter encrypts the saved payloads when set.
+	// Unencrypted payloads are still loaded, so that it can be enabled on existing sessions.
+	Encrypter encryption.IEncrypter
+
+	unlock func() error
 }
 
 func NewStore(id string, handler SessionHandler) *Store {
@@ -33,6 +45,34 @@ func (s *Store) Start(ctx context.Context) error {
 	return nil
 }
 
+// Lock waits up to timeout for the lock of the session, when the handler is a LockingSessionHandler.
+// The lock is held until Unlock is called.
+func (s *Store) Lock(ctx context.Context, timeout time.Duration) error {
+	handler, ok := s.Handler.(LockingSessionHandler)
+	if !ok || s.unlock != nil {
+		return nil
+	}
+
+	unlock, err := handler.Lock(ctx, s.ID, timeout)
+	if err != nil {
+		return err
+	}
+
+	s.unlock = unlock
+	return nil
+}
+
+// Unlock releases the lock taken by Lock, if any.
+func (s *Store) Unlock() error {
+	if s.unlock == nil {
+		return nil
+	}
+
+	unlock := s.unlock
+	s.unlock = nil
+	return unlock()
+}
+
 func (s *Store) loadSession(ctx context.Context) error {
 	payload, err := s.Handler.Read(ctx, s.ID)
 	if err != nil {
@@ -43,7 +83,20 @@ func (s *Store) loadSession(ctx context.Context) error {
 		return nil
 	}
 
-	return unmarshal(payload, &s.Attributes)
+	payload, err = decryptPayload(s.Encrypter, payload)
+	if err != nil {
+		return err
+	}
+
+	attributes, err := decodePayload(s.Serializer, payload)
+	if err != nil {
+		return err
+	}
+
+	if attributes != nil {
+		s.Attributes = attributes
+	}
+	return nil
 }
 
 func (s *Store) Get(key string) (interface{}, bool) {
@@ -118,17 +171,31 @@ func (s *Store) Save(ctx context.Context, data ClientData) error {
 
 	s.compactForStorage()
 
-	payload, err := marshal(s.Attributes)
+	payload, err := encodePayload(s.serializer(), s.Attributes)
 	if err != nil {
 		return err
 	}
 
+	if s.Encrypter != nil {
+		payload, err = encryptPayload(s.Encrypter, payload)
+		if err != nil {
+			return err
+		}
+	}
+
 	return s.Handler.Write(ctx, s.ID, SessionData{
 		ClientData: data,
 		Payload:    payload,
 	})
 }
 
+func (s *Store) serializer() Serializer {
+	if s.Serializer == nil {
+		return GobSerializer{}
+	}
+	return s.Serializer
+}
+
 func (s *Store) FlashInput(value any) error {
 	data, err := inputToMap(value)
 	if err != nil {
@@ -0,0 +1,151 @@
+package session
+
+import (
+	"encoding/gob"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// The portable serializers (JSON, msgpack) only keep strings, booleans, float64, nil, []any and map[string]any as is.
+// Values of any other type are wrapped as {"$type": name, "$value": value}, and restored to their type on load.
+const (
+	typeKey  = "$type"
+	valueKey = "$value"
+)
+
+var types = struct {
+	sync.RWMutex
+	byName map[string]reflect.Type
+	names  map[reflect.Type]string
+}{
+	byName: make(map[string]reflect.Type),
+	names:  make(map[reflect.Type]string),
+}
+
+func init() {
+	for _, value := range []any{
+		int(0), int8(0), int16(0), int32(0), int64(0),
+		uint(0), uint8(0), uint16(0), uint32(0), uint64(0),
+		float32(0), []byte(nil), []string(nil), map[string]string(nil), time.Time{},
+	} {
+		registerType(reflect.TypeOf(value).String(), value)
+	}
+}
+
+// RegisterType records the type of the value, so that it round-trips through every serializer.
+// It is also registered with gob.
+func RegisterType(value any) {
+	rt := reflect.TypeOf(value)
+
+	name := rt.String()
+	if rt.Name() != "" && rt.PkgPath() != "" {
+		name = rt.PkgPath() + "." + rt.Name()
+	}
+
+	RegisterTypeName(name, value)
+}
+
+// RegisterTypeName is like RegisterType, but with the name stored in the payloads.
+func RegisterTypeName(name string, value any) {
+	gob.RegisterName(name, value)
+	registerType(name, value)
+}
+
+func registerType(name string, value any) {
+	rt := reflect.TypeOf(value)
+
+	types.Lock()
+	defer types.Unlock()
+
+	types.byName[name] = rt
+	types.names[rt] = name
+}
+
+// toPortable wraps the values of the types unknown to the portable formats.
+func toPortable(value any) (any, error) {
+	switch v := value.(type) {
+	case nil, string, bool, float64:
+		return v, nil
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for key, item := range v {
+			item, err := toPortable(item)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = item
+		}
+		return m, nil
+	case []any:
+		s := make([]any, len(v))
+		for i, item := range v {
+			item, err := toPortable(item)
+			if err != nil {
+				return nil, err
+			}
+			s[i] = item
+		}
+		return s, nil
+	}
+
+	types.RLock()
+	name, ok := types.names[reflect.TypeOf(value)]
+	types.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("session: type %T is not registered, see RegisterType", value)
+	}
+
+	return map[string]any{typeKey: name, valueKey: value}, nil
+}
+
+// fromPortable restores the wrapped values, converting them with the given function.
+func fromPortable(value any, convert func(value any, target any) error) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		if name, ok := v[typeKey].(string); ok && len(v) == 2 {
+			if raw, ok := v[valueKey]; ok {
+				return restoreType(name, raw, convert)
+			}
+		}
+
+		for key, item := range v {
+			item, err := fromPortable(item, convert)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = item
+		}
+		return v, nil
+	case []any:
+		for i, item := range v {
+			item, err := fromPortable(item, convert)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = item
+		}
+		return v, nil
+	}
+
+	return value, nil
+}
+
+func restoreType(name string, raw any, convert func(value any, target any) error) (any, error) {
+	types.RLock()
+	rt, ok := types.byName[name]
+	types.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("session: type %q is not registered, see RegisterType", name)
+	}
+
+	target := reflect.New(rt)
+	if err := convert(raw, target.Interface()); err != nil {
+		return nil, fmt.Errorf("session: cannot restore %s: %w", name, err)
+	}
+
+	return target.Elem().Interface(), nil
+}
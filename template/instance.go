@@ -88,10 +88,31 @@ func WithCsrf(c *gin.Context, data H) H {
 }
 
 func WithAuth(c *gin.Context, data H) H {
-	data["auth"] = instance.MustGetInstance(c).Auth
+	i := instance.MustGetInstance(c)
+	data["auth"] = i.Auth
+	data["can"] = Can(c, i)
 	return data
 }
 
+// Can returns a template helper reporting whether the current user is allowed an ability.
+//
+// Example:
+//
+//	{{ if call .can "update" .post }}<a href="...">Edit</a>{{ end }}
+func Can(c *gin.Context, i *instance.Instance) func(ability string, args ...any) bool {
+	gate := i.App.Base().Gate
+	user := i.Auth.User()
+
+	return func(ability string, args ...any) bool {
+		if gate == nil {
+			return false
+		}
+
+		allowed, err := gate.Allows(c, user, ability, args...)
+		return err == nil && allowed
+	}
+}
+
 func WithTranslator(c *gin.Context, data H) H {
 	i := instance.MustGetInstance(c)
 	fallback := i.App.Base().Translation.GetFallback()
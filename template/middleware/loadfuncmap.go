@@ -1,7 +1,9 @@
 package middleware
 
 import (
+	"errors"
 	"html/template"
+	"net/url"
 
 	"github.com/gin-gonic/gin"
 	"github.com/wolftotem4/golava-core/golava"
@@ -9,6 +11,35 @@ import (
 
 func LoadFuncMap(engine *gin.Engine, app golava.GolavaApp) {
 	engine.SetFuncMap(template.FuncMap{
-		"url": app.Base().Router.URL,
+		"url":   app.Base().Router.URL,
+		"route": routeFunc(app),
 	})
 }
+
+// routeFunc takes the parameters either as a map or as key/value pairs:
+//
+//	{{ route "users.show" "id" .User.ID }}
+func routeFunc(app golava.GolavaApp) func(name string, params ...any) (*url.URL, error) {
+	return func(name string, params ...any) (*url.URL, error) {
+		if len(params) == 1 {
+			if m, ok := params[0].(map[string]any); ok {
+				return app.Base().Router.Route(name, m)
+			}
+		}
+
+		if len(params)%2 != 0 {
+			return nil, errors.New("route parameters must be key/value pairs")
+		}
+
+		m := make(map[string]any, len(params)/2)
+		for i := 0; i < len(params); i += 2 {
+			key, ok := params[i].(string)
+			if !ok {
+				return nil, errors.New("route parameter names must be strings")
+			}
+			m[key] = params[i+1]
+		}
+
+		return app.Base().Router.Route(name, m)
+	}
+}
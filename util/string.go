@@ -5,8 +5,28 @@ import (
 	"encoding/base64"
 )
 
+const alphanumeric = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
 func RandomToken(n int) string {
 	b := make([]byte, n)
 	rand.Read(b)
 	return base64.StdEncoding.EncodeToString(b)
 }
+
+// RandomString returns a random alphanumeric string of length n, safe for URLs and headers.
+func RandomString(n int) string {
+	// discard bytes above the largest multiple of len(alphanumeric) to avoid modulo bias
+	const limit = 256 - 256%len(alphanumeric)
+
+	result := make([]byte, 0, n)
+	buf := make([]byte, n)
+	for len(result) < n {
+		rand.Read(buf)
+		for _, b := range buf {
+			if int(b) < limit && len(result) < n {
+				result = append(result, alphanumeric[int(b)%len(alphanumeric)])
+			}
+		}
+	}
+	return string(result)
+}